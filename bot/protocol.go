@@ -0,0 +1,97 @@
+/*
+Package bot lets external programs play snake against the real rules.
+
+The engine starts the bot as a child process and talks to it with JSON lines:
+one JSON object per line on the bot's stdin, one answer per line on its
+stdout. Anything the bot writes to stderr is passed through untouched.
+
+Engine → bot
+
+	{"type":"start","width":40,"height":20,"timeout_ms":200}
+	{"type":"state","tick":0,"width":40,"height":20,"snake":[{"x":5,"y":5},...],
+	 "direction":"right","food":{"x":12,"y":7,"kind":"normal","ttl_ms":10000},
+	 "score":0,"hunger":30}
+	{"type":"end","score":12,"ticks":840,"reason":"wall","timeouts":0}
+
+"snake" lists the body head first. "hunger" is the number of game seconds the
+snake can still go without eating. A "state" line is sent before every tick
+and the bot must answer each one.
+
+Bot → engine
+
+	{"direction":"up","tick":0}
+
+"tick" is optional; when present, answers to ticks that already timed out
+are recognised and dropped instead of being applied to a later tick.
+A bare direction ("up", "down", "left" or "right") on its own line is also
+accepted. Reversing onto the snake's own neck is ignored, like in the TUI.
+An answer that does not arrive within the move timeout counts as a timeout:
+the snake keeps its current direction and the game goes on until the bot
+exceeds the allowed number of timeouts.
+
+Every exchange is appended to a replay file (JSON lines, one "state" record
+per tick with the move that answered it, followed by the "end" record).
+*/
+package bot
+
+// Point is a cell on the field. (0,0) is the top-left corner.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// FoodInfo describes the single food item on the field.
+type FoodInfo struct {
+	Point
+	Kind  string `json:"kind"`
+	TTLMs int64  `json:"ttl_ms"`
+}
+
+// StartMsg opens a game.
+type StartMsg struct {
+	Type      string `json:"type"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	TimeoutMs int64  `json:"timeout_ms"`
+}
+
+// StateMsg is sent before every tick.
+type StateMsg struct {
+	Type      string   `json:"type"`
+	Tick      int      `json:"tick"`
+	Width     int      `json:"width"`
+	Height    int      `json:"height"`
+	Snake     []Point  `json:"snake"`
+	Direction string   `json:"direction"`
+	Food      FoodInfo `json:"food"`
+	Score     int      `json:"score"`
+	Hunger    int      `json:"hunger"`
+}
+
+// MoveMsg is the bot's answer to a StateMsg.
+type MoveMsg struct {
+	Direction string `json:"direction"`
+	Tick      *int   `json:"tick,omitempty"`
+}
+
+// EndMsg closes a game and is the last record of a replay.
+type EndMsg struct {
+	Type     string `json:"type"`
+	Score    int    `json:"score"`
+	Ticks    int    `json:"ticks"`
+	Reason   string `json:"reason"`
+	Timeouts int    `json:"timeouts"`
+}
+
+// ReplayTick is one line of a replay file.
+type ReplayTick struct {
+	State    StateMsg `json:"state"`
+	Move     string   `json:"move"`
+	TimedOut bool     `json:"timed_out,omitempty"`
+}
+
+const (
+	msgStart = "start"
+	msgState = "state"
+	msgEnd   = "end"
+)
@@ -0,0 +1,255 @@
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gamics/games/snake"
+)
+
+// Config describes one headless game against a bot process.
+type Config struct {
+	Command     string        // bot command line, split on spaces
+	Width       int           // field width in cells
+	Height      int           // field height in cells
+	MoveTimeout time.Duration // how long the bot may think per tick
+	MaxTimeouts int           // timeouts tolerated before the game ends
+	MaxTicks    int           // hard cap on the game length, 0 for none
+	Seed        uint64        // seed of the food and spawn generator
+	ReplayPath  string        // where the replay is written
+}
+
+// Result is the outcome of a finished game.
+type Result struct {
+	Score      int
+	Ticks      int
+	Timeouts   int
+	Reason     string
+	ReplayPath string
+}
+
+// Run plays one game of snake with the bot described by cfg.
+func Run(cfg Config) (Result, error) {
+	args := strings.Fields(cfg.Command)
+	if len(args) == 0 {
+		return Result{}, fmt.Errorf("bot command is empty")
+	}
+	if cfg.Width < 10 || cfg.Height < 5 {
+		return Result{}, fmt.Errorf("field must be at least 10x5, got %dx%d", cfg.Width, cfg.Height)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cfg.ReplayPath), 0755); err != nil {
+		return Result{}, fmt.Errorf("could not create replay directory: %w", err)
+	}
+	replayFile, err := os.Create(cfg.ReplayPath)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not create replay file: %w", err)
+	}
+	defer replayFile.Close()
+	replay := json.NewEncoder(replayFile)
+
+	proc := exec.Command(args[0], args[1:]...)
+	proc.Stderr = os.Stderr
+	// An os.Pipe rather than StdinPipe, for its write deadline: a bot that
+	// stops reading must not block the runner once the pipe fills up.
+	botIn, stdin, err := os.Pipe()
+	if err != nil {
+		return Result{}, fmt.Errorf("could not open bot stdin: %w", err)
+	}
+	proc.Stdin = botIn
+	stdout, err := proc.StdoutPipe()
+	if err != nil {
+		botIn.Close()
+		stdin.Close()
+		return Result{}, fmt.Errorf("could not open bot stdout: %w", err)
+	}
+	err = proc.Start()
+	botIn.Close() // the bot has its own copy now
+	if err != nil {
+		stdin.Close()
+		return Result{}, fmt.Errorf("could not start bot: %w", err)
+	}
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		stdin.Close()
+		if proc.Process != nil {
+			proc.Process.Kill()
+		}
+		proc.Wait()
+	}()
+
+	answers := readLines(stdout, done)
+	send := json.NewEncoder(stdin)
+	stdin.SetWriteDeadline(time.Now().Add(cfg.MoveTimeout))
+	if err := send.Encode(StartMsg{Type: msgStart, Width: cfg.Width, Height: cfg.Height, TimeoutMs: cfg.MoveTimeout.Milliseconds()}); err != nil {
+		return Result{}, fmt.Errorf("could not talk to bot: %w", err)
+	}
+
+	g := newGame(cfg)
+	res := Result{ReplayPath: cfg.ReplayPath}
+	for !g.over() {
+		if cfg.MaxTicks > 0 && g.st.Tick >= cfg.MaxTicks {
+			res.Reason = "max-ticks"
+			break
+		}
+
+		// Writing the state counts towards the bot's time to move.
+		deadline := time.Now().Add(cfg.MoveTimeout)
+		msg := g.stateMsg()
+		stdin.SetWriteDeadline(deadline)
+		if err := send.Encode(msg); err != nil {
+			res.Reason = "bot-exited"
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				// Part of the line may be written: the stream can't go on.
+				res.Timeouts++
+				res.Reason = "timeout"
+			}
+			break
+		}
+
+		move, timedOut, closed := awaitMove(answers, g.st.Tick, deadline)
+		if closed {
+			res.Reason = "bot-exited"
+			break
+		}
+		if timedOut {
+			res.Timeouts++
+		}
+		if err := replay.Encode(ReplayTick{State: msg, Move: move, TimedOut: timedOut}); err != nil {
+			return res, fmt.Errorf("could not write replay: %w", err)
+		}
+		if res.Timeouts > cfg.MaxTimeouts {
+			res.Reason = "timeout"
+			break
+		}
+
+		g.step(move)
+	}
+
+	if g.over() {
+		res.Reason = g.reason
+	}
+	res.Score, res.Ticks = g.st.Snakes[0].Score, g.st.Tick
+
+	end := EndMsg{Type: msgEnd, Score: res.Score, Ticks: res.Ticks, Reason: res.Reason, Timeouts: res.Timeouts}
+	stdin.SetWriteDeadline(time.Now().Add(cfg.MoveTimeout))
+	send.Encode(end) // the bot may already be gone
+	if err := replay.Encode(end); err != nil {
+		return res, fmt.Errorf("could not write replay: %w", err)
+	}
+	return res, nil
+}
+
+// game is one solo game on the engine. Each step lasts the tick interval
+// the TUI would have waited, so hunger, food expiry and power-ups keep their
+// usual pace in game time no matter how fast the bot answers.
+type game struct {
+	st     snake.State
+	reason string
+}
+
+func newGame(cfg Config) *game {
+	y := cfg.Height / 2
+	st := snake.New(snake.Config{
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Seed:   cfg.Seed,
+		Snakes: []snake.SnakeConfig{{Body: []snake.Point{{X: 5, Y: y}, {X: 4, Y: y}, {X: 3, Y: y}}, Heading: snake.Right}},
+	})
+	return &game{st: st}
+}
+
+func (g *game) over() bool { return g.reason != "" }
+
+// step turns the snake towards direction and advances the game one tick.
+func (g *game) step(direction string) {
+	var events []snake.Event
+	g.st, events = snake.Step(g.st, snake.Input{Turns: []string{direction}, Elapsed: g.st.TickInterval(0)})
+	g.died(events)
+}
+
+func (g *game) died(events []snake.Event) {
+	for _, e := range events {
+		if e.Kind == snake.EventDied && e.Snake == 0 {
+			g.reason = e.Reason
+		}
+	}
+}
+
+func (g *game) stateMsg() StateMsg {
+	sn := g.st.Snakes[0]
+	body := make([]Point, 0, len(sn.Body))
+	for _, c := range sn.Body {
+		body = append(body, Point{X: c.X, Y: c.Y})
+	}
+	food := g.st.Food
+	return StateMsg{
+		Type:      msgState,
+		Tick:      g.st.Tick,
+		Width:     g.st.Width,
+		Height:    g.st.Height,
+		Snake:     body,
+		Direction: sn.Heading,
+		Food:      FoodInfo{Point: Point{X: food.Position.X, Y: food.Position.Y}, Kind: snake.LookupFoodKind(food.Kind).Name, TTLMs: max(food.TTL, 0).Milliseconds()},
+		Score:     sn.Score,
+		Hunger:    snake.Seconds(sn.Hunger),
+	}
+}
+
+// readLines forwards every line the bot prints; the channel closes when the
+// bot's stdout does or once done is closed.
+func readLines(r io.Reader, done <-chan struct{}) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-done:
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// awaitMove waits until deadline for the answer to tick. Answers that echo
+// an older tick were late for their own deadline and are dropped; answers
+// without a tick are taken as they come.
+func awaitMove(lines <-chan string, tick int, deadline time.Time) (move string, timedOut, closed bool) {
+	timeout := time.After(time.Until(deadline))
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return "", false, true
+			}
+			m := parseMove(line)
+			if m.Tick != nil && *m.Tick < tick {
+				continue
+			}
+			return strings.ToLower(m.Direction), false, false
+		case <-timeout:
+			return "", true, false
+		}
+	}
+}
+
+func parseMove(line string) MoveMsg {
+	line = strings.TrimSpace(line)
+	var m MoveMsg
+	if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &m) == nil {
+		return m
+	}
+	return MoveMsg{Direction: line}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"fmt"
+	"gamics/daily"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	dailyGame string
+	dailyDate string
+)
+
+// dailyCmd prints the leaderboard of a daily challenge
+var dailyCmd = &cobra.Command{
+	Use:   "daily",
+	Short: "Show the daily challenge leaderboard",
+	Long: `Show the leaderboard of a daily challenge.
+
+Every day each game offers a challenge with the same seed for everybody.
+Each player gets one ranked attempt per day, started from the game menu.
+The logged in player's current streak is shown below the board.`,
+	Example:       `gamics daily --game snake --date 2025-06-01`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showDailyBoard()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dailyCmd)
+
+	dailyCmd.Flags().StringVar(&dailyGame, "game", "snake", "Game whose challenge to show")
+	dailyCmd.Flags().StringVar(&dailyDate, "date", "", "Day to show as YYYY-MM-DD (default today, UTC)")
+}
+
+func showDailyBoard() error {
+	day := daily.Today()
+	if dailyDate != "" {
+		var err error
+		if day, err = daily.Parse(dailyDate); err != nil {
+			return err
+		}
+	}
+
+	root := path.Join(INITIAL_PATH, ".gamics")
+	board, err := daily.Load(root, dailyGame, day)
+	if err != nil {
+		return fmt.Errorf("error loading daily board: %w", err)
+	}
+
+	fmt.Printf("Daily %s challenge, %s\n", board.Game, board.Date)
+	if len(board.Entries) == 0 {
+		fmt.Println("Nobody played this challenge yet.")
+	}
+	for i, e := range board.Entries {
+		status := fmt.Sprintf("%d ticks", e.Ticks)
+		if !e.Finished {
+			status = "in progress"
+		}
+		fmt.Printf("%3d. %-16s %6d  (%s, started %s)\n", i+1, e.Player, e.Score, status, e.Started.Local().Format(time.Kitchen))
+	}
+
+	user := appCfg.GetString("logged-user")
+	if user == "" {
+		return nil
+	}
+	profile := viper.New()
+	profile.SetConfigFile(path.Join(root, user, "profile.yaml"))
+	if err := profile.ReadInConfig(); err != nil {
+		return nil // no profile yet, so no streak either
+	}
+	streak := daily.CurrentStreak(profile.GetString("daily-last"), profile.GetInt("daily-streak"), daily.Today())
+	fmt.Printf("\n%s: streak %d day(s), best %d\n", user, streak, profile.GetInt("daily-best-streak"))
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"fmt"
+	"gamics/netplay"
+	"gamics/tui"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+var joinName string
+
+// joinCmd connects to a multiplayer server
+var joinCmd = &cobra.Command{
+	Use:   "join <addr>",
+	Short: "Join a multiplayer server on the local network",
+	Long: `Connect to a server started with "gamics serve".
+
+Pick or create a lobby, mark yourself ready and the game starts once every
+player in the lobby is ready. Press t to chat at any time.`,
+	Example:       `gamics join 192.168.1.20:7777 --name ana`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := joinName
+		if name == "" {
+			name = appCfg.GetString("logged-user")
+		}
+		if name == "" {
+			return fmt.Errorf("please log in or pass --name")
+		}
+
+		c, err := netplay.Dial(args[0], name, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+
+		_, err = tea.NewProgram(
+			tui.NewNetPlayModel(c),
+			tea.WithInputTTY(),
+			tea.WithAltScreen(),
+		).Run()
+		if err != nil {
+			return fmt.Errorf("error running the application: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(joinCmd)
+
+	joinCmd.Flags().StringVar(&joinName, "name", "", "Name shown to other players (default the logged in user)")
+}
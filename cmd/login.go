@@ -64,17 +64,23 @@ func checkAuthentication() error {
 		return fmt.Errorf("password is required")
 	}
 
+	return checkPassword(chall.name, chall.password)
+}
+
+// checkPassword verifies the password of a registered player.
+func checkPassword(name, password string) error {
 	gamicsPath := path.Join(INITIAL_PATH, ".gamics")
 	if _, err := os.Stat(gamicsPath); os.IsNotExist(err) {
 		return fmt.Errorf("gamics directory does not exist at %s", gamicsPath)
 	}
 
-	userConfigDir := path.Join(gamicsPath, chall.name)
-	playerCfg.SetConfigName(chall.name)
-	playerCfg.SetConfigType(EXTENSION_CONFIGS)
-	playerCfg.AddConfigPath(userConfigDir)
+	userConfigDir := path.Join(gamicsPath, name)
+	cfg := viper.New()
+	cfg.SetConfigName(name)
+	cfg.SetConfigType(EXTENSION_CONFIGS)
+	cfg.AddConfigPath(userConfigDir)
 
-	if err := playerCfg.ReadInConfig(); err != nil {
+	if err := cfg.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return fmt.Errorf("error reading user config file: %w", err)
 		}
@@ -82,7 +88,7 @@ func checkAuthentication() error {
 		return fmt.Errorf("username or password is incorrect")
 	}
 
-	if playerCfg.GetString("password") != chall.password {
+	if cfg.GetString("password") != password {
 		return fmt.Errorf("username or password is incorrect")
 	}
 
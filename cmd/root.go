@@ -5,9 +5,14 @@ package cmd
 
 import (
 	"fmt"
+	"gamics/games/checkers"
+	"gamics/games/connectfour"
+	"gamics/games/reversi"
+	"gamics/games/snake"
 	"gamics/tui"
 	"os"
 	"path"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
@@ -44,6 +49,7 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
+		applyGameConfig(appCfg)
 		_, err = tea.NewProgram(
 			tui.NewModel(tui.LIST_GAMES_UI),
 			tea.WithInputTTY(),
@@ -78,6 +84,10 @@ func loadGeneralConfig() error {
 	appCfg.SetConfigType(EXTENSION_CONFIGS)
 	appCfg.AddConfigPath(gamicsDir)
 	appCfg.SetDefault("logged-user", "")
+	appCfg.SetDefault("snake-input-buffer", snake.DefaultQueueSize)
+	appCfg.SetDefault("connect-four-depth", connectfour.DefaultDepth)
+	appCfg.SetDefault("reversi-think-ms", reversi.DefaultThinkTime.Milliseconds())
+	appCfg.SetDefault("checkers-depth", checkers.DefaultDepth)
 
 	if err := appCfg.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -93,6 +103,14 @@ func loadGeneralConfig() error {
 	return nil
 }
 
+// applyGameConfig hands the game settings of cfg to the TUI.
+func applyGameConfig(cfg *viper.Viper) {
+	tui.InputBufferSize = cfg.GetInt("snake-input-buffer")
+	tui.ConnectFourDepth = cfg.GetInt("connect-four-depth")
+	tui.ReversiThinkTime = time.Duration(cfg.GetInt("reversi-think-ms")) * time.Millisecond
+	tui.CheckersDepth = cfg.GetInt("checkers-depth")
+}
+
 func checkIfUserIsLoggedIn() error {
 	loggedUser := appCfg.GetString("logged-user")
 	if loggedUser == "" {
@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"fmt"
+	"gamics/netplay"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+// serveCmd runs a multiplayer server on the local network
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Host multiplayer games on the local network",
+	Long: `Run a server that other gamics players can join with "gamics join".
+
+Players meet in lobbies, chat, and play snake together in real time; the
+server owns the clock so everybody sees the same game. Turn-based games are
+relayed move by move. The protocol is plain TCP with one JSON object per
+line, documented in the gamics/netplay package.`,
+	Example:       `gamics serve --addr :7777`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger := log.New(os.Stdout, "", log.LstdFlags)
+		if err := netplay.ListenAndServe(serveAddr, logger); err != nil {
+			return fmt.Errorf("error running server: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveAddr, "addr", netplay.DefaultAddr, "Address to listen on")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"fmt"
+	"gamics/bot"
+	"path"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	botCfg bot.Config
+)
+
+// snakeCmd groups the snake tools that run outside the TUI
+var snakeCmd = &cobra.Command{
+	Use:   "snake",
+	Short: "Snake tools that run outside the game menu",
+	Long:  `Snake tools that run outside the game menu, such as playing headless games against bots.`,
+}
+
+// snakeBotCmd plays a headless game of snake driven by an external program
+var snakeBotCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Let a bot program play snake headless",
+	Long: `Run the snake rules headless and let an external program play them.
+
+The bot is started as a child process. Every tick it receives the board
+(snake body, food, score and hunger) as a JSON line on stdin and must answer
+with a direction on stdout. See the documentation of the gamics/bot package
+for the full protocol. A replay of the game is written as JSON lines and the
+final score is printed when the game ends.`,
+	Example:       `gamics snake bot --cmd ./mybot --width 40 --height 20 --timeout 200ms`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSnakeBot()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snakeCmd)
+	snakeCmd.AddCommand(snakeBotCmd)
+
+	snakeBotCmd.Flags().StringVar(&botCfg.Command, "cmd", "", "Command line that starts the bot")
+	snakeBotCmd.Flags().IntVar(&botCfg.Width, "width", 40, "Field width in cells")
+	snakeBotCmd.Flags().IntVar(&botCfg.Height, "height", 20, "Field height in cells")
+	snakeBotCmd.Flags().DurationVar(&botCfg.MoveTimeout, "timeout", 200*time.Millisecond, "Time the bot has to answer each tick")
+	snakeBotCmd.Flags().IntVar(&botCfg.MaxTimeouts, "max-timeouts", 10, "Timeouts tolerated before the bot forfeits")
+	snakeBotCmd.Flags().IntVar(&botCfg.MaxTicks, "max-ticks", 0, "Stop the game after this many ticks (0 for no limit)")
+	snakeBotCmd.Flags().Uint64Var(&botCfg.Seed, "seed", 0, "Seed for food and spawns, to replay the same game (default random)")
+	snakeBotCmd.Flags().StringVar(&botCfg.ReplayPath, "replay", "", "Replay file (default .gamics/replays/snake-bot-<time>.jsonl)")
+	snakeBotCmd.MarkFlagRequired("cmd")
+}
+
+func runSnakeBot() error {
+	if botCfg.ReplayPath == "" {
+		name := fmt.Sprintf("snake-bot-%s.jsonl", time.Now().Format("20060102-150405"))
+		botCfg.ReplayPath = path.Join(INITIAL_PATH, ".gamics", "replays", name)
+	}
+
+	if botCfg.Seed == 0 {
+		botCfg.Seed = uint64(time.Now().UnixNano())
+	}
+
+	res, err := bot.Run(botCfg)
+	if err != nil {
+		return fmt.Errorf("error running bot: %w", err)
+	}
+
+	fmt.Printf("Final score: %d\n", res.Score)
+	fmt.Printf("Ticks: %d, timeouts: %d, game over by: %s\n", res.Ticks, res.Timeouts, res.Reason)
+	fmt.Printf("Seed: %d\n", botCfg.Seed)
+	fmt.Printf("Replay written to %s\n", res.ReplayPath)
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"gamics/sshserve"
+	"gamics/tui"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	sshAddr    string
+	sshHostKey string
+)
+
+// sshServeCmd serves the game menu over SSH
+var sshServeCmd = &cobra.Command{
+	Use:   "ssh-serve",
+	Short: "Serve gamics to the team over SSH",
+	Long: `Serve the gamics menu over SSH, so that the whole team can play on one box.
+
+Every connection gets its own game menu, sized to the client's terminal.
+Players log in with the name and password they registered with, or with a
+public key listed in .gamics/<player>/authorized_keys (same format as
+OpenSSH). Scores, sessions and daily challenges are stored for the player
+who logged in.
+
+The host key is read from .gamics/ssh_host_ed25519_key and generated there
+on first start unless --host-key says otherwise.`,
+	Example:       `gamics ssh-serve --addr :2222`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSSHServer()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sshServeCmd)
+
+	sshServeCmd.Flags().StringVar(&sshAddr, "addr", ":2222", "Address to listen on")
+	sshServeCmd.Flags().StringVar(&sshHostKey, "host-key", "", "Host key file (default .gamics/ssh_host_ed25519_key)")
+}
+
+func runSSHServer() error {
+	if sshHostKey == "" {
+		sshHostKey = path.Join(INITIAL_PATH, ".gamics", "ssh_host_ed25519_key")
+	}
+
+	s, err := sshserve.NewServer(sshHostKey)
+	if err != nil {
+		return fmt.Errorf("error starting ssh server: %w", err)
+	}
+	s.Log = log.New(os.Stdout, "", log.LstdFlags)
+	s.Password = func(name, password string) bool {
+		return validPlayerName(name) && checkPassword(name, password) == nil
+	}
+	s.PublicKey = func(name string, key ssh.PublicKey) bool {
+		return validPlayerName(name) && authorizedKey(name, key)
+	}
+	s.NewModel = func(name string, r *lipgloss.Renderer) tea.Model {
+		return tui.NewPlayerModel(tui.LIST_GAMES_UI, name, r)
+	}
+	// Styles shared by every session render in full color, which each
+	// session's renderer brings down to what its terminal shows; the
+	// server's own terminal is never asked.
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
+
+	applyGameConfig(appCfg)
+	if err := s.ListenAndServe(sshAddr); err != nil {
+		return fmt.Errorf("error running ssh server: %w", err)
+	}
+	return nil
+}
+
+// validPlayerName keeps SSH user names from reaching outside .gamics.
+func validPlayerName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// authorizedKey reports whether key is listed in the player's
+// authorized_keys file.
+func authorizedKey(name string, key ssh.PublicKey) bool {
+	data, err := os.ReadFile(path.Join(INITIAL_PATH, ".gamics", name, "authorized_keys"))
+	if err != nil {
+		return false
+	}
+
+	want := key.Marshal()
+	for len(data) > 0 {
+		known, _, _, rest, err := ssh.ParseAuthorizedKey(data)
+		if err != nil {
+			return false
+		}
+		if bytes.Equal(known.Marshal(), want) {
+			return true
+		}
+		data = rest
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Gio
+*/
+package cmd
+
+import (
+	"fmt"
+	"gamics/spectate"
+	"gamics/tui"
+	"path"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd follows another player's game read-only
+var watchCmd = &cobra.Command{
+	Use:   "watch <player>",
+	Short: "Watch a player's game live",
+	Long: `Watch the snake game a player is running on this machine, live and read-only.
+
+A solo snake game broadcasts itself on .gamics/<player>/watch.sock while it
+is on screen, including games played over "gamics ssh-serve". The watcher
+sees the same field, stats bar and game-over screen as the player.`,
+	Example:       `gamics watch ana`,
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		player := args[0]
+		if !validPlayerName(player) {
+			return fmt.Errorf("invalid player name %q", player)
+		}
+
+		w, err := spectate.Dial(spectate.SocketPath(path.Join(INITIAL_PATH, ".gamics"), player))
+		if err != nil {
+			return fmt.Errorf("%s is not playing right now", player)
+		}
+		defer w.Close()
+
+		_, err = tea.NewProgram(
+			tui.NewWatchModel(player, w),
+			tea.WithInputTTY(),
+			tea.WithAltScreen(),
+		).Run()
+		if err != nil {
+			return fmt.Errorf("error running the application: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,229 @@
+/*
+Package daily runs the daily challenges: one game per day where every
+player gets the same seed, one ranked attempt and a place on that day's
+leaderboard.
+
+Days are UTC calendar days so players in different time zones share a
+challenge. Leaderboards live next to the player directories:
+
+	.gamics/daily/<game>/<YYYY-MM-DD>.yaml
+
+An entry is written as soon as an attempt starts and updated when it ends,
+so quitting half way still uses up the day's attempt. Updates hold a lock
+on the board, so players finishing at once don't drop each other's results.
+*/
+package daily
+
+import (
+	"errors"
+	"fmt"
+	"gamics/internal/lockfile"
+	"hash/fnv"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const dateLayout = "2006-01-02"
+
+// ErrAlreadyPlayed is returned by Begin when the player already used the
+// day's attempt.
+var ErrAlreadyPlayed = errors.New("today's challenge was already played")
+
+// Entry is one player's attempt on a daily board.
+type Entry struct {
+	Player   string    `yaml:"player"   mapstructure:"player"`
+	Score    int       `yaml:"score"    mapstructure:"score"`
+	Ticks    int       `yaml:"ticks"    mapstructure:"ticks"`
+	Finished bool      `yaml:"finished" mapstructure:"finished"`
+	Started  time.Time `yaml:"started"  mapstructure:"started"`
+}
+
+// Board is the leaderboard of one game on one day.
+type Board struct {
+	Game    string  `yaml:"game"    mapstructure:"game"`
+	Date    string  `yaml:"date"    mapstructure:"date"`
+	Entries []Entry `yaml:"entries" mapstructure:"entries"`
+}
+
+// Today returns the current challenge day.
+func Today() time.Time {
+	return Day(time.Now())
+}
+
+// Day truncates t to its UTC calendar day.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Key formats day the way boards and profiles store it.
+func Key(day time.Time) string {
+	return Day(day).Format(dateLayout)
+}
+
+// Parse reads a day written by Key.
+func Parse(key string) (time.Time, error) {
+	day, err := time.Parse(dateLayout, key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD: %w", key, err)
+	}
+	return day, nil
+}
+
+// Seed derives the shared seed of game's challenge on day.
+func Seed(game string, day time.Time) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte("gamics-daily/" + game + "/" + Key(day)))
+	return h.Sum64()
+}
+
+// NextStreak returns the streak after playing on day, given the last day
+// played (as written by Key, empty if never) and the streak so far.
+func NextStreak(last string, streak int, day time.Time) int {
+	prev, err := Parse(last)
+	switch {
+	case err != nil:
+		return 1
+	case prev.Equal(Day(day)):
+		return streak
+	case prev.AddDate(0, 0, 1).Equal(Day(day)):
+		return streak + 1
+	default:
+		return 1
+	}
+}
+
+// CurrentStreak is the streak still alive on day: it survives until the end
+// of the day after the last one played.
+func CurrentStreak(last string, streak int, day time.Time) int {
+	prev, err := Parse(last)
+	if err != nil || prev.AddDate(0, 0, 1).Before(Day(day)) {
+		return 0
+	}
+	return streak
+}
+
+// ----------------------------------------------------------------------------------
+// Leaderboards
+// ----------------------------------------------------------------------------------
+
+// Load reads the board of game on day below root (usually ".gamics"). A
+// day nobody played yields an empty board.
+func Load(root, game string, day time.Time) (Board, error) {
+	b := Board{Game: game, Date: Key(day)}
+	cfg := boardConfig(root, game, day)
+	if err := cfg.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return b, nil
+		}
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return b, nil
+		}
+		return b, fmt.Errorf("could not read daily board: %w", err)
+	}
+	if err := cfg.UnmarshalKey("entries", &b.Entries); err != nil {
+		return b, fmt.Errorf("could not parse daily board: %w", err)
+	}
+	b.sort()
+	return b, nil
+}
+
+// Begin records that player started the day's attempt. It fails with
+// ErrAlreadyPlayed when there already is an entry for the player.
+func Begin(root, game, player string, day time.Time) error {
+	return lockfile.Update(boardPath(root, game, day), func() error {
+		b, err := Load(root, game, day)
+		if err != nil {
+			return err
+		}
+		if _, ok := b.Find(player); ok {
+			return ErrAlreadyPlayed
+		}
+		b.Entries = append(b.Entries, Entry{Player: player, Started: time.Now().UTC()})
+		return save(root, b, day)
+	})
+}
+
+// Finish stores the result of player's attempt. Finishing twice keeps the
+// first result.
+func Finish(root, game, player string, day time.Time, score, ticks int) error {
+	return lockfile.Update(boardPath(root, game, day), func() error {
+		b, err := Load(root, game, day)
+		if err != nil {
+			return err
+		}
+		for i := range b.Entries {
+			e := &b.Entries[i]
+			if e.Player != player || e.Finished {
+				continue
+			}
+			e.Score, e.Ticks, e.Finished = score, ticks, true
+			return save(root, b, day)
+		}
+		return nil
+	})
+}
+
+// Find returns player's entry on the board.
+func (b Board) Find(player string) (Entry, bool) {
+	for _, e := range b.Entries {
+		if e.Player == player {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Rank is player's 1-based position on the board, 0 when absent.
+func (b Board) Rank(player string) int {
+	for i, e := range b.Entries {
+		if e.Player == player {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// sort orders the board by score, then by who got there in fewer ticks,
+// then by who started first.
+func (b *Board) sort() {
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		if a.Score != c.Score {
+			return a.Score > c.Score
+		}
+		if a.Ticks != c.Ticks {
+			return a.Ticks < c.Ticks
+		}
+		return a.Started.Before(c.Started)
+	})
+}
+
+// save writes b over its file. Callers hold the board's lock.
+func save(root string, b Board, day time.Time) error {
+	b.sort()
+	cfg := viper.New()
+	cfg.Set("game", b.Game)
+	cfg.Set("date", b.Date)
+	cfg.Set("entries", b.Entries)
+	return lockfile.Replace(boardPath(root, b.Game, day), func(tmp string) error {
+		if err := cfg.WriteConfigAs(tmp); err != nil {
+			return fmt.Errorf("could not write daily board: %w", err)
+		}
+		return nil
+	})
+}
+
+func boardPath(root, game string, day time.Time) string {
+	return path.Join(root, "daily", game, Key(day)+".yaml")
+}
+
+func boardConfig(root, game string, day time.Time) *viper.Viper {
+	cfg := viper.New()
+	cfg.SetConfigFile(boardPath(root, game, day))
+	return cfg
+}
@@ -0,0 +1,47 @@
+package daily
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBeginFinishConcurrent(t *testing.T) {
+	root := t.TempDir()
+	day := Day(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	const players = 20
+
+	var wg sync.WaitGroup
+	for i := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("p%02d", i)
+			if err := Begin(root, "snake", name, day); err != nil {
+				t.Errorf("Begin(%s): %v", name, err)
+				return
+			}
+			if err := Finish(root, "snake", name, day, i, 100); err != nil {
+				t.Errorf("Finish(%s): %v", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	b, err := Load(root, "snake", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Entries) != players {
+		t.Fatalf("board has %d entries, want %d", len(b.Entries), players)
+	}
+	for _, e := range b.Entries {
+		if !e.Finished {
+			t.Errorf("%s's result was lost", e.Player)
+		}
+	}
+	if err := Begin(root, "snake", "p00", day); err != ErrAlreadyPlayed {
+		t.Errorf("second Begin = %v, want ErrAlreadyPlayed", err)
+	}
+}
@@ -0,0 +1,83 @@
+package draw
+
+// HANGMAN is the gallows drawn a part per missed letter: the scaffold
+// first, then the figure. HANGMAN[n] is the drawing after n misses.
+var HANGMAN = [...]string{
+	`         
+         
+         
+         
+         
+         
+         `,
+	`         
+         
+         
+         
+         
+         
+=========`,
+	`         
+      |  
+      |  
+      |  
+      |  
+      |  
+=========`,
+	`  +---+  
+      |  
+      |  
+      |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+      |  
+      |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+      |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+  |   |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+ /|   |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+ /|\  |  
+      |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+ /|\  |  
+ /    |  
+      |  
+=========`,
+	`  +---+  
+  |   |  
+  O   |  
+ /|\  |  
+ / \  |  
+      |  
+=========`,
+}
@@ -0,0 +1,172 @@
+package battleship
+
+import "gamics/games/turn"
+
+// AI picks the square to fire at next. The sight passed in always has an
+// unknown square left.
+type AI interface {
+	Target(s Sight) Point
+}
+
+// NewAI maps a difficulty level to its strategy. Easy fires at random
+// until it hits, then around the hit; medium and hard fire by the
+// probability density of the ships afloat, medium drawing a square by its
+// density and hard taking the densest.
+func NewAI(level string, seed uint64) AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return Density{r: r}
+	case turn.AIMedium:
+		return Density{r: r, Draw: true}
+	default:
+		return HuntTarget{r: r}
+	}
+}
+
+// HuntTarget fires at random until it hits, then at the unknown squares
+// next to hits of ships afloat until they sink.
+type HuntTarget struct {
+	r *turn.Random
+}
+
+func (ai HuntTarget) Target(s Sight) Point {
+	var around, unknown []Point
+	for row := 0; row < s.Size; row++ {
+		for col := 0; col < s.Size; col++ {
+			p := Point{row, col}
+			if s.Marks[row][col] != Unknown {
+				continue
+			}
+			unknown = append(unknown, p)
+			for _, d := range []Point{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				if n := (Point{row + d.Row, col + d.Col}); s.in(n) && s.Marks[n.Row][n.Col] == Hit {
+					around = append(around, p)
+					break
+				}
+			}
+		}
+	}
+	if len(around) > 0 {
+		return around[ai.r.Intn(len(around))]
+	}
+	return unknown[ai.r.Intn(len(unknown))]
+}
+
+// Density counts, for every unknown square, the ways the ships afloat
+// could lie across it given the shots so far, and fires where a ship is
+// most likely to be. Ways through hits of ships afloat count many times
+// more, so once it hits it closes in on that ship.
+type Density struct {
+	// Draw fires at a square drawn at random by its density instead of
+	// the densest one.
+	Draw bool
+	r    *turn.Random
+}
+
+// hitWeight is how many times more a way to lie counts for each hit it
+// explains.
+const hitWeight = 32
+
+func (ai Density) Target(s Sight) Point {
+	grid := DensityMap(s)
+	var best []Point
+	bestN, total := 0, 0
+	for row := 0; row < s.Size; row++ {
+		for col := 0; col < s.Size; col++ {
+			n := grid[row][col]
+			total += n
+			switch {
+			case n > bestN:
+				best, bestN = []Point{{row, col}}, n
+			case n == bestN && n > 0:
+				best = append(best, Point{row, col})
+			}
+		}
+	}
+	if total == 0 {
+		// No way for the ships afloat to fit: the sight is not of a real
+		// board. Fire anywhere unknown.
+		return HuntTarget{r: ai.r}.Target(s)
+	}
+	if ai.Draw {
+		pick := ai.r.Intn(total)
+		for row := 0; row < s.Size; row++ {
+			for col := 0; col < s.Size; col++ {
+				if pick -= grid[row][col]; pick < 0 {
+					return Point{row, col}
+				}
+			}
+		}
+	}
+	return best[ai.r.Intn(len(best))]
+}
+
+// DensityMap weighs every unknown square by the ways the ships afloat
+// could cover it. While there are hits on ships afloat only the ways
+// through them count.
+func DensityMap(s Sight) [MaxSize][MaxSize]int {
+	var grid [MaxSize][MaxSize]int
+	targeting := false
+	for row := 0; row < s.Size; row++ {
+		for col := 0; col < s.Size; col++ {
+			targeting = targeting || s.Marks[row][col] == Hit
+		}
+	}
+
+	for _, ship := range s.Afloat {
+		for row := 0; row < s.Size; row++ {
+			for col := 0; col < s.Size; col++ {
+				for _, v := range []bool{false, true} {
+					if v && ship.Len == 1 {
+						continue // one square lies the same both ways
+					}
+					cells := Placement{Point{row, col}, v}.Cells(ship.Len)
+					hits, ok := s.fits(cells)
+					if !ok || (targeting && hits == 0) {
+						continue
+					}
+					weight := 1
+					for range hits {
+						weight *= hitWeight
+					}
+					for _, c := range cells {
+						if s.Marks[c.Row][c.Col] == Unknown {
+							grid[c.Row][c.Col] += weight
+						}
+					}
+				}
+			}
+		}
+	}
+	return grid
+}
+
+// fits reports whether a ship afloat could cover cells, and over how many
+// hits.
+func (s Sight) fits(cells []Point) (hits int, ok bool) {
+	for _, c := range cells {
+		if !s.in(c) {
+			return 0, false
+		}
+		switch s.Marks[c.Row][c.Col] {
+		case Miss, Sunk:
+			return 0, false
+		case Hit:
+			hits++
+		}
+		if !s.NoTouch {
+			continue
+		}
+		for _, n := range neighbours(c) {
+			if s.in(n) && s.Marks[n.Row][n.Col] == Sunk {
+				return 0, false // squares around a sunk ship are empty
+			}
+		}
+	}
+	return hits, true
+}
+
+func (s Sight) in(p Point) bool {
+	return p.Row >= 0 && p.Col >= 0 && p.Row < s.Size && p.Col < s.Size
+}
@@ -0,0 +1,307 @@
+/*
+Package battleship is the rules of Battleship: fleets, placing ships on a
+board, firing at it, and what the side firing gets to see.
+
+Rows are numbered from 1 and columns lettered from A, so the top left
+square is A1.
+*/
+package battleship
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// MaxSize is the side of the largest board.
+const MaxSize = 10
+
+// randomTries bounds how many times RandomFleet starts over when the ships
+// left have no room; it only happens when ships may not touch.
+const randomTries = 100
+
+// ErrFired is returned for a shot at a square fired at before.
+var ErrFired = errors.New("already fired there")
+
+// ----------------------------------------------------------------------------------
+// Fleets
+// ----------------------------------------------------------------------------------
+
+// Ship is a kind of ship of a fleet.
+type Ship struct {
+	Name string
+	Len  int
+}
+
+// Fleet is the ships each side places and the board they go on.
+type Fleet struct {
+	Name  string
+	Size  int // squares on a side
+	Ships []Ship
+	// NoTouch keeps ships apart: not even their corners may touch.
+	NoTouch bool
+}
+
+var (
+	// Classic is the five ships of the Milton Bradley game.
+	Classic = Fleet{Name: "Classic", Size: 10, Ships: []Ship{
+		{"Carrier", 5}, {"Battleship", 4}, {"Cruiser", 3}, {"Submarine", 3}, {"Destroyer", 2},
+	}}
+	// Russian is the ten ships of the pen-and-paper game, kept apart.
+	Russian = Fleet{Name: "Russian", Size: 10, NoTouch: true, Ships: []Ship{
+		{"Battleship", 4}, {"Cruiser", 3}, {"Cruiser", 3},
+		{"Destroyer", 2}, {"Destroyer", 2}, {"Destroyer", 2},
+		{"Submarine", 1}, {"Submarine", 1}, {"Submarine", 1}, {"Submarine", 1},
+	}}
+	// Quick is four ships on an 8×8 board, for a short game.
+	Quick = Fleet{Name: "Quick", Size: 8, Ships: []Ship{
+		{"Battleship", 4}, {"Cruiser", 3}, {"Destroyer", 2}, {"Destroyer", 2},
+	}}
+
+	// Fleets lists the fleets, the default first.
+	Fleets = []Fleet{Classic, Russian, Quick}
+)
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Point is a square of the board, counted from 0 at the top left.
+type Point struct{ Row, Col int }
+
+// String writes p the way players call shots, such as "B7".
+func (p Point) String() string { return fmt.Sprintf("%c%d", 'A'+p.Col, p.Row+1) }
+
+// Placement is where a ship lies: its top or left end and which way it
+// runs.
+type Placement struct {
+	Point
+	Vertical bool
+}
+
+// Cells lists the squares a ship of length n covers at p.
+func (p Placement) Cells(n int) []Point {
+	cells := make([]Point, n)
+	for i := range cells {
+		cells[i] = p.Point
+		if p.Vertical {
+			cells[i].Row += i
+		} else {
+			cells[i].Col += i
+		}
+	}
+	return cells
+}
+
+// Mark is what a shot at a square found.
+type Mark int8
+
+const (
+	Unknown Mark = iota // not fired at yet
+	Miss
+	Hit
+	Sunk // a hit on a ship that has since sunk
+)
+
+// Shot is the result of firing at a square.
+type Shot struct {
+	At   Point
+	Mark Mark
+	// Ship is the index in the fleet of the ship hit, or -1 for a miss.
+	Ship int
+}
+
+// Board is one side's ocean: its ships, placed in fleet order, and the
+// shots taken at it. It is a value: Place and Fire return a new board.
+type Board struct {
+	Fleet Fleet
+	Ships []Placement
+	Marks [MaxSize][MaxSize]Mark
+	Shots int
+}
+
+// Sight is what the side firing at a board knows of it: the marks of its
+// own shots and which ships are still afloat.
+type Sight struct {
+	Size    int
+	NoTouch bool
+	Marks   [MaxSize][MaxSize]Mark
+	Afloat  []Ship
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewBoard returns an empty ocean for fleet.
+func NewBoard(fleet Fleet) Board {
+	return Board{Fleet: fleet}
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// In reports whether p is on the board.
+func (b Board) In(p Point) bool {
+	return p.Row >= 0 && p.Col >= 0 && p.Row < b.Fleet.Size && p.Col < b.Fleet.Size
+}
+
+// Placed reports whether the whole fleet is on the board.
+func (b Board) Placed() bool { return len(b.Ships) == len(b.Fleet.Ships) }
+
+// Next is the ship to place next. The fleet must not be placed yet.
+func (b Board) Next() Ship { return b.Fleet.Ships[len(b.Ships)] }
+
+// ShipAt returns the index of the ship covering p, or -1.
+func (b Board) ShipAt(p Point) int {
+	for i, pl := range b.Ships {
+		if slices.Contains(pl.Cells(b.Fleet.Ships[i].Len), p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Check says why the next ship can't go at p, or nil if it can.
+func (b Board) Check(p Placement) error {
+	for _, c := range p.Cells(b.Next().Len) {
+		if !b.In(c) {
+			return errors.New("the ship must fit on the board")
+		}
+		if i := b.ShipAt(c); i >= 0 {
+			return fmt.Errorf("the ship would overlap the %s", b.Fleet.Ships[i].Name)
+		}
+		if !b.Fleet.NoTouch {
+			continue
+		}
+		for _, n := range neighbours(c) {
+			if i := b.ShipAt(n); i >= 0 {
+				return fmt.Errorf("the ship would touch the %s", b.Fleet.Ships[i].Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Place puts the next ship at p.
+func (b Board) Place(p Placement) (Board, error) {
+	if b.Placed() {
+		return b, errors.New("the whole fleet is placed")
+	}
+	if err := b.Check(p); err != nil {
+		return b, err
+	}
+	b.Ships = append(slices.Clip(b.Ships), p)
+	return b, nil
+}
+
+// Unplace takes back the last ship placed.
+func (b Board) Unplace() Board {
+	if len(b.Ships) > 0 {
+		b.Ships = slices.Clip(b.Ships[:len(b.Ships)-1])
+	}
+	return b
+}
+
+// RandomFleet places the ships left at random.
+func (b Board) RandomFleet(r *rand.Rand) Board {
+	start := b
+	for try := 0; ; try++ {
+		b = start
+		if try >= randomTries {
+			b.Ships = nil // what was placed by hand leaves no room
+		}
+		for !b.Placed() {
+			var fits []Placement
+			for row := 0; row < b.Fleet.Size; row++ {
+				for col := 0; col < b.Fleet.Size; col++ {
+					for _, v := range []bool{false, true} {
+						p := Placement{Point{row, col}, v}
+						if b.Check(p) == nil {
+							fits = append(fits, p)
+						}
+					}
+				}
+			}
+			if len(fits) == 0 {
+				break
+			}
+			b, _ = b.Place(fits[r.IntN(len(fits))])
+		}
+		if b.Placed() {
+			return b
+		}
+	}
+}
+
+// Sunk reports whether every square of ship i is hit.
+func (b Board) Sunk(i int) bool {
+	for _, c := range b.Ships[i].Cells(b.Fleet.Ships[i].Len) {
+		if b.Marks[c.Row][c.Col] == Unknown {
+			return false
+		}
+	}
+	return true
+}
+
+// AllSunk reports whether the fleet is at the bottom.
+func (b Board) AllSunk() bool {
+	for i := range b.Ships {
+		if !b.Sunk(i) {
+			return false
+		}
+	}
+	return b.Placed()
+}
+
+// Fire shoots at p. When the shot sinks a ship every square of it is
+// marked Sunk.
+func (b Board) Fire(p Point) (Board, Shot, error) {
+	if !b.In(p) {
+		return b, Shot{}, errors.New("off the board")
+	}
+	if b.Marks[p.Row][p.Col] != Unknown {
+		return b, Shot{}, ErrFired
+	}
+	b.Shots++
+	shot := Shot{At: p, Mark: Miss, Ship: b.ShipAt(p)}
+	if shot.Ship < 0 {
+		b.Marks[p.Row][p.Col] = Miss
+		return b, shot, nil
+	}
+	b.Marks[p.Row][p.Col] = Hit
+	shot.Mark = Hit
+	if b.Sunk(shot.Ship) {
+		shot.Mark = Sunk
+		for _, c := range b.Ships[shot.Ship].Cells(b.Fleet.Ships[shot.Ship].Len) {
+			b.Marks[c.Row][c.Col] = Sunk
+		}
+	}
+	return b, shot, nil
+}
+
+// Sight is the board as the side firing at it sees it.
+func (b Board) Sight() Sight {
+	s := Sight{Size: b.Fleet.Size, NoTouch: b.Fleet.NoTouch, Marks: b.Marks}
+	for i, ship := range b.Fleet.Ships {
+		if i >= len(b.Ships) || !b.Sunk(i) {
+			s.Afloat = append(s.Afloat, ship)
+		}
+	}
+	return s
+}
+
+// neighbours lists the eight squares around p, on the board or not.
+func neighbours(p Point) []Point {
+	var out []Point
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if dr != 0 || dc != 0 {
+				out = append(out, Point{p.Row + dr, p.Col + dc})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+package battleship
+
+import (
+	"gamics/games/turn"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestPlace(t *testing.T) {
+	b := NewBoard(Russian)
+	b, err := b.Place(Placement{Point{0, 0}, false}) // the battleship, A1 to D1
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		at   Placement
+		ok   bool
+	}{
+		{"off the edge", Placement{Point{0, 8}, false}, false},
+		{"across the battleship", Placement{Point{0, 2}, true}, false},
+		{"touching a corner", Placement{Point{1, 4}, false}, false},
+		{"a square apart", Placement{Point{2, 0}, false}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := b.Check(tt.at); (err == nil) != tt.ok {
+				t.Errorf("Check(%v) = %v, want ok %v", tt.at, err, tt.ok)
+			}
+		})
+	}
+
+	if b.Unplace().Placed() || len(b.Unplace().Ships) != 0 {
+		t.Error("Unplace left the battleship")
+	}
+}
+
+func TestRandomFleet(t *testing.T) {
+	for _, fleet := range Fleets {
+		for seed := range uint64(20) {
+			b := NewBoard(fleet).RandomFleet(rand.New(rand.NewPCG(seed, 0)))
+			if !b.Placed() {
+				t.Fatalf("%s seed %d: fleet not placed", fleet.Name, seed)
+			}
+			// Place again, in order, what RandomFleet laid: every ship must
+			// pass the rules.
+			check := NewBoard(fleet)
+			for i, p := range b.Ships {
+				var err error
+				if check, err = check.Place(p); err != nil {
+					t.Fatalf("%s seed %d: the %s at %v: %v", fleet.Name, seed, fleet.Ships[i].Name, p.Point, err)
+				}
+			}
+		}
+	}
+}
+
+func TestFire(t *testing.T) {
+	b := NewBoard(Quick)
+	for _, p := range []Placement{
+		{Point{0, 0}, false}, {Point{2, 0}, false}, {Point{4, 0}, false}, {Point{6, 0}, false},
+	} {
+		b, _ = b.Place(p)
+	}
+
+	b, shot, err := b.Fire(Point{7, 7})
+	if err != nil || shot.Mark != Miss || shot.Ship != -1 {
+		t.Fatalf("Fire(H8) = %+v, %v; want a miss", shot, err)
+	}
+	if _, _, err := b.Fire(Point{7, 7}); err != ErrFired {
+		t.Errorf("firing at H8 twice: %v", err)
+	}
+	if _, _, err := b.Fire(Point{8, 0}); err == nil {
+		t.Error("fired off the board")
+	}
+
+	b, shot, _ = b.Fire(Point{6, 0})
+	if shot.Mark != Hit || shot.Ship != 3 {
+		t.Fatalf("Fire(A7) = %+v, want a hit on the second destroyer", shot)
+	}
+	if got := len(b.Sight().Afloat); got != 4 {
+		t.Errorf("%d ships afloat after a hit, want 4", got)
+	}
+	b, shot, _ = b.Fire(Point{6, 1})
+	if shot.Mark != Sunk || b.Marks[6][0] != Sunk || b.Marks[6][1] != Sunk {
+		t.Fatalf("Fire(B7) = %+v, marks %v; want the destroyer sunk", shot, b.Marks[6][:2])
+	}
+	if got := len(b.Sight().Afloat); got != 3 {
+		t.Errorf("%d ships afloat after a sinking, want 3", got)
+	}
+	if b.AllSunk() {
+		t.Error("AllSunk with three ships afloat")
+	}
+	if b.Shots != 3 {
+		t.Errorf("Shots = %d, want 3", b.Shots)
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Density
+// ----------------------------------------------------------------------------------
+
+func TestDensityEmpty(t *testing.T) {
+	s := NewBoard(Classic).Sight()
+	grid := DensityMap(s)
+	n := s.Size - 1
+	for row := 0; row <= n; row++ {
+		for col := 0; col <= n; col++ {
+			// The empty board looks the same turned or mirrored.
+			want := grid[row][col]
+			for _, p := range []Point{{col, row}, {n - row, col}, {row, n - col}} {
+				if got := grid[p.Row][p.Col]; got != want {
+					t.Fatalf("density %v = %d but %v = %d", Point{row, col}, want, p, got)
+				}
+			}
+		}
+	}
+	if corner, middle := grid[0][0], grid[n/2][n/2]; corner >= middle {
+		t.Errorf("corner %d is as dense as the middle %d", corner, middle)
+	}
+	// A corner has one way across it each way for every ship.
+	if got := grid[0][0]; got != 2*len(Classic.Ships) {
+		t.Errorf("corner density %d, want %d", got, 2*len(Classic.Ships))
+	}
+}
+
+func TestDensityMiss(t *testing.T) {
+	s := NewBoard(Classic).Sight()
+	s.Marks[4][4] = Miss
+	grid := DensityMap(s)
+	if grid[4][4] != 0 {
+		t.Errorf("a miss has density %d", grid[4][4])
+	}
+	// No ship lies across E5 any more, so its neighbours lose ways.
+	if empty := DensityMap(NewBoard(Classic).Sight()); grid[4][5] >= empty[4][5] {
+		t.Errorf("F5 has density %d next to a miss, %d without", grid[4][5], empty[4][5])
+	}
+}
+
+func TestDensityTargets(t *testing.T) {
+	s := NewBoard(Classic).Sight()
+	s.Marks[3][3] = Hit
+	grid := DensityMap(s)
+	best, bestN := Point{}, 0
+	for row := 0; row < s.Size; row++ {
+		for col := 0; col < s.Size; col++ {
+			if n := grid[row][col]; n > 0 && row != 3 && col != 3 {
+				t.Errorf("%v has density %d off the hit's row and column", Point{row, col}, n)
+			}
+			if grid[row][col] > bestN {
+				best, bestN = Point{row, col}, grid[row][col]
+			}
+		}
+	}
+	if d := abs(best.Row-3) + abs(best.Col-3); d != 1 {
+		t.Errorf("densest square %v is not next to the hit on D4", best)
+	}
+
+	// A second hit in line settles which way the ship runs.
+	s.Marks[3][4] = Hit
+	grid = DensityMap(s)
+	if grid[3][2] == 0 || grid[3][5] == 0 {
+		t.Error("the ends of two hits in a row have no density")
+	}
+	if grid[2][3] >= grid[3][2] || grid[2][4] >= grid[3][5] {
+		t.Errorf("across the line (%d, %d) is as dense as along it (%d, %d)",
+			grid[2][3], grid[2][4], grid[3][2], grid[3][5])
+	}
+}
+
+func TestDensityNoTouch(t *testing.T) {
+	b := NewBoard(Russian)
+	b.Ships = []Placement{{Point{5, 5}, false}} // lay a submarine alone
+	b.Fleet.Ships = []Ship{{"Submarine", 1}}
+	b, _, _ = b.Fire(Point{5, 5})
+
+	s := b.Sight()
+	s.Afloat = Russian.Ships[:len(Russian.Ships)-1]
+	grid := DensityMap(s)
+	for _, n := range neighbours(Point{5, 5}) {
+		if grid[n.Row][n.Col] != 0 {
+			t.Errorf("%v next to a sunk ship has density %d", n, grid[n.Row][n.Col])
+		}
+	}
+	if grid[5][7] == 0 {
+		t.Error("F8, two squares off, has no density")
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ----------------------------------------------------------------------------------
+// AI
+// ----------------------------------------------------------------------------------
+
+// sink has ai fire at a random fleet until it is all sunk and returns the
+// shots it took.
+func sink(t *testing.T, ai AI, fleet Fleet, seed uint64) int {
+	t.Helper()
+	b := NewBoard(fleet).RandomFleet(rand.New(rand.NewPCG(seed, 1)))
+	for !b.AllSunk() {
+		p := ai.Target(b.Sight())
+		var err error
+		if b, _, err = b.Fire(p); err != nil {
+			t.Fatalf("%s seed %d: shot %d at %v: %v", fleet.Name, seed, b.Shots+1, p, err)
+		}
+	}
+	return b.Shots
+}
+
+func TestAISinksFleets(t *testing.T) {
+	for _, level := range []string{turn.AIEasy, turn.AIMedium, turn.AIHard} {
+		for _, fleet := range Fleets {
+			for seed := range uint64(5) {
+				if n := sink(t, NewAI(level, seed), fleet, seed); n > fleet.Size*fleet.Size {
+					t.Errorf("%s on %s seed %d: %d shots", level, fleet.Name, seed, n)
+				}
+			}
+		}
+	}
+}
+
+func TestHardBeatsEasy(t *testing.T) {
+	const games = 20
+	easy, hard := 0, 0
+	for seed := range uint64(games) {
+		easy += sink(t, NewAI(turn.AIEasy, seed), Classic, seed)
+		hard += sink(t, NewAI(turn.AIHard, seed), Classic, seed)
+	}
+	// Hunting by density sinks the classic fleet in about 45 shots on
+	// average, firing at random and then around hits in about 60.
+	if hard >= easy {
+		t.Errorf("hard took %d shots over %d games, easy %d", hard, games, easy)
+	}
+	if avg := hard / games; avg > 60 {
+		t.Errorf("hard took %d shots a game", avg)
+	}
+}
@@ -0,0 +1,117 @@
+package checkers
+
+import "gamics/games/turn"
+
+const (
+	winScore = 1_000_000
+
+	// DefaultDepth is how many plies the hardest CPU looks ahead on an 8×8
+	// board; the bigger board costs it one ply.
+	DefaultDepth = 7
+
+	manValue  = 100
+	kingValue = 250
+	// flyingKingValue is a king that crosses the board in one move.
+	flyingKingValue = 400
+)
+
+// NewAI maps a difficulty level to its strategy. Easy looks two plies ahead
+// and slips one move in three, medium looks four plies ahead and hard
+// depth plies.
+func NewAI(level string, depth int, seed uint64) turn.AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return AlphaBeta{Depth: max(depth, 1), r: r}
+	case turn.AIMedium:
+		return AlphaBeta{Depth: 4, r: r}
+	default:
+		return AlphaBeta{Depth: 2, r: r, mistakes: 3}
+	}
+}
+
+// AlphaBeta is a depth-limited negamax search with alpha-beta pruning.
+// Positions at the horizon are scored by material and how far the men
+// have come.
+type AlphaBeta struct {
+	Depth    int
+	r        *turn.Random
+	mistakes int // play at random one move in mistakes, 0 never
+}
+
+func (ai AlphaBeta) Choose(tg turn.Game) turn.Move {
+	g := tg.(Game)
+	if ai.mistakes > 0 && ai.r.Intn(ai.mistakes) == 0 {
+		return ai.r.Choose(g)
+	}
+	depth := ai.Depth
+	if g.Rules.Size > 8 {
+		depth = max(depth-1, 1)
+	}
+
+	steps := g.Steps()
+	var best []Step
+	bestScore := -2 * winScore
+	for _, s := range steps {
+		score := -negamax(g.apply(s), depth-1, -2*winScore, -(bestScore - 1))
+		switch {
+		case score > bestScore:
+			bestScore, best = score, []Step{s}
+		case score == bestScore:
+			best = append(best, s)
+		}
+	}
+	return best[ai.r.Intn(len(best))].Move()
+}
+
+// negamax scores g for the seat to move, within alpha and beta. Wins found
+// sooner score higher. Forced captures are searched past the horizon, so
+// a position is never scored half way through an exchange.
+func negamax(g Game, depth, alpha, beta int) int {
+	steps := g.Steps()
+	if len(steps) == 0 {
+		return -(winScore + depth) // the seat to move is stuck: it lost
+	}
+	if depth <= 0 && len(steps[0].Captured) == 0 {
+		return evaluate(g)
+	}
+	for _, s := range steps {
+		score := -negamax(g.apply(s), depth-1, -beta, -alpha)
+		if score >= beta {
+			return score
+		}
+		alpha = max(alpha, score)
+	}
+	return alpha
+}
+
+// evaluate scores a quiet position for the seat to move.
+func evaluate(g Game) int {
+	king := kingValue
+	if g.Rules.FlyingKings {
+		king = flyingKingValue
+	}
+	score := 0
+	for sq, p := range g.Board[:g.Rules.Squares()] {
+		if p == Empty {
+			continue
+		}
+		v := manValue
+		if p.IsKing() {
+			v = king
+		} else {
+			// Men gain a little for each row they advance.
+			row, _ := g.Rules.Point(sq)
+			if g.forward(p.Seat()) < 0 {
+				row = g.Rules.Size - 1 - row
+			}
+			v += 3 * row
+		}
+		if p.Seat() == g.Turn {
+			score += v
+		} else {
+			score -= v
+		}
+	}
+	return score
+}
@@ -0,0 +1,322 @@
+/*
+Package checkers is draughts under several rule sets, with PDN game records
+and an alpha-beta opponent.
+
+Pieces stand on the dark squares, numbered from 1 in reading order from
+the top left as PDN does. Captures are compulsory and go on as long as a
+piece can jump; a man reaching the far row is crowned. A side without a
+move loses. Repeating a position three times, or a long run of moves
+without a capture or a man moving, draws.
+
+A move is written as its squares: "11-15" for a step, "15x24x31" for a
+capture, with every square the piece lands on.
+*/
+package checkers
+
+import (
+	"fmt"
+	"gamics/games/turn"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxSquares = 50
+
+// Piece is what stands on a square: 0 when empty, seat+1 for a man, and
+// the same plus King for a king.
+type Piece int8
+
+const (
+	Empty Piece = 0
+	King  Piece = 4
+)
+
+// Seat is the seat the piece belongs to, -1 for an empty square.
+func (p Piece) Seat() int {
+	if p == Empty {
+		return -1
+	}
+	return int(p&^King) - 1
+}
+
+// IsKing reports whether the piece is crowned.
+func (p Piece) IsKing() bool { return p&King != 0 }
+
+// directions are the four diagonals, as row and column steps.
+var directions = [4][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Step is a move spelled out: the squares the piece lands on, the first
+// where it starts, and the squares of the pieces it captures.
+type Step struct {
+	Path     []int
+	Captured []int
+}
+
+// Move writes s in PDN notation.
+func (s Step) Move() turn.Move {
+	sep := "-"
+	if len(s.Captured) > 0 {
+		sep = "x"
+	}
+	parts := make([]string, len(s.Path))
+	for i, sq := range s.Path {
+		parts[i] = strconv.Itoa(sq + 1)
+	}
+	return turn.Move(strings.Join(parts, sep))
+}
+
+// Game is a draughts position. It implements turn.Game.
+type Game struct {
+	Rules Rules
+	Board [maxSquares]Piece
+	Turn  int
+	// Quiet counts the moves since the last capture or man move.
+	Quiet int
+	// Last is the path of the last move, nil before the first.
+	Last []int
+
+	seen []uint64 // keys of every position so far, this one last
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New sets up the men of both sides for rules.
+func New(rules Rules) Game {
+	g := Game{Rules: rules}
+	top := 0
+	if !rules.FirstFromTop {
+		top = 1
+	}
+	per := rules.Size / 2
+	for sq := 0; sq < rules.Rows*per; sq++ {
+		g.Board[sq] = Piece(top + 1)
+	}
+	for sq := rules.Squares() - rules.Rows*per; sq < rules.Squares(); sq++ {
+		g.Board[sq] = Piece(2 - top)
+	}
+	g.seen = []uint64{g.key()}
+	return g
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+func (g Game) Players() int { return 2 }
+func (g Game) ToMove() int  { return g.Turn }
+
+func (g Game) Legal() []turn.Move {
+	if g.Outcome().Over {
+		return nil
+	}
+	steps := g.Steps()
+	moves := make([]turn.Move, len(steps))
+	for i, s := range steps {
+		moves[i] = s.Move()
+	}
+	return moves
+}
+
+func (g Game) Play(m turn.Move) (turn.Game, error) {
+	for _, s := range g.Steps() {
+		if s.Move() == m {
+			next := g.apply(s)
+			next.seen = append(slices.Clip(g.seen), next.key())
+			return next, nil
+		}
+	}
+	return g, fmt.Errorf("%w: %s", turn.ErrIllegal, m)
+}
+
+func (g Game) Outcome() turn.Outcome {
+	if len(g.Steps()) == 0 {
+		reason := "no moves left"
+		if g.Count(g.Turn) == 0 {
+			reason = "all pieces taken"
+		}
+		return turn.Outcome{Over: true, Winner: 1 - g.Turn, Reason: reason}
+	}
+	if len(g.seen) > 0 {
+		now, times := g.seen[len(g.seen)-1], 0
+		for _, k := range g.seen {
+			if k == now {
+				times++
+			}
+		}
+		if times >= 3 {
+			return turn.Outcome{Over: true, Winner: turn.Draw, Reason: "the same position three times"}
+		}
+	}
+	if g.Quiet >= g.Rules.QuietLimit {
+		return turn.Outcome{Over: true, Winner: turn.Draw,
+			Reason: fmt.Sprintf("%d moves each without a capture or a man moving", g.Rules.QuietLimit/2)}
+	}
+	return turn.Outcome{}
+}
+
+// Count returns how many pieces seat has.
+func (g Game) Count(seat int) int {
+	n := 0
+	for _, p := range g.Board[:g.Rules.Squares()] {
+		if p.Seat() == seat {
+			n++
+		}
+	}
+	return n
+}
+
+// forward is the row step of seat's men.
+func (g Game) forward(seat int) int {
+	if (seat == 0) == g.Rules.FirstFromTop {
+		return 1
+	}
+	return -1
+}
+
+// crowns reports whether a man of seat landing on sq is crowned.
+func (g Game) crowns(seat, sq int) bool {
+	row, _ := g.Rules.Point(sq)
+	if g.forward(seat) > 0 {
+		return row == g.Rules.Size-1
+	}
+	return row == 0
+}
+
+// Steps lists the moves of the seat to move. When a capture is possible
+// only captures are, and under MaxCapture only the longest ones.
+func (g Game) Steps() []Step {
+	var caps []Step
+	for sq := 0; sq < g.Rules.Squares(); sq++ {
+		if g.Board[sq].Seat() == g.Turn {
+			caps = append(caps, g.captures(sq)...)
+		}
+	}
+	if len(caps) > 0 {
+		if g.Rules.MaxCapture {
+			most := 0
+			for _, s := range caps {
+				most = max(most, len(s.Captured))
+			}
+			caps = slices.DeleteFunc(caps, func(s Step) bool { return len(s.Captured) < most })
+		}
+		return caps
+	}
+
+	var steps []Step
+	for sq := 0; sq < g.Rules.Squares(); sq++ {
+		p := g.Board[sq]
+		if p.Seat() != g.Turn {
+			continue
+		}
+		row, col := g.Rules.Point(sq)
+		for _, d := range directions {
+			if !p.IsKing() && d[0] != g.forward(g.Turn) {
+				continue
+			}
+			for dist := 1; ; dist++ {
+				to := g.Rules.Square(row+dist*d[0], col+dist*d[1])
+				if to < 0 || g.Board[to] != Empty {
+					break
+				}
+				steps = append(steps, Step{Path: []int{sq, to}})
+				if !p.IsKing() || !g.Rules.FlyingKings {
+					break
+				}
+			}
+		}
+	}
+	return steps
+}
+
+// captures lists every complete capture of the piece on from. Captured
+// pieces stay on the board until the capture ends: they can't be jumped
+// twice and nothing lands on them.
+func (g Game) captures(from int) []Step {
+	p := g.Board[from]
+	board := g.Board
+	board[from] = Empty // the piece may pass its own start square
+	flying := p.IsKing() && g.Rules.FlyingKings
+
+	var out []Step
+	var walk func(at int, path, taken []int)
+	walk = func(at int, path, taken []int) {
+		found := false
+		row, col := g.Rules.Point(at)
+		for _, d := range directions {
+			if !p.IsKing() && !g.Rules.MenCaptureBack && d[0] != g.forward(p.Seat()) {
+				continue
+			}
+			r, c := row+d[0], col+d[1]
+			for flying && g.Rules.Square(r, c) >= 0 && board[g.Rules.Square(r, c)] == Empty {
+				r, c = r+d[0], c+d[1]
+			}
+			over := g.Rules.Square(r, c)
+			if over < 0 || board[over].Seat() != 1-p.Seat() || slices.Contains(taken, over) {
+				continue
+			}
+			for r, c = r+d[0], c+d[1]; ; r, c = r+d[0], c+d[1] {
+				land := g.Rules.Square(r, c)
+				if land < 0 || board[land] != Empty {
+					break
+				}
+				found = true
+				nextPath := append(slices.Clone(path), land)
+				nextTaken := append(slices.Clone(taken), over)
+				if !p.IsKing() && g.Rules.CrownEndsMove && g.crowns(p.Seat(), land) {
+					out = append(out, Step{Path: nextPath, Captured: nextTaken})
+				} else {
+					walk(land, nextPath, nextTaken)
+				}
+				if !flying {
+					break
+				}
+			}
+		}
+		if !found && len(taken) > 0 {
+			out = append(out, Step{Path: path, Captured: taken})
+		}
+	}
+	walk(from, []int{from}, nil)
+	return out
+}
+
+// apply plays s, which must be legal, leaving the position history alone.
+func (g Game) apply(s Step) Game {
+	from, to := s.Path[0], s.Path[len(s.Path)-1]
+	p := g.Board[from]
+	g.Board[from] = Empty
+	for _, sq := range s.Captured {
+		g.Board[sq] = Empty
+	}
+	g.Quiet++
+	if len(s.Captured) > 0 || !p.IsKing() {
+		g.Quiet = 0
+	}
+	if !p.IsKing() && g.crowns(p.Seat(), to) {
+		p |= King
+	}
+	g.Board[to] = p
+	g.Last = s.Path
+	g.Turn = 1 - g.Turn
+	return g
+}
+
+// key identifies the position for spotting repetitions.
+func (g Game) key() uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 0, maxSquares+1)
+	for _, p := range g.Board[:g.Rules.Squares()] {
+		buf = append(buf, byte(p))
+	}
+	buf = append(buf, byte(g.Turn))
+	h.Write(buf)
+	return h.Sum64()
+}
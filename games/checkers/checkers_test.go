@@ -0,0 +1,210 @@
+package checkers
+
+import (
+	"gamics/games/turn"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// perft counts the positions depth plies from g.
+func perft(g Game, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	n := 0
+	for _, s := range g.Steps() {
+		n += perft(g.apply(s), depth-1)
+	}
+	return n
+}
+
+func TestPerft(t *testing.T) {
+	tests := []struct {
+		rules Rules
+		want  []int // by depth, from 1
+	}{
+		{American, []int{7, 49, 302, 1469, 7361}},
+		{International, []int{9, 81, 658, 4265}},
+	}
+	for _, tt := range tests {
+		for i, want := range tt.want {
+			if got := perft(New(tt.rules), i+1); got != want {
+				t.Errorf("%s perft(%d) = %d, want %d", tt.rules.Name, i+1, got, want)
+			}
+		}
+	}
+}
+
+// setUp returns an empty board of rules with seat to move and pieces on
+// the given row, column pairs.
+func setUp(rules Rules, seat int, pieces map[[2]int]Piece) Game {
+	g := Game{Rules: rules, Turn: seat}
+	for rc, p := range pieces {
+		g.Board[rules.Square(rc[0], rc[1])] = p
+	}
+	g.seen = []uint64{g.key()}
+	return g
+}
+
+func moves(g Game) []string {
+	var out []string
+	for _, m := range g.Legal() {
+		out = append(out, string(m))
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestMenCaptureBack(t *testing.T) {
+	// The man of the first seat has a piece of the other just behind it.
+	tests := []struct {
+		rules Rules
+		man   [2]int
+		enemy [2]int
+		want  []string
+	}{
+		{American, [2]int{4, 3}, [2]int{3, 2}, []string{"18-22", "18-23"}},
+		{Brazilian, [2]int{2, 1}, [2]int{3, 2}, []string{"9x18"}},
+	}
+	for _, tt := range tests {
+		g := setUp(tt.rules, 0, map[[2]int]Piece{tt.man: 1, tt.enemy: 2})
+		if got := moves(g); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: moves %v, want %v", tt.rules.Name, got, tt.want)
+		}
+	}
+}
+
+func TestMaxCapture(t *testing.T) {
+	// A man of the second seat can take one piece to its left or two to
+	// its right.
+	pieces := map[[2]int]Piece{{7, 2}: 2, {6, 1}: 1, {6, 3}: 1, {4, 5}: 1}
+	tests := []struct {
+		rules Rules
+		want  int // captures on offer
+	}{
+		{American, 2},
+		{International, 1},
+	}
+	for _, tt := range tests {
+		g := setUp(tt.rules, 1, pieces)
+		steps := g.Steps()
+		if len(steps) != tt.want {
+			t.Errorf("%s: %d captures, want %d", tt.rules.Name, len(steps), tt.want)
+		}
+		for _, s := range steps {
+			if tt.rules.MaxCapture && len(s.Captured) != 2 {
+				t.Errorf("%s: %s is not the longest capture", tt.rules.Name, s.Move())
+			}
+		}
+	}
+}
+
+func TestFlyingKing(t *testing.T) {
+	// A king in the corner of an empty 10×10 board sees down the whole
+	// long diagonal.
+	g := setUp(International, 0, map[[2]int]Piece{{9, 0}: 1 | King, {0, 1}: 2})
+	if got := len(g.Steps()); got != 9 {
+		t.Errorf("flying king has %d moves, want 9", got)
+	}
+	g = setUp(American, 0, map[[2]int]Piece{{7, 0}: 1 | King, {0, 1}: 2})
+	if got := len(g.Steps()); got != 1 {
+		t.Errorf("short king has %d moves, want 1", got)
+	}
+}
+
+// playout plays random moves from the start of rules until the game ends
+// or plies moves were made.
+func playout(rules Rules, seed uint64, plies int) (Game, []turn.Move) {
+	r := turn.NewRandom(seed)
+	g := New(rules)
+	var played []turn.Move
+	for len(played) < plies && !g.Outcome().Over {
+		mv := r.Choose(g)
+		next, _ := g.Play(mv)
+		g = next.(Game)
+		played = append(played, mv)
+	}
+	return g, played
+}
+
+func TestPDNRoundTrip(t *testing.T) {
+	for _, rules := range Variants {
+		for seed := range uint64(5) {
+			g, played := playout(rules, seed, 200)
+			rec := Record{
+				Rules: rules, Players: [2]string{"ana", `bo "the wall"`},
+				Event: "Friday", Date: "2026.10.19", Moves: played, Result: ResultOf(g.Outcome()),
+			}
+			back, err := ParsePDN(rec.PDN())
+			if err != nil {
+				t.Fatalf("%s seed %d: %v\n%s", rules.Name, seed, err, rec.PDN())
+			}
+			if back.Rules.Name != rules.Name || back.Players != rec.Players || back.Event != rec.Event ||
+				back.Date != rec.Date || back.Result != rec.Result || !slices.Equal(back.Moves, played) {
+				t.Errorf("%s seed %d: read back %+v, want %+v", rules.Name, seed, back, rec)
+			}
+		}
+	}
+}
+
+func TestPDNAbbreviatedCaptures(t *testing.T) {
+	found := false
+	for seed := range uint64(50) {
+		_, played := playout(American, seed, 200)
+		short := make([]string, len(played))
+		long := false
+		for i, mv := range played {
+			squares := strings.Split(string(mv), "x")
+			short[i] = string(mv)
+			if len(squares) > 2 {
+				short[i] = squares[0] + "x" + squares[len(squares)-1]
+				long = true
+			}
+		}
+		if !long {
+			continue
+		}
+		rec, err := ParsePDN(strings.Join(short, " "))
+		if err != nil {
+			continue // two captures share their ends: the PDN is ambiguous
+		}
+		found = true
+		if !slices.Equal(rec.Moves, played) {
+			t.Errorf("seed %d: read %v, want %v", seed, rec.Moves, played)
+		}
+	}
+	if !found {
+		t.Fatal("no random game had a capture of several pieces")
+	}
+}
+
+func TestParsePDN(t *testing.T) {
+	rec, err := ParsePDN(`[Event "Club night"]
+[Black "ana"]
+[White "bo"]
+[GameType "21"]
+
+1.11-15 {the Old Faithful} 23-19 2. 8-11! (2. 9-14 22-17) 22-17 $1 *
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []turn.Move{"11-15", "23-19", "8-11", "22-17"}
+	if rec.Rules.Name != "American" || rec.Players != [2]string{"ana", "bo"} || rec.Event != "Club night" ||
+		rec.Result != ResultOngoing || !slices.Equal(rec.Moves, want) {
+		t.Errorf("read %+v", rec)
+	}
+
+	for _, bad := range []string{
+		"1. 11-18",
+		"1. 11-15 11-15",
+		"[GameType \"30\"]\n\n1. 11-15",
+		"[FEN \"W:W21:B1\"]\n\n1. 11-15",
+		"1. e2-e4",
+	} {
+		if _, err := ParsePDN(bad); err == nil {
+			t.Errorf("ParsePDN(%q) succeeded", bad)
+		}
+	}
+}
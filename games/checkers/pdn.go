@@ -0,0 +1,231 @@
+package checkers
+
+import (
+	"fmt"
+	"gamics/games/turn"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PDNExt is the file extension of PDN game records.
+const PDNExt = ".pdn"
+
+// PDN results, from the first mover's side.
+const (
+	ResultFirst   = "1-0"
+	ResultSecond  = "0-1"
+	ResultDraw    = "1/2-1/2"
+	ResultOngoing = "*"
+)
+
+var (
+	pdnTag  = regexp.MustCompile(`^\[(\w+)\s+"((?:[^"\\]|\\.)*)"\]$`)
+	pdnMove = regexp.MustCompile(`^\d+([-x]\d+)+$`)
+	// pdnTail is what annotated moves may end with: !, ?, and the like.
+	pdnTail = regexp.MustCompile(`[!?]+$`)
+)
+
+// Record is one game in PDN: the rule set, the players by seat and the
+// moves in full notation.
+type Record struct {
+	Rules   Rules
+	Players [2]string
+	Event   string
+	Date    string // YYYY.MM.DD, as PDN writes dates
+	Moves   []turn.Move
+	Result  string
+}
+
+// ResultOf is the PDN result of an outcome.
+func ResultOf(o turn.Outcome) string {
+	switch {
+	case !o.Over:
+		return ResultOngoing
+	case o.Winner == turn.Draw:
+		return ResultDraw
+	case o.Winner == 0:
+		return ResultFirst
+	}
+	return ResultSecond
+}
+
+// PDN writes the record as a PDN game. The player of the first seat goes
+// in the tag of that side's colour, Black in American checkers and White
+// in the international games.
+func (r Record) PDN() string {
+	var b strings.Builder
+	tag := func(k, v string) {
+		fmt.Fprintf(&b, "[%s %q]\n", k, v)
+	}
+	tag("Event", r.Event)
+	tag("Date", r.Date)
+	names := map[string]string{}
+	for seat, colour := range r.Rules.Seats {
+		names[colour] = r.Players[seat]
+	}
+	tag("Black", names["black"])
+	tag("White", names["white"])
+	tag("Result", r.Result)
+	tag("GameType", strconv.Itoa(r.Rules.GameType))
+	b.WriteString("\n")
+
+	line := 0
+	word := func(w string) {
+		if line > 0 && line+1+len(w) > 79 {
+			b.WriteString("\n")
+			line = 0
+		}
+		if line > 0 {
+			b.WriteString(" ")
+			line++
+		}
+		b.WriteString(w)
+		line += len(w)
+	}
+	for i, m := range r.Moves {
+		if i%2 == 0 {
+			word(fmt.Sprintf("%d.", i/2+1))
+		}
+		word(string(m))
+	}
+	word(r.Result)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// ParsePDN reads the first game of a PDN text. Its moves are checked
+// against the rules and written back in full, so a capture abbreviated to
+// its first and last square, as PDN allows, becomes every square it lands
+// on. Games from a set-up position (a FEN tag) are not supported.
+func ParsePDN(text string) (Record, error) {
+	rec := Record{Rules: American, Result: ResultOngoing}
+	tags := map[string]string{}
+	var movetext strings.Builder
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	inMoves := false
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := pdnTag.FindStringSubmatch(line); m != nil {
+			if inMoves {
+				break // the next game starts
+			}
+			tags[strings.ToLower(m[1])] = strings.ReplaceAll(m[2], `\"`, `"`)
+			continue
+		}
+		if line != "" {
+			inMoves = true
+			movetext.WriteString(line + "\n")
+		}
+	}
+
+	if gt, ok := tags["gametype"]; ok {
+		n, err := strconv.Atoi(strings.SplitN(gt, ",", 2)[0])
+		if err != nil {
+			return Record{}, fmt.Errorf("bad GameType %q", gt)
+		}
+		if rec.Rules, ok = VariantByGameType(n); !ok {
+			return Record{}, fmt.Errorf("GameType %d is not a supported rule set", n)
+		}
+	}
+	if _, ok := tags["fen"]; ok {
+		return Record{}, fmt.Errorf("games from a set-up position (FEN) are not supported")
+	}
+	for seat, colour := range rec.Rules.Seats {
+		rec.Players[seat] = tags[colour]
+	}
+	rec.Event, rec.Date = tags["event"], tags["date"]
+	if res, ok := tags["result"]; ok {
+		rec.Result = res
+	}
+
+	g := New(rec.Rules)
+	for _, tok := range pdnTokens(movetext.String()) {
+		switch {
+		case tok == ResultFirst || tok == ResultSecond || tok == ResultDraw || tok == ResultOngoing ||
+			tok == "2-0" || tok == "0-2" || tok == "1-1":
+			rec.Result = tok
+			continue
+		case strings.HasSuffix(tok, ".") || strings.HasPrefix(tok, "$"):
+			continue // a move number or an annotation glyph
+		}
+		tok = pdnTail.ReplaceAllString(tok, "")
+		if !pdnMove.MatchString(tok) {
+			return Record{}, fmt.Errorf("%q is not a move", tok)
+		}
+		s, err := g.resolve(tok)
+		if err != nil {
+			return Record{}, fmt.Errorf("move %d (%s): %w", len(rec.Moves)+1, tok, err)
+		}
+		next, _ := g.Play(s.Move())
+		g = next.(Game)
+		rec.Moves = append(rec.Moves, s.Move())
+	}
+	return rec, nil
+}
+
+// pdnTokens splits movetext into words, dropping {comments} and
+// (variations) and splitting move numbers glued to moves, as in "1.11-15".
+func pdnTokens(text string) []string {
+	var clean strings.Builder
+	depth, comment := 0, false
+	for _, r := range text {
+		switch {
+		case comment:
+			comment = r != '}'
+		case r == '{':
+			comment = true
+		case r == '(':
+			depth++
+		case r == ')':
+			depth = max(depth-1, 0)
+		case depth == 0:
+			clean.WriteRune(r)
+		}
+	}
+	var toks []string
+	for _, w := range strings.Fields(clean.String()) {
+		for {
+			num, rest, ok := strings.Cut(w, ".")
+			if !ok || rest == "" || strings.Trim(num, "0123456789") != "" || num == "" {
+				break
+			}
+			toks = append(toks, num+".")
+			w = strings.TrimLeft(rest, ".")
+		}
+		if w != "" {
+			toks = append(toks, w)
+		}
+	}
+	return toks
+}
+
+// resolve finds the legal step written as tok, in full or as its first
+// and last squares.
+func (g Game) resolve(tok string) (Step, error) {
+	if g.Outcome().Over {
+		return Step{}, turn.ErrOver
+	}
+	capture := strings.Contains(tok, "x")
+	squares := strings.FieldsFunc(tok, func(r rune) bool { return r == '-' || r == 'x' })
+	from, _ := strconv.Atoi(squares[0])
+	to, _ := strconv.Atoi(squares[len(squares)-1])
+
+	var found []Step
+	for _, s := range g.Steps() {
+		if string(s.Move()) == tok {
+			return s, nil
+		}
+		if s.Path[0] == from-1 && s.Path[len(s.Path)-1] == to-1 && (len(s.Captured) > 0) == capture && len(squares) == 2 {
+			found = append(found, s)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Step{}, turn.ErrIllegal
+	case 1:
+		return found[0], nil
+	}
+	return Step{}, fmt.Errorf("ambiguous: %d captures go from %d to %d", len(found), from, to)
+}
@@ -0,0 +1,87 @@
+package checkers
+
+// Rules is a rule set of draughts. Every rule set plays on the dark squares
+// with captures mandatory; they differ in board size, in what men and kings
+// may do, and in when a game is drawn.
+type Rules struct {
+	Name string
+	Size int // squares on a side, 8 or 10
+	// Rows is how many rows of men each side starts with.
+	Rows int
+	// FirstFromTop puts the first mover's men on the top rows, squares 1
+	// and up, as in American checkers.
+	FirstFromTop bool
+	// Seats names the sides, the first mover first.
+	Seats [2]string
+	// FlyingKings lets kings move and capture over any distance.
+	FlyingKings bool
+	// MenCaptureBack lets men capture backwards too.
+	MenCaptureBack bool
+	// MaxCapture forces the capture that takes the most pieces.
+	MaxCapture bool
+	// CrownEndsMove stops a capture when a man reaches the far row; without
+	// it a man passing the far row mid-capture goes on as a man.
+	CrownEndsMove bool
+	// QuietLimit is how many moves in a row, of both sides, without a
+	// capture or a man moving draw the game.
+	QuietLimit int
+	// GameType is the PDN number of the rule set.
+	GameType int
+}
+
+var (
+	// American is English draughts: short kings, men capture forward only
+	// and any capture may be chosen.
+	American = Rules{
+		Name: "American", Size: 8, Rows: 3, FirstFromTop: true, Seats: [2]string{"black", "white"},
+		CrownEndsMove: true, QuietLimit: 80, GameType: 21,
+	}
+	// International is the 10×10 game: flying kings, men capture both
+	// ways and the longest capture is compulsory.
+	International = Rules{
+		Name: "International", Size: 10, Rows: 4, Seats: [2]string{"white", "black"},
+		FlyingKings: true, MenCaptureBack: true, MaxCapture: true, QuietLimit: 50, GameType: 20,
+	}
+	// Brazilian is the international rules on an 8×8 board.
+	Brazilian = Rules{
+		Name: "Brazilian", Size: 8, Rows: 3, Seats: [2]string{"white", "black"},
+		FlyingKings: true, MenCaptureBack: true, MaxCapture: true, QuietLimit: 50, GameType: 26,
+	}
+
+	// Variants lists the rule sets, the default first.
+	Variants = []Rules{American, International, Brazilian}
+)
+
+// VariantByGameType finds the rule set with a PDN game type.
+func VariantByGameType(gt int) (Rules, bool) {
+	for _, r := range Variants {
+		if r.GameType == gt {
+			return r, true
+		}
+	}
+	return Rules{}, false
+}
+
+// Squares is how many dark squares the board has.
+func (r Rules) Squares() int { return r.Size * r.Size / 2 }
+
+// Point returns the row and column of dark square sq, counted from 0 in
+// reading order; row 0 is the top, where square 1 is.
+func (r Rules) Point(sq int) (row, col int) {
+	half := r.Size / 2
+	row = sq / half
+	col = 2 * (sq % half)
+	if row%2 == 0 {
+		col++
+	}
+	return row, col
+}
+
+// Square returns the dark square on row, col, or -1 for a light square or
+// one off the board.
+func (r Rules) Square(row, col int) int {
+	if row < 0 || col < 0 || row >= r.Size || col >= r.Size || (row+col)%2 == 0 {
+		return -1
+	}
+	return row*(r.Size/2) + col/2
+}
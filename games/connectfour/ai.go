@@ -0,0 +1,129 @@
+package connectfour
+
+import "gamics/games/turn"
+
+const winScore = 1_000_000
+
+// searchOrder tries the centre columns first: they are usually the best
+// moves, and good moves first make alpha-beta cut more.
+var searchOrder = []int{3, 2, 4, 1, 5, 0, 6}
+
+// NewAI maps a difficulty level to its strategy. Easy looks two plies ahead
+// and slips one move in two, medium looks four plies ahead and hard depth
+// plies.
+func NewAI(level string, depth int, seed uint64) turn.AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return AlphaBeta{Depth: max(depth, 1), r: r}
+	case turn.AIMedium:
+		return AlphaBeta{Depth: 4, r: r}
+	default:
+		return AlphaBeta{Depth: 2, r: r, mistakes: 2}
+	}
+}
+
+// AlphaBeta is a depth-limited negamax search with alpha-beta pruning.
+// Positions at the horizon are scored by counting the open windows of four
+// each side could still complete.
+type AlphaBeta struct {
+	Depth    int
+	r        *turn.Random
+	mistakes int // play at random one move in mistakes, 0 never
+}
+
+func (ai AlphaBeta) Choose(tg turn.Game) turn.Move {
+	g := tg.(Game)
+	if ai.mistakes > 0 && ai.r.Intn(ai.mistakes) == 0 {
+		return ai.r.Choose(g)
+	}
+
+	var best []int
+	bestScore := -2 * winScore
+	for _, c := range searchOrder {
+		if g.Cells[0][c] != 0 {
+			continue
+		}
+		// A window one below the best so far still tells moves that tie
+		// the best apart from worse ones.
+		score := -negamax(g.drop(c), ai.Depth-1, -2*winScore, -(bestScore - 1))
+		switch {
+		case score > bestScore:
+			bestScore, best = score, []int{c}
+		case score == bestScore:
+			best = append(best, c)
+		}
+	}
+	return ColumnMove(best[ai.r.Intn(len(best))])
+}
+
+// negamax scores g for the seat to move, within alpha and beta. Wins found
+// sooner score higher.
+func negamax(g Game, depth, alpha, beta int) int {
+	if g.lastWon() {
+		return -(winScore + depth) // the seat that just moved won
+	}
+	if g.Discs == Rows*Cols {
+		return 0
+	}
+	if depth == 0 {
+		return evaluate(g)
+	}
+
+	for _, c := range searchOrder {
+		if g.Cells[0][c] != 0 {
+			continue
+		}
+		score := -negamax(g.drop(c), depth-1, -beta, -alpha)
+		if score >= beta {
+			return score
+		}
+		alpha = max(alpha, score)
+	}
+	return alpha
+}
+
+// evaluate scores a quiet position for the seat to move: every window of
+// four cells that holds discs of one side only counts for that side, more
+// so the fuller it is, and discs in the centre column are worth a little.
+func evaluate(g Game) int {
+	me, them := g.Turn+1, 2-g.Turn
+	score := 0
+	for r := 0; r < Rows; r++ {
+		if g.Cells[r][Cols/2] == me {
+			score += 3
+		} else if g.Cells[r][Cols/2] == them {
+			score -= 3
+		}
+	}
+
+	for r := 0; r < Rows; r++ {
+		for c := 0; c < Cols; c++ {
+			for _, d := range directions {
+				end := Point{r + 3*d.Row, c + 3*d.Col}
+				if !inside(end) {
+					continue
+				}
+				mine, theirs := 0, 0
+				for i := 0; i < 4; i++ {
+					switch g.Cells[r+i*d.Row][c+i*d.Col] {
+					case me:
+						mine++
+					case them:
+						theirs++
+					}
+				}
+				switch {
+				case theirs == 0:
+					score += windowScore[mine]
+				case mine == 0:
+					score -= windowScore[theirs]
+				}
+			}
+		}
+	}
+	return score
+}
+
+// windowScore is what an open window with n discs of one side is worth.
+var windowScore = [4]int{0, 1, 5, 50}
@@ -0,0 +1,161 @@
+/*
+Package connectfour is the rules of Connect Four and its alpha-beta
+opponent.
+
+Discs are dropped into one of seven columns of a six-row board and fall to
+the lowest free cell; four in a row, in any direction, wins. Seat 0 moves
+first. A move is the column number, "1" to "7".
+*/
+package connectfour
+
+import (
+	"fmt"
+	"gamics/games/turn"
+	"strconv"
+)
+
+const (
+	Rows = 6
+	Cols = 7
+
+	// DefaultDepth is how many plies the hardest CPU looks ahead.
+	DefaultDepth = 7
+)
+
+// Point is a cell, row 0 being the top.
+type Point struct{ Row, Col int }
+
+// Game is a Connect Four position. It implements turn.Game.
+type Game struct {
+	// Cells holds seat+1 of the disc in each cell, 0 when empty.
+	Cells [Rows][Cols]int
+	Turn  int
+	Last  Point // cell of the last disc, Row -1 before the first
+	Discs int
+}
+
+// New returns the empty board.
+func New() Game { return Game{Last: Point{Row: -1}} }
+
+// ColumnMove is the move dropping a disc in col (0-based).
+func ColumnMove(col int) turn.Move { return turn.Move(strconv.Itoa(col + 1)) }
+
+// ParseMove reads a move back into a 0-based column.
+func ParseMove(m turn.Move) (int, error) {
+	c, err := strconv.Atoi(string(m))
+	if err != nil || c < 1 || c > Cols {
+		return 0, fmt.Errorf("%w: %q is not a column", turn.ErrIllegal, m)
+	}
+	return c - 1, nil
+}
+
+func (g Game) Players() int { return 2 }
+func (g Game) ToMove() int  { return g.Turn }
+
+func (g Game) Legal() []turn.Move {
+	if g.Outcome().Over {
+		return nil
+	}
+	moves := make([]turn.Move, 0, Cols)
+	for c := 0; c < Cols; c++ {
+		if g.Cells[0][c] == 0 {
+			moves = append(moves, ColumnMove(c))
+		}
+	}
+	return moves
+}
+
+func (g Game) Play(m turn.Move) (turn.Game, error) {
+	c, err := ParseMove(m)
+	if err != nil {
+		return g, err
+	}
+	if g.Cells[0][c] != 0 {
+		return g, fmt.Errorf("%w: column %s is full", turn.ErrIllegal, m)
+	}
+	return g.drop(c), nil
+}
+
+// drop lets a disc of the seat to move fall down column c, which must have
+// room.
+func (g Game) drop(c int) Game {
+	r := Rows - 1
+	for g.Cells[r][c] != 0 {
+		r--
+	}
+	g.Cells[r][c] = g.Turn + 1
+	g.Last = Point{r, c}
+	g.Turn = 1 - g.Turn
+	g.Discs++
+	return g
+}
+
+func (g Game) Outcome() turn.Outcome {
+	if line, ok := g.WinLine(); ok {
+		return turn.Outcome{Over: true, Winner: g.Cells[line[0].Row][line[0].Col] - 1, Reason: "four in a row"}
+	}
+	if g.Discs == Rows*Cols {
+		return turn.Outcome{Over: true, Winner: turn.Draw, Reason: "the board is full"}
+	}
+	return turn.Outcome{}
+}
+
+// directions to look for lines in: right, down, and both diagonals.
+var directions = []Point{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// WinLine returns the four cells of a completed line, if any.
+func (g Game) WinLine() ([4]Point, bool) {
+	for r := 0; r < Rows; r++ {
+		for c := 0; c < Cols; c++ {
+			v := g.Cells[r][c]
+			if v == 0 {
+				continue
+			}
+			for _, d := range directions {
+				var line [4]Point
+				i := 0
+				for ; i < 4; i++ {
+					p := Point{r + i*d.Row, c + i*d.Col}
+					if !inside(p) || g.Cells[p.Row][p.Col] != v {
+						break
+					}
+					line[i] = p
+				}
+				if i == 4 {
+					return line, true
+				}
+			}
+		}
+	}
+	return [4]Point{}, false
+}
+
+// lastWon reports whether the last disc completed a line. It is the cheap
+// check the search runs on every node.
+func (g Game) lastWon() bool {
+	if g.Last.Row < 0 {
+		return false
+	}
+	v := g.Cells[g.Last.Row][g.Last.Col]
+	for _, d := range directions {
+		n := 1 + g.run(g.Last, d, v) + g.run(g.Last, Point{-d.Row, -d.Col}, v)
+		if n >= 4 {
+			return true
+		}
+	}
+	return false
+}
+
+// run counts the discs of v next to p going in direction d.
+func (g Game) run(p, d Point, v int) int {
+	n := 0
+	for {
+		p = Point{p.Row + d.Row, p.Col + d.Col}
+		if !inside(p) || g.Cells[p.Row][p.Col] != v {
+			return n
+		}
+		n++
+	}
+}
+
+func inside(p Point) bool { return p.Row >= 0 && p.Row < Rows && p.Col >= 0 && p.Col < Cols }
@@ -0,0 +1,193 @@
+/*
+Package game2048 is the slide-and-merge engine of 2048, free of any UI.
+
+A game is a State value and Move returns the next one, so keeping old
+states is all an undo needs. The random generator lives inside State like
+in the snake engine: a saved game spawns the same tiles when continued, and
+a move taken back and played again spawns the same tile again.
+*/
+package game2048
+
+import (
+	"fmt"
+	"gamics/internal/rng"
+)
+
+const (
+	MinSize     = 3
+	MaxSize     = 8
+	DefaultSize = 4
+
+	// Goal is the tile that wins; play may go on after it.
+	Goal = 2048
+)
+
+// Directions.
+const (
+	Up    = "up"
+	Down  = "down"
+	Left  = "left"
+	Right = "right"
+)
+
+// Directions lists every direction in a fixed order.
+var Directions = []string{Up, Down, Left, Right}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// State is a 2048 board. Cells holds the tile values row by row, 0 for an
+// empty cell.
+type State struct {
+	Size  int    `yaml:"size"  mapstructure:"size"`
+	Cells []int  `yaml:"cells" mapstructure:"cells"`
+	Score int    `yaml:"score" mapstructure:"score"`
+	Moves int    `yaml:"moves" mapstructure:"moves"`
+	Rand  uint64 `yaml:"rand"  mapstructure:"rand"`
+}
+
+// Slide is what one move did before the new tile spawned.
+type Slide struct {
+	Moved  bool
+	Gained int
+	// Merged marks the cells, in the slid board, that hold a merged tile.
+	Merged []bool
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New returns a size×size board with its first two tiles.
+func New(size int, seed uint64) (State, error) {
+	if size < MinSize || size > MaxSize {
+		return State{}, fmt.Errorf("board size must be %d to %d, got %d", MinSize, MaxSize, size)
+	}
+	s := State{Size: size, Cells: make([]int, size*size), Rand: seed}
+	s.spawn()
+	s.spawn()
+	return s, nil
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Tile returns the value at column x, row y.
+func (s State) Tile(x, y int) int { return s.Cells[y*s.Size+x] }
+
+// Move slides the board toward dir, merging equal neighbours once per move,
+// and spawns a tile if anything moved. A move that changes nothing returns
+// s as it is.
+func (s State) Move(dir string) (State, Slide) {
+	next, slide := s.Slide(dir)
+	if !slide.Moved {
+		return s, slide
+	}
+	next.spawn()
+	return next, slide
+}
+
+// Slide is Move without the spawn, as a preview of what dir would do.
+func (s State) Slide(dir string) (State, Slide) {
+	next := s
+	next.Cells = make([]int, len(s.Cells))
+	slide := Slide{Merged: make([]bool, len(s.Cells))}
+
+	for line := 0; line < s.Size; line++ {
+		idx := s.line(dir, line)
+		out := 0 // next free slot along the line
+		lastMerged := false
+		for _, i := range idx {
+			v := s.Cells[i]
+			if v == 0 {
+				continue
+			}
+			if out > 0 && !lastMerged && next.Cells[idx[out-1]] == v {
+				next.Cells[idx[out-1]] = 2 * v
+				slide.Merged[idx[out-1]] = true
+				slide.Gained += 2 * v
+				lastMerged = true
+				continue
+			}
+			next.Cells[idx[out]] = v
+			out++
+			lastMerged = false
+		}
+	}
+
+	for i := range s.Cells {
+		if s.Cells[i] != next.Cells[i] {
+			slide.Moved = true
+			break
+		}
+	}
+	if slide.Moved {
+		next.Score += slide.Gained
+		next.Moves++
+	}
+	return next, slide
+}
+
+// line lists the cells of one row or column in the order tiles travel
+// through them, the cell they slide toward first.
+func (s State) line(dir string, n int) []int {
+	idx := make([]int, s.Size)
+	for k := range idx {
+		switch dir {
+		case Left:
+			idx[k] = n*s.Size + k
+		case Right:
+			idx[k] = n*s.Size + s.Size - 1 - k
+		case Up:
+			idx[k] = k*s.Size + n
+		default: // Down
+			idx[k] = (s.Size-1-k)*s.Size + n
+		}
+	}
+	return idx
+}
+
+// CanMove reports whether any direction changes the board.
+func (s State) CanMove() bool {
+	for i, v := range s.Cells {
+		if v == 0 {
+			return true
+		}
+		x, y := i%s.Size, i/s.Size
+		if x+1 < s.Size && s.Cells[i+1] == v || y+1 < s.Size && s.Cells[i+s.Size] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// BestTile is the highest tile on the board.
+func (s State) BestTile() int {
+	best := 0
+	for _, v := range s.Cells {
+		best = max(best, v)
+	}
+	return best
+}
+
+// spawn puts a 2, or one time in ten a 4, on a random empty cell.
+func (s *State) spawn() {
+	var empty []int
+	for i, v := range s.Cells {
+		if v == 0 {
+			empty = append(empty, i)
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+	i := empty[s.intn(len(empty))]
+	s.Cells[i] = 2
+	if s.intn(10) == 0 {
+		s.Cells[i] = 4
+	}
+}
+
+func (s *State) intn(n int) int { return rng.Intn(&s.Rand, n) }
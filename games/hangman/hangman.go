@@ -0,0 +1,167 @@
+/*
+Package hangman is the letter guessing game, free of any UI: the rules of
+a round and the word packs rounds are dealt from.
+
+Letters are matched without case or accents, so guessing a also finds á
+and ã in a Portuguese word. Anything in a word that is not a letter, such
+as the space in "polar bear", is shown from the start.
+*/
+package hangman
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLives is the most misses a round allows, one per part of the gallows.
+const MaxLives = 10
+
+// Difficulty is how hard the words of a pack are; harder packs also give
+// fewer lives.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+)
+
+var difficultyNames = []string{"easy", "medium", "hard"}
+
+func (d Difficulty) String() string { return difficultyNames[d] }
+
+// Lives is how many misses a round of d allows.
+func (d Difficulty) Lives() int { return MaxLives - 2*int(d) }
+
+// ParseDifficulty reads a difficulty by name.
+func ParseDifficulty(s string) (Difficulty, error) {
+	for i, name := range difficultyNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return Difficulty(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty %q, want easy, medium or hard", s)
+}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Game is one round. It is a plain value so a round can be saved and
+// continued.
+type Game struct {
+	Word     string `yaml:"word"     mapstructure:"word"`
+	Category string `yaml:"category" mapstructure:"category"`
+	Lives    int    `yaml:"lives"    mapstructure:"lives"`
+	// Guessed is every letter tried so far, folded, in order.
+	Guessed string `yaml:"guessed" mapstructure:"guessed"`
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New starts a round on word, allowing lives misses.
+func New(word, category string, lives int) Game {
+	return Game{Word: strings.TrimSpace(word), Category: category, Lives: min(max(lives, 1), MaxLives)}
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Fold is the letter r is matched as: lower case without accents.
+func Fold(r rune) rune {
+	if d := []rune(norm.NFD.String(string(r))); len(d) > 0 {
+		r = d[0]
+	}
+	return unicode.ToLower(r)
+}
+
+// CheckWord tells whether w can be played: at least two letters and
+// nothing but letters, spaces, hyphens and apostrophes.
+func CheckWord(w string) error {
+	letters := 0
+	for _, r := range w {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case r == ' ' || r == '-' || r == '\'':
+		default:
+			return fmt.Errorf("%q can't be in a word", r)
+		}
+	}
+	if letters < 2 {
+		return errors.New("a word needs at least two letters")
+	}
+	return nil
+}
+
+func (g Game) tried(r rune) bool { return strings.ContainsRune(g.Guessed, Fold(r)) }
+
+func (g Game) inWord(r rune) bool {
+	for _, c := range g.Word {
+		if Fold(c) == Fold(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Guess tries letter r. It fails, leaving the round as it is, on anything
+// but a letter not tried yet.
+func (g Game) Guess(r rune) (Game, error) {
+	switch {
+	case g.Over():
+		return g, errors.New("the round is over")
+	case !unicode.IsLetter(r):
+		return g, fmt.Errorf("%q is not a letter", r)
+	case g.tried(r):
+		return g, fmt.Errorf("%c was already tried", unicode.ToUpper(Fold(r)))
+	}
+	g.Guessed += string(Fold(r))
+	return g, nil
+}
+
+// Found reports whether letter r was tried and is in the word.
+func (g Game) Found(r rune) bool { return g.tried(r) && g.inWord(r) }
+
+// Missed returns the letters tried that are not in the word, in order.
+func (g Game) Missed() []rune {
+	var missed []rune
+	for _, r := range g.Guessed {
+		if !g.inWord(r) {
+			missed = append(missed, r)
+		}
+	}
+	return missed
+}
+
+// Misses counts the letters tried that are not in the word.
+func (g Game) Misses() int { return len(g.Missed()) }
+
+// Masked is the word as the guesser sees it, each letter not found yet
+// an underscore.
+func (g Game) Masked() string {
+	var b strings.Builder
+	for _, r := range g.Word {
+		if unicode.IsLetter(r) && !g.tried(r) {
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Won reports whether every letter was found.
+func (g Game) Won() bool { return !strings.ContainsRune(g.Masked(), '_') }
+
+// Lost reports whether the misses used up the lives.
+func (g Game) Lost() bool { return g.Misses() >= g.Lives }
+
+// Over reports whether the round is won or lost.
+func (g Game) Over() bool { return g.Won() || g.Lost() }
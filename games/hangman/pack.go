@@ -0,0 +1,152 @@
+package hangman
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// PackExt is the file extension of word packs.
+const PackExt = ".txt"
+
+//go:embed packs/*.txt
+var builtin embed.FS
+
+// Pack is a file of words on one category.
+type Pack struct {
+	Name string
+	// File is the file name without its extension.
+	File       string
+	Language   string // language code, such as en or pt
+	Difficulty Difficulty
+	Words      []string
+	// Builtin is set on the packs shipped with gamics.
+	Builtin bool
+}
+
+// ----------------------------------------------------------------------------------
+// Word packs
+// ----------------------------------------------------------------------------------
+
+// ParsePack reads a word pack: a header of Key: value lines, then one word
+// or phrase per line. Blank lines and lines starting with # are skipped.
+//
+//	Name: Animals
+//	Language: en
+//	Difficulty: easy
+//
+//	elephant
+//	polar bear
+//
+// The header is optional: a pack is named name, in English and of medium
+// difficulty unless it says otherwise.
+func ParsePack(name string, r io.Reader) (Pack, error) {
+	pack := Pack{Name: name, File: name, Language: "en", Difficulty: Medium}
+	lineNo := 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			if err := CheckWord(line); err != nil {
+				return Pack{}, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			pack.Words = append(pack.Words, line)
+			continue
+		}
+		if len(pack.Words) > 0 {
+			return Pack{}, fmt.Errorf("line %d: %q comes after the words; the header goes first", lineNo, line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			pack.Name = value
+		case "language":
+			pack.Language = strings.ToLower(value)
+		case "difficulty":
+			d, err := ParseDifficulty(value)
+			if err != nil {
+				return Pack{}, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			pack.Difficulty = d
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Pack{}, fmt.Errorf("could not read pack %s: %w", name, err)
+	}
+	if len(pack.Words) == 0 {
+		return Pack{}, fmt.Errorf("pack %s has no words", name)
+	}
+	return pack, nil
+}
+
+// Builtin returns the packs shipped with gamics.
+func Builtin() []Pack {
+	entries, err := builtin.ReadDir("packs")
+	if err != nil {
+		panic(err)
+	}
+	var packs []Pack
+	for _, e := range entries {
+		f, err := builtin.Open("packs/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		p, err := ParsePack(strings.TrimSuffix(e.Name(), PackExt), f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("built-in hangman pack %s: %v", e.Name(), err))
+		}
+		p.Builtin = true
+		packs = append(packs, p)
+	}
+	return packs
+}
+
+// LoadDir reads every word pack in dir, sorted by file name. A missing dir
+// has no packs. A pack that can't be read is left out and reported in the
+// error along with the others; the rest still load.
+func LoadDir(dir string) ([]Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list hangman packs: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var (
+		packs []Pack
+		errs  []error
+	)
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(path.Ext(e.Name()), PackExt) {
+			continue
+		}
+		f, err := os.Open(path.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not open hangman pack: %w", err))
+			continue
+		}
+		p, err := ParsePack(strings.TrimSuffix(e.Name(), path.Ext(e.Name())), f)
+		f.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hangman pack %s: %w", e.Name(), err))
+			continue
+		}
+		packs = append(packs, p)
+	}
+	return packs, errors.Join(errs...)
+}
@@ -0,0 +1,307 @@
+/*
+Package minesweeper is the rules of Minesweeper and its board generator.
+
+Mines are only placed on the first dig, away from the dug cell, so the
+first dig never loses. With NoGuess set the generator keeps drawing boards
+until its solver clears one from that first dig by deduction alone, so the
+player never has to guess.
+*/
+package minesweeper
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// maxAttempts bounds how many boards the no-guess generator draws before it
+// settles for the last one.
+const maxAttempts = 500
+
+// Preset is the size and mine count of a board.
+type Preset struct {
+	Name          string
+	Width, Height int
+	Mines         int
+}
+
+var (
+	Beginner     = Preset{Name: "beginner", Width: 9, Height: 9, Mines: 10}
+	Intermediate = Preset{Name: "intermediate", Width: 16, Height: 16, Mines: 40}
+	Expert       = Preset{Name: "expert", Width: 30, Height: 16, Mines: 99}
+	Tiny         = Preset{Name: "tiny", Width: 5, Height: 5, Mines: 4}
+)
+
+// Limits of a custom board.
+const (
+	MinSide   = 5
+	MaxWidth  = 30
+	MaxHeight = 24
+)
+
+// Presets are the fixed boards, smallest first.
+func Presets() []Preset { return []Preset{Tiny, Beginner, Intermediate, Expert} }
+
+// Custom returns the preset of a custom board, or why it cannot be played.
+func Custom(width, height, mines int) (Preset, error) {
+	p := Preset{Name: "custom", Width: width, Height: height, Mines: mines}
+	return p, p.Validate()
+}
+
+// Validate checks the board fits the limits and leaves room for a safe
+// first dig.
+func (p Preset) Validate() error {
+	if p.Width < MinSide || p.Width > MaxWidth || p.Height < MinSide || p.Height > MaxHeight {
+		return fmt.Errorf("board must be %d to %d wide and %d to %d high", MinSide, MaxWidth, MinSide, MaxHeight)
+	}
+	if p.Mines < 1 || p.Mines > p.Width*p.Height-1 {
+		return fmt.Errorf("a %dx%d board takes 1 to %d mines", p.Width, p.Height, p.Width*p.Height-1)
+	}
+	return nil
+}
+
+// State is how a game stands.
+type State int
+
+const (
+	Playing State = iota
+	Won
+	Lost
+)
+
+// Cell is what a board holds at one cell.
+type Cell struct {
+	Mine, Open, Flag bool
+	// Adjacent counts the mines around the cell.
+	Adjacent int
+}
+
+// Board is one game of Minesweeper. Its slices are shared between copies,
+// so a copy plays the same game.
+type Board struct {
+	Preset
+	NoGuess bool
+	// Guaranteed is set once the no-guess generator placed the mines of a
+	// board its solver can clear; it stays false if it gave up.
+	Guaranteed bool
+
+	cells    []Cell
+	placed   bool
+	state    State
+	opened   int
+	flags    int
+	exploded int
+	rng      *rand.Rand
+}
+
+// New returns a board with no mines placed yet. The same seed and first dig
+// give the same board.
+func New(p Preset, noGuess bool, seed uint64) Board {
+	return Board{
+		Preset:   p,
+		NoGuess:  noGuess,
+		cells:    make([]Cell, p.Width*p.Height),
+		exploded: -1,
+		rng:      rand.New(rand.NewPCG(seed, seed^0x9E3779B97F4A7C15)),
+	}
+}
+
+func (b Board) State() State { return b.state }
+
+// Started reports whether the first dig was made.
+func (b Board) Started() bool { return b.placed }
+
+// MinesLeft is the mine count less the flags planted; it goes negative
+// when too many flags are down.
+func (b Board) MinesLeft() int { return b.Mines - b.flags }
+
+// Cell returns the cell at x, y.
+func (b Board) Cell(x, y int) Cell { return b.cells[y*b.Width+x] }
+
+// Exploded reports the mine that was dug, once the game is lost.
+func (b Board) Exploded() (x, y int, ok bool) {
+	if b.exploded < 0 {
+		return 0, 0, false
+	}
+	return b.exploded % b.Width, b.exploded / b.Width, true
+}
+
+// Prepared returns a copy of b with the mines laid for a first dig at x, y,
+// leaving b as it is. No-guess boards can take a moment to draw on crowded
+// boards, so callers may want to prepare them in the background.
+func (b Board) Prepared(x, y int) Board {
+	if b.placed || !b.inside(x, y) {
+		return b
+	}
+	b.cells = slices.Clone(b.cells)
+	b.place(y*b.Width + x)
+	return b
+}
+
+// Open digs the cell at x, y, placing the mines first on the first dig.
+// Digging a blank cell opens the area around it. Flagged and open cells
+// are left alone.
+func (b *Board) Open(x, y int) {
+	if b.state != Playing || !b.inside(x, y) {
+		return
+	}
+	i := y*b.Width + x
+	if b.cells[i].Open || b.cells[i].Flag {
+		return
+	}
+	if !b.placed {
+		b.place(i)
+	}
+	if b.cells[i].Mine {
+		b.cells[i].Open = true
+		b.exploded = i
+		b.state = Lost
+		return
+	}
+	b.flood(i)
+	b.checkWon()
+}
+
+// Chord digs every unflagged neighbour of an open number once as many
+// flags as the number surround it.
+func (b *Board) Chord(x, y int) {
+	if b.state != Playing || !b.inside(x, y) {
+		return
+	}
+	i := y*b.Width + x
+	c := b.cells[i]
+	if !c.Open || c.Adjacent == 0 {
+		return
+	}
+	flags := 0
+	b.neighbours(i, func(j int) {
+		if b.cells[j].Flag {
+			flags++
+		}
+	})
+	if flags != c.Adjacent {
+		return
+	}
+	b.neighbours(i, func(j int) { b.Open(j%b.Width, j/b.Width) })
+}
+
+// ToggleFlag plants or lifts a flag on a closed cell.
+func (b *Board) ToggleFlag(x, y int) {
+	if b.state != Playing || !b.inside(x, y) {
+		return
+	}
+	c := &b.cells[y*b.Width+x]
+	if c.Open {
+		return
+	}
+	c.Flag = !c.Flag
+	if c.Flag {
+		b.flags++
+	} else {
+		b.flags--
+	}
+}
+
+// flood opens i and, while the cells opened are blank, their neighbours.
+func (b *Board) flood(i int) {
+	stack := []int{i}
+	for len(stack) > 0 {
+		i, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		c := &b.cells[i]
+		if c.Open || c.Flag || c.Mine {
+			continue
+		}
+		c.Open = true
+		b.opened++
+		if c.Adjacent == 0 {
+			b.neighbours(i, func(j int) { stack = append(stack, j) })
+		}
+	}
+}
+
+// checkWon ends the game once every safe cell is open, flagging the mines.
+func (b *Board) checkWon() {
+	if b.opened < len(b.cells)-b.Mines {
+		return
+	}
+	b.state = Won
+	for i := range b.cells {
+		if b.cells[i].Mine && !b.cells[i].Flag {
+			b.cells[i].Flag = true
+			b.flags++
+		}
+	}
+}
+
+// place lays the mines for a first dig at start. The dug cell and, when
+// there is room, its neighbours stay clear. A no-guess board is redrawn
+// until the solver clears it from start.
+func (b *Board) place(start int) {
+	b.placed = true
+	for attempt := 1; ; attempt++ {
+		mines := b.drawMines(start)
+		if !b.NoGuess || attempt == maxAttempts {
+			b.lay(mines)
+			return
+		}
+		if solvable(b.Width, b.Height, mines, start) {
+			b.Guaranteed = true
+			b.lay(mines)
+			return
+		}
+	}
+}
+
+func (b *Board) drawMines(start int) []bool {
+	safe := map[int]bool{start: true}
+	b.neighbours(start, func(j int) { safe[j] = true })
+	if len(b.cells)-len(safe) < b.Mines {
+		safe = map[int]bool{start: true} // too crowded to clear the neighbours
+	}
+	free := make([]int, 0, len(b.cells))
+	for i := range b.cells {
+		if !safe[i] {
+			free = append(free, i)
+		}
+	}
+	mines := make([]bool, len(b.cells))
+	for n := 0; n < b.Mines && len(free) > 0; n++ {
+		k := b.rng.IntN(len(free))
+		mines[free[k]] = true
+		free[k] = free[len(free)-1]
+		free = free[:len(free)-1]
+	}
+	return mines
+}
+
+func (b *Board) lay(mines []bool) {
+	for i := range b.cells {
+		b.cells[i].Mine = mines[i]
+	}
+	for i := range b.cells {
+		n := 0
+		b.neighbours(i, func(j int) {
+			if mines[j] {
+				n++
+			}
+		})
+		b.cells[i].Adjacent = n
+	}
+}
+
+func (b Board) inside(x, y int) bool { return x >= 0 && x < b.Width && y >= 0 && y < b.Height }
+
+func (b Board) neighbours(i int, f func(j int)) { neighbours(b.Width, b.Height, i, f) }
+
+// neighbours calls f with the index of each cell around i.
+func neighbours(w, h, i int, f func(j int)) {
+	x, y := i%w, i/w
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := x+dx, y+dy
+			if (dx != 0 || dy != 0) && nx >= 0 && nx < w && ny >= 0 && ny < h {
+				f(ny*w + nx)
+			}
+		}
+	}
+}
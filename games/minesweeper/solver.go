@@ -0,0 +1,291 @@
+package minesweeper
+
+// maxNodes bounds the backtracking of one frontier region, so a huge open
+// region costs a failed attempt rather than a stall.
+const maxNodes = 100_000
+
+// constraint says that exactly Mines of Cells, all still closed, hide a
+// mine. Every open number with closed neighbours yields one.
+type constraint struct {
+	Cells []int
+	Mines int
+}
+
+// solver plays a board the way a careful player would: it only digs cells
+// it has proved safe and only flags cells it has proved to be mines.
+type solver struct {
+	w, h  int
+	adj   []int
+	open  []bool
+	flag  []bool
+	left  int // safe cells still closed
+	found int // mines flagged
+	total int
+}
+
+// solvable reports whether the board with mines can be cleared from a
+// first dig at start without ever guessing.
+func solvable(w, h int, mines []bool, start int) bool {
+	s := solver{w: w, h: h, adj: make([]int, len(mines)), open: make([]bool, len(mines)), flag: make([]bool, len(mines))}
+	for i, m := range mines {
+		if m {
+			s.total++
+			continue
+		}
+		s.left++
+		neighbours(w, h, i, func(j int) {
+			if mines[j] {
+				s.adj[i]++
+			}
+		})
+	}
+
+	s.dig(start)
+	for s.left > 0 {
+		if !s.deduceLocal() && !s.deduceGlobal() && !s.deduceRegions() {
+			return false
+		}
+	}
+	return true
+}
+
+// dig opens a cell proved safe, and the blank area around it.
+func (s *solver) dig(i int) {
+	stack := []int{i}
+	for len(stack) > 0 {
+		i, stack = stack[len(stack)-1], stack[:len(stack)-1]
+		if s.open[i] || s.flag[i] {
+			continue
+		}
+		s.open[i] = true
+		s.left--
+		if s.adj[i] == 0 {
+			neighbours(s.w, s.h, i, func(j int) { stack = append(stack, j) })
+		}
+	}
+}
+
+func (s *solver) mark(i int) {
+	if !s.flag[i] {
+		s.flag[i] = true
+		s.found++
+	}
+}
+
+func (s *solver) constraints() []constraint {
+	var cs []constraint
+	for i := range s.open {
+		if !s.open[i] || s.adj[i] == 0 {
+			continue
+		}
+		c := constraint{Mines: s.adj[i]}
+		neighbours(s.w, s.h, i, func(j int) {
+			switch {
+			case s.flag[j]:
+				c.Mines--
+			case !s.open[j]:
+				c.Cells = append(c.Cells, j)
+			}
+		})
+		if len(c.Cells) > 0 {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// deduceLocal applies the rules a single number gives: all of its closed
+// neighbours are mines, or none are.
+func (s *solver) deduceLocal() bool {
+	progress := false
+	for _, c := range s.constraints() {
+		switch c.Mines {
+		case 0:
+			for _, j := range c.Cells {
+				s.dig(j)
+			}
+			progress = true
+		case len(c.Cells):
+			for _, j := range c.Cells {
+				s.mark(j)
+			}
+			progress = true
+		}
+	}
+	return progress
+}
+
+// deduceGlobal uses the mine count: once every mine is flagged the rest is
+// safe, and once the closed cells are as many as the mines left they are
+// all mines.
+func (s *solver) deduceGlobal() bool {
+	var closed []int
+	for i := range s.open {
+		if !s.open[i] && !s.flag[i] {
+			closed = append(closed, i)
+		}
+	}
+	switch s.total - s.found {
+	case 0:
+		for _, i := range closed {
+			s.dig(i)
+		}
+		return len(closed) > 0
+	case len(closed):
+		for _, i := range closed {
+			s.mark(i)
+		}
+		return len(closed) > 0
+	}
+	return false
+}
+
+// deduceRegions enumerates every way to place mines on the closed cells
+// next to the numbers, one connected region at a time, and settles the
+// cells that are a mine in every arrangement or in none.
+func (s *solver) deduceRegions() bool {
+	cs := s.constraints()
+	progress := false
+	for _, region := range regions(cs) {
+		cells, mine, solutions := s.enumerate(region)
+		if solutions == 0 {
+			continue // gave up on a region too large
+		}
+		for k, i := range cells {
+			switch mine[k] {
+			case 0:
+				s.dig(i)
+				progress = true
+			case solutions:
+				s.mark(i)
+				progress = true
+			}
+		}
+	}
+	return progress
+}
+
+// regions splits constraints into groups that share no cell.
+func regions(cs []constraint) [][]constraint {
+	parent := make([]int, len(cs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	owner := map[int]int{}
+	for k, c := range cs {
+		for _, j := range c.Cells {
+			if o, ok := owner[j]; ok {
+				parent[find(k)] = find(o)
+			} else {
+				owner[j] = k
+			}
+		}
+	}
+
+	groups := map[int][]constraint{}
+	var order []int
+	for k, c := range cs {
+		r := find(k)
+		if _, ok := groups[r]; !ok {
+			order = append(order, r)
+		}
+		groups[r] = append(groups[r], c)
+	}
+	out := make([][]constraint, 0, len(order))
+	for _, r := range order {
+		out = append(out, groups[r])
+	}
+	return out
+}
+
+// enumerate backtracks over the cells of a region. It returns the cells,
+// how many arrangements put a mine on each, and how many arrangements
+// there are; 0 when it ran out of nodes.
+func (s *solver) enumerate(region []constraint) (cells []int, mine []int, solutions int) {
+	index := map[int]int{}
+	for _, c := range region {
+		for _, j := range c.Cells {
+			if _, ok := index[j]; !ok {
+				index[j] = len(cells)
+				cells = append(cells, j)
+			}
+		}
+	}
+	// watch lists the constraints each cell is part of.
+	watch := make([][]int, len(cells))
+	need := make([]int, len(region))
+	open := make([]int, len(region)) // cells not yet assigned
+	for k, c := range region {
+		need[k] = c.Mines
+		open[k] = len(c.Cells)
+		for _, j := range c.Cells {
+			watch[index[j]] = append(watch[index[j]], k)
+		}
+	}
+
+	mine = make([]int, len(cells))
+	assign := make([]bool, len(cells))
+	budget := s.total - s.found
+	nodes := 0
+
+	var walk func(n, placed int) bool
+	walk = func(n, placed int) bool {
+		if nodes++; nodes > maxNodes {
+			return false
+		}
+		if n == len(cells) {
+			solutions++
+			for k, m := range assign {
+				if m {
+					mine[k]++
+				}
+			}
+			return true
+		}
+		for _, m := range []bool{false, true} {
+			if m && placed == budget {
+				continue
+			}
+			ok := true
+			for _, k := range watch[n] {
+				open[k]--
+				if m {
+					need[k]--
+				}
+				if need[k] < 0 || need[k] > open[k] {
+					ok = false
+				}
+			}
+			assign[n] = m
+			if ok {
+				p := placed
+				if m {
+					p++
+				}
+				if !walk(n+1, p) {
+					return false
+				}
+			}
+			for _, k := range watch[n] {
+				open[k]++
+				if m {
+					need[k]++
+				}
+			}
+		}
+		assign[n] = false
+		return true
+	}
+	if !walk(0, 0) {
+		return nil, nil, 0
+	}
+	return cells, mine, solutions
+}
@@ -0,0 +1,125 @@
+package minesweeper
+
+import (
+	"strings"
+	"testing"
+)
+
+// layout reads a board drawn one row per string, "*" for a mine.
+func layout(rows ...string) (w, h int, mines []bool) {
+	for _, r := range rows {
+		for _, c := range r {
+			mines = append(mines, c == '*')
+		}
+	}
+	return len(rows[0]), len(rows), mines
+}
+
+func TestSolvable(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   []string
+		startX int
+		startY int
+		want   bool
+	}{
+		{
+			name: "one dig opens the board", startX: 4, startY: 4, want: true,
+			rows: []string{
+				"*....",
+				".....",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		{
+			// No number under the top row settles a cell on its own; only
+			// the numbers taken together do.
+			name: "1-2-1 needs the regions", startX: 2, startY: 4, want: true,
+			rows: []string{
+				".*.*.",
+				".....",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		{
+			name: "the wall is proved", startX: 4, startY: 4, want: true,
+			rows: []string{
+				"..*..",
+				"..*..",
+				".....",
+				".....",
+				".....",
+			},
+		},
+		{
+			// The corner pair touch the same numbers and hold one mine
+			// between them: a coin toss.
+			name: "a 50/50 in the corner", startX: 4, startY: 4, want: false,
+			rows: []string{
+				"*.*..",
+				"..*..",
+				".....",
+				".....",
+				".....",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h, mines := layout(tt.rows...)
+			if got := solvable(w, h, mines, tt.startY*w+tt.startX); got != tt.want {
+				t.Errorf("solvable = %v, want %v\n%s", got, tt.want, strings.Join(tt.rows, "\n"))
+			}
+		})
+	}
+}
+
+func TestNoGuessBoards(t *testing.T) {
+	for _, p := range []Preset{Tiny, Beginner, Intermediate} {
+		for seed := range uint64(10) {
+			x, y := p.Width/2, p.Height/2
+			b := New(p, true, seed).Prepared(x, y)
+			if !b.Guaranteed {
+				t.Errorf("%s seed %d: the generator gave up", p.Name, seed)
+				continue
+			}
+			if c := b.Cell(x, y); c.Mine || c.Adjacent != 0 {
+				t.Errorf("%s seed %d: first dig lands on %+v", p.Name, seed, c)
+			}
+			mines := make([]bool, len(b.cells))
+			n := 0
+			for i, c := range b.cells {
+				mines[i] = c.Mine
+				if c.Mine {
+					n++
+				}
+			}
+			if n != p.Mines {
+				t.Errorf("%s seed %d: %d mines, want %d", p.Name, seed, n, p.Mines)
+			}
+			if !solvable(p.Width, p.Height, mines, y*p.Width+x) {
+				t.Errorf("%s seed %d: the board needs a guess", p.Name, seed)
+			}
+		}
+	}
+}
+
+func TestPreparedIsSeeded(t *testing.T) {
+	a := New(Beginner, true, 7).Prepared(3, 3)
+	b := New(Beginner, true, 7).Prepared(3, 3)
+	for i := range a.cells {
+		if a.cells[i] != b.cells[i] {
+			t.Fatalf("the same seed laid different boards at cell %d", i)
+		}
+	}
+
+	fresh := New(Beginner, true, 7)
+	fresh.Prepared(3, 3)
+	if fresh.Started() {
+		t.Error("Prepared changed the board it was called on")
+	}
+}
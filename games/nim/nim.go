@@ -0,0 +1,163 @@
+/*
+Package nim is the rules of Nim: two players take turns removing matches
+from one heap at a time, as many as they like, and whoever takes the last
+match wins.
+
+A move is written "<heap>-<count>" with heaps numbered from 1, so "2-3"
+takes three matches from the second heap.
+*/
+package nim
+
+import (
+	"fmt"
+	"gamics/games/turn"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultHeaps is the classic 3-5-7 start.
+var DefaultHeaps = []int{3, 5, 7}
+
+// Game is a Nim position. It implements turn.Game.
+type Game struct {
+	Heaps []int
+	Turn  int // seat to move, 0 or 1
+	Last  int // seat that made the last move, -1 before the first
+}
+
+// New starts a game on the given heaps.
+func New(heaps []int) Game {
+	return Game{Heaps: slices.Clone(heaps), Last: -1}
+}
+
+// MoveFor writes the move taking count matches from heap (0-based).
+func MoveFor(heap, count int) turn.Move {
+	return turn.Move(fmt.Sprintf("%d-%d", heap+1, count))
+}
+
+// ParseMove reads a move back into a 0-based heap and a count.
+func ParseMove(m turn.Move) (heap, count int, err error) {
+	h, c, ok := strings.Cut(string(m), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %q is not <heap>-<count>", turn.ErrIllegal, m)
+	}
+	heap, err1 := strconv.Atoi(h)
+	count, err2 := strconv.Atoi(c)
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("%w: %q is not <heap>-<count>", turn.ErrIllegal, m)
+	}
+	return heap - 1, count, nil
+}
+
+func (g Game) Players() int { return 2 }
+func (g Game) ToMove() int  { return g.Turn }
+
+func (g Game) Legal() []turn.Move {
+	if g.Outcome().Over {
+		return nil
+	}
+	moves := make([]turn.Move, 0)
+	for h, n := range g.Heaps {
+		for c := 1; c <= n; c++ {
+			moves = append(moves, MoveFor(h, c))
+		}
+	}
+	return moves
+}
+
+func (g Game) Play(m turn.Move) (turn.Game, error) {
+	heap, count, err := ParseMove(m)
+	if err != nil {
+		return g, err
+	}
+	if heap < 0 || heap >= len(g.Heaps) || count < 1 || count > g.Heaps[heap] {
+		return g, fmt.Errorf("%w: heap %d has %d matches", turn.ErrIllegal, heap+1, g.heap(heap))
+	}
+
+	next := Game{Heaps: slices.Clone(g.Heaps), Turn: 1 - g.Turn, Last: g.Turn}
+	next.Heaps[heap] -= count
+	return next, nil
+}
+
+// Outcome is over once the matches are gone: whoever took the last one
+// wins. On heaps that start out empty the seat to move has nothing to take
+// and loses.
+func (g Game) Outcome() turn.Outcome {
+	if g.Matches() > 0 {
+		return turn.Outcome{}
+	}
+	if g.Last < 0 {
+		return turn.Outcome{Over: true, Winner: 1 - g.Turn, Reason: "no matches to take"}
+	}
+	return turn.Outcome{Over: true, Winner: g.Last, Reason: "took the last match"}
+}
+
+// Matches is how many matches are left on the table.
+func (g Game) Matches() int {
+	total := 0
+	for _, n := range g.Heaps {
+		total += n
+	}
+	return total
+}
+
+func (g Game) heap(i int) int {
+	if i < 0 || i >= len(g.Heaps) {
+		return 0
+	}
+	return g.Heaps[i]
+}
+
+// ----------------------------------------------------------------------------------
+// AI
+// ----------------------------------------------------------------------------------
+
+// NewAI maps a difficulty level to its strategy.
+func NewAI(level string, seed uint64) turn.AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return nimSumAI{r: r, mistakes: 0}
+	case turn.AIMedium:
+		return nimSumAI{r: r, mistakes: 2}
+	default:
+		return r
+	}
+}
+
+// nimSumAI plays the winning strategy: leave a position whose nim-sum (the
+// XOR of all heaps) is zero. With mistakes > 0 it plays a random move one
+// time in mistakes instead.
+type nimSumAI struct {
+	r        *turn.Random
+	mistakes int
+}
+
+func (ai nimSumAI) Choose(tg turn.Game) turn.Move {
+	g := tg.(Game)
+	if ai.mistakes > 0 && ai.r.Intn(ai.mistakes) == 0 {
+		return ai.r.Choose(g)
+	}
+
+	sum := 0
+	for _, n := range g.Heaps {
+		sum ^= n
+	}
+	if sum != 0 {
+		for h, n := range g.Heaps {
+			if target := n ^ sum; target < n {
+				return MoveFor(h, n-target)
+			}
+		}
+	}
+
+	// A lost position: take one match from the biggest heap and hope.
+	big := 0
+	for h, n := range g.Heaps {
+		if n > g.Heaps[big] {
+			big = h
+		}
+	}
+	return MoveFor(big, 1)
+}
@@ -0,0 +1,61 @@
+package nim
+
+import (
+	"gamics/games/turn"
+	"testing"
+)
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name  string
+		heaps []int
+		moves []turn.Move
+		want  turn.Outcome
+	}{
+		{"matches left", []int{1, 2}, []turn.Move{"2-2"}, turn.Outcome{}},
+		{"last match taken", []int{1, 2}, []turn.Move{"2-2", "1-1"}, turn.Outcome{Over: true, Winner: 1, Reason: "took the last match"}},
+		{"empty from the start", []int{0, 0}, nil, turn.Outcome{Over: true, Winner: 1, Reason: "no matches to take"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var g turn.Game = New(tt.heaps)
+			for _, m := range tt.moves {
+				var err error
+				if g, err = g.Play(m); err != nil {
+					t.Fatalf("Play(%s): %v", m, err)
+				}
+			}
+			if got := g.Outcome(); got != tt.want {
+				t.Errorf("Outcome = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAIOnEmptyHeaps(t *testing.T) {
+	g := New([]int{0, 0, 0})
+	if moves := g.Legal(); len(moves) != 0 {
+		t.Errorf("Legal = %v on empty heaps", moves)
+	}
+	if m := turn.NewRandom(1).Choose(g); m != "" {
+		t.Errorf("Random chose %q with no moves", m)
+	}
+}
+
+func TestNimSum(t *testing.T) {
+	ai := NewAI(turn.AIHard, 1)
+	for _, heaps := range [][]int{{3, 5, 7}, {1, 4}, {6}} {
+		g := New(heaps)
+		next, err := g.Play(ai.Choose(g))
+		if err != nil {
+			t.Fatalf("%v: %v", heaps, err)
+		}
+		sum := 0
+		for _, n := range next.(Game).Heaps {
+			sum ^= n
+		}
+		if sum != 0 {
+			t.Errorf("%v: the hard AI left %v, nim-sum %d", heaps, next.(Game).Heaps, sum)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package reversi
+
+import (
+	"gamics/games/turn"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+const (
+	winScore = 1_000_000
+
+	// DefaultThinkTime is how long the hardest CPU searches a move.
+	DefaultThinkTime = time.Second
+
+	mobilityWeight = 8
+	// checkEvery is how many nodes the search visits between looks at the
+	// clock.
+	checkEvery = 1024
+)
+
+// weights is what owning each square is worth: corners are gold, the
+// squares next to them give corners away, and edges are solid.
+var weights = [Squares]int{
+	100, -20, 10, 5, 5, 10, -20, 100,
+	-20, -50, -2, -2, -2, -2, -50, -20,
+	10, -2, -1, -1, -1, -1, -2, 10,
+	5, -2, -1, -1, -1, -1, -2, 5,
+	5, -2, -1, -1, -1, -1, -2, 5,
+	10, -2, -1, -1, -1, -1, -2, 10,
+	-20, -50, -2, -2, -2, -2, -50, -20,
+	100, -20, 10, 5, 5, 10, -20, 100,
+}
+
+// searchOrder tries the best squares first, so that alpha-beta cuts more.
+var searchOrder = func() []int {
+	order := make([]int, Squares)
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return weights[b] - weights[a] })
+	return order
+}()
+
+// NewAI maps a difficulty level to its strategy. Easy looks one ply ahead
+// and slips one move in three, medium searches up to four plies, and hard
+// as deep as think lets it, which solves the last dozen or so moves
+// exactly.
+func NewAI(level string, think time.Duration, seed uint64) turn.AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return Search{Think: max(think, time.Millisecond), MaxDepth: Squares, r: r}
+	case turn.AIMedium:
+		return Search{Think: max(think, time.Millisecond), MaxDepth: 4, r: r}
+	default:
+		return Search{Think: max(think, time.Millisecond), MaxDepth: 1, r: r, mistakes: 3}
+	}
+}
+
+// Search is a negamax alpha-beta search deepened one ply at a time until
+// its time is up; the move of the deepest search that finished is played.
+// Positions at the horizon are scored by the squares each side holds and
+// how many moves it has.
+type Search struct {
+	Think    time.Duration
+	MaxDepth int
+	r        *turn.Random
+	mistakes int // play at random one move in mistakes, 0 never
+}
+
+// searcher is the state of one Choose call.
+type searcher struct {
+	deadline time.Time
+	nodes    int
+	timeUp   bool
+}
+
+func (ai Search) Choose(tg turn.Game) turn.Move {
+	g := tg.(Game)
+	if ai.mistakes > 0 && ai.r.Intn(ai.mistakes) == 0 {
+		return ai.r.Choose(g)
+	}
+
+	me, opp := g.Discs[g.Turn], g.Discs[1-g.Turn]
+	moves := ordered(movesOf(me, opp))
+	s := searcher{deadline: time.Now().Add(ai.Think)}
+	empties := Squares - bits.OnesCount64(me|opp)
+
+	best := []int{moves[0]}
+	for depth := 1; depth <= min(ai.MaxDepth, empties); depth++ {
+		var found []int
+		bestScore := -2 * winScore
+		for _, sq := range moves {
+			f := flips(me, opp, sq)
+			score := -s.negamax(opp&^f, me|f|1<<sq, depth-1, -2*winScore, -(bestScore - 1), false)
+			if s.timeUp {
+				break
+			}
+			switch {
+			case score > bestScore:
+				bestScore, found = score, []int{sq}
+			case score == bestScore:
+				found = append(found, sq)
+			}
+		}
+		if s.timeUp {
+			break
+		}
+		best = found
+		// Search the best move first next time round.
+		moves = append([]int{found[0]}, slices.DeleteFunc(moves, func(sq int) bool { return sq == found[0] })...)
+		if bestScore >= winScore || bestScore <= -winScore {
+			break // the end is in sight: deeper won't change it
+		}
+	}
+	sq := best[ai.r.Intn(len(best))]
+	return SquareMove(sq/Size, sq%Size)
+}
+
+// negamax scores the position for me, within alpha and beta. passed is set
+// when the other side just had to pass.
+func (s *searcher) negamax(me, opp uint64, depth, alpha, beta int, passed bool) int {
+	if s.nodes++; s.nodes%checkEvery == 0 && time.Now().After(s.deadline) {
+		s.timeUp = true
+	}
+	if s.timeUp {
+		return 0
+	}
+
+	moves := movesOf(me, opp)
+	if moves == 0 {
+		if passed || movesOf(opp, me) == 0 {
+			return final(me, opp)
+		}
+		return -s.negamax(opp, me, depth, -beta, -alpha, true)
+	}
+	if depth == 0 {
+		return evaluate(me, opp, moves)
+	}
+
+	for _, sq := range ordered(moves) {
+		f := flips(me, opp, sq)
+		score := -s.negamax(opp&^f, me|f|1<<sq, depth-1, -beta, -alpha, false)
+		if score >= beta {
+			return score
+		}
+		alpha = max(alpha, score)
+	}
+	return alpha
+}
+
+// ordered lists the squares of moves best first.
+func ordered(moves uint64) []int {
+	list := make([]int, 0, bits.OnesCount64(moves))
+	for _, sq := range searchOrder {
+		if moves&(1<<sq) != 0 {
+			list = append(list, sq)
+		}
+	}
+	return list
+}
+
+// final scores a finished game by the disc margin, far above any estimate.
+func final(me, opp uint64) int {
+	diff := bits.OnesCount64(me) - bits.OnesCount64(opp)
+	switch {
+	case diff > 0:
+		return winScore + diff
+	case diff < 0:
+		return -winScore + diff
+	}
+	return 0
+}
+
+// evaluate scores a position for me, who has moves to play.
+func evaluate(me, opp, moves uint64) int {
+	score := 0
+	for b := me; b != 0; b &= b - 1 {
+		score += weights[bits.TrailingZeros64(b)]
+	}
+	for b := opp; b != 0; b &= b - 1 {
+		score -= weights[bits.TrailingZeros64(b)]
+	}
+	mobility := bits.OnesCount64(moves) - bits.OnesCount64(movesOf(opp, me))
+	return score + mobilityWeight*mobility
+}
@@ -0,0 +1,190 @@
+/*
+Package reversi is the rules of Reversi (Othello) and its searching
+opponent.
+
+Discs are placed on an 8×8 board so that they close a line of the
+opponent's discs, which all flip. A seat without a legal move passes, which
+the game does on its own; when neither seat can move, the most discs win.
+Seat 0 is black and moves first. A move is a square, "a1" to "h8", columns
+first.
+
+The board is a pair of bitboards, one per seat, bit row*8+col set where a
+disc lies. It keeps moves cheap enough for the search to look deep.
+*/
+package reversi
+
+import (
+	"fmt"
+	"gamics/games/turn"
+	"math/bits"
+)
+
+const (
+	Size    = 8
+	Squares = Size * Size
+)
+
+const (
+	notColA uint64 = 0xFEFEFEFEFEFEFEFE // every square but column a
+	notColH uint64 = 0x7F7F7F7F7F7F7F7F // every square but column h
+)
+
+// Point is a square, row 0 being the top, where a1 is.
+type Point struct{ Row, Col int }
+
+// Game is a Reversi position. It implements turn.Game.
+type Game struct {
+	Discs [2]uint64 // squares of each seat's discs
+	Turn  int
+	Last  int    // square of the last disc placed, -1 before the first
+	Flips uint64 // discs the last move turned over
+	// Passed is the seat that had no move after the last one and was
+	// skipped, -1 when nobody was.
+	Passed int
+}
+
+// New returns the opening position: two discs each, crossed in the centre.
+func New() Game {
+	return Game{
+		Discs:  [2]uint64{bit(3, 4) | bit(4, 3), bit(3, 3) | bit(4, 4)},
+		Last:   -1,
+		Passed: -1,
+	}
+}
+
+func bit(row, col int) uint64 { return 1 << (row*Size + col) }
+
+// SquareMove is the move placing a disc on row, col.
+func SquareMove(row, col int) turn.Move {
+	return turn.Move(fmt.Sprintf("%c%d", 'a'+col, row+1))
+}
+
+// ParseMove reads a move back into a square.
+func ParseMove(m turn.Move) (Point, error) {
+	if len(m) != 2 || m[0] < 'a' || m[0] >= 'a'+Size || m[1] < '1' || m[1] >= '1'+Size {
+		return Point{}, fmt.Errorf("%w: %q is not a square", turn.ErrIllegal, m)
+	}
+	return Point{Row: int(m[1] - '1'), Col: int(m[0] - 'a')}, nil
+}
+
+// Cell returns seat+1 of the disc on row, col, 0 when empty.
+func (g Game) Cell(row, col int) int {
+	b := bit(row, col)
+	switch {
+	case g.Discs[0]&b != 0:
+		return 1
+	case g.Discs[1]&b != 0:
+		return 2
+	}
+	return 0
+}
+
+// Count returns how many discs seat has.
+func (g Game) Count(seat int) int { return bits.OnesCount64(g.Discs[seat]) }
+
+func (g Game) Players() int { return 2 }
+func (g Game) ToMove() int  { return g.Turn }
+
+func (g Game) Legal() []turn.Move {
+	moves := movesOf(g.Discs[g.Turn], g.Discs[1-g.Turn])
+	list := make([]turn.Move, 0, bits.OnesCount64(moves))
+	for ; moves != 0; moves &= moves - 1 {
+		sq := bits.TrailingZeros64(moves)
+		list = append(list, SquareMove(sq/Size, sq%Size))
+	}
+	return list
+}
+
+func (g Game) Play(m turn.Move) (turn.Game, error) {
+	p, err := ParseMove(m)
+	if err != nil {
+		return g, err
+	}
+	sq := p.Row*Size + p.Col
+	if movesOf(g.Discs[g.Turn], g.Discs[1-g.Turn])&(1<<sq) == 0 {
+		return g, fmt.Errorf("%w: %s flips nothing", turn.ErrIllegal, m)
+	}
+	return g.place(sq), nil
+}
+
+// place puts a disc of the seat to move on sq, which must be legal, flips
+// what it closes and hands the turn on, skipping a seat without moves.
+func (g Game) place(sq int) Game {
+	me, opp := g.Turn, 1-g.Turn
+	f := flips(g.Discs[me], g.Discs[opp], sq)
+	g.Discs[me] |= f | 1<<sq
+	g.Discs[opp] &^= f
+	g.Last, g.Flips, g.Passed = sq, f, -1
+
+	switch {
+	case movesOf(g.Discs[opp], g.Discs[me]) != 0:
+		g.Turn = opp
+	case movesOf(g.Discs[me], g.Discs[opp]) != 0:
+		g.Passed = opp // the mover goes again
+	default:
+		g.Turn = opp // nobody can move: the game is over
+	}
+	return g
+}
+
+func (g Game) Outcome() turn.Outcome {
+	if movesOf(g.Discs[0], g.Discs[1]) != 0 || movesOf(g.Discs[1], g.Discs[0]) != 0 {
+		return turn.Outcome{}
+	}
+	black, white := g.Count(0), g.Count(1)
+	switch {
+	case black > white:
+		return turn.Outcome{Over: true, Winner: 0, Reason: fmt.Sprintf("%d discs to %d", black, white)}
+	case white > black:
+		return turn.Outcome{Over: true, Winner: 1, Reason: fmt.Sprintf("%d discs to %d", white, black)}
+	}
+	return turn.Outcome{Over: true, Winner: turn.Draw, Reason: fmt.Sprintf("%d discs each", black)}
+}
+
+// ----------------------------------------------------------------------------------
+// Bitboards
+// ----------------------------------------------------------------------------------
+
+// shifts steps every disc of b one square in each of the eight directions,
+// dropping those that would wrap around an edge.
+var shifts = [8]func(b uint64) uint64{
+	func(b uint64) uint64 { return b << 1 & notColA }, // right
+	func(b uint64) uint64 { return b >> 1 & notColH }, // left
+	func(b uint64) uint64 { return b << 8 },           // down
+	func(b uint64) uint64 { return b >> 8 },           // up
+	func(b uint64) uint64 { return b << 9 & notColA }, // down right
+	func(b uint64) uint64 { return b << 7 & notColH }, // down left
+	func(b uint64) uint64 { return b >> 7 & notColA }, // up right
+	func(b uint64) uint64 { return b >> 9 & notColH }, // up left
+}
+
+// movesOf returns the empty squares where me closes a line of opp.
+func movesOf(me, opp uint64) uint64 {
+	empty := ^(me | opp)
+	var moves uint64
+	for _, shift := range shifts {
+		x := shift(me) & opp
+		for i := 0; i < Size-3; i++ {
+			x |= shift(x) & opp
+		}
+		moves |= shift(x) & empty
+	}
+	return moves
+}
+
+// flips returns the discs of opp that a disc of me on sq turns over.
+func flips(me, opp uint64, sq int) uint64 {
+	var all uint64
+	for _, shift := range shifts {
+		var line uint64
+		x := shift(1 << sq)
+		for x&opp != 0 {
+			line |= x
+			x = shift(x)
+		}
+		if x&me != 0 {
+			all |= line
+		}
+	}
+	return all
+}
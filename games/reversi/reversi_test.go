@@ -0,0 +1,113 @@
+package reversi
+
+import (
+	"errors"
+	"gamics/games/turn"
+	"math/bits"
+	"testing"
+	"time"
+)
+
+// perft counts the positions depth plies from g.
+func perft(g Game, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	n := 0
+	for _, mv := range g.Legal() {
+		next, _ := g.Play(mv)
+		n += perft(next.(Game), depth-1)
+	}
+	return n
+}
+
+func TestPerft(t *testing.T) {
+	for depth, want := range []int{1, 4, 12, 56, 244, 1396, 8200} {
+		if got := perft(New(), depth); got != want {
+			t.Errorf("perft(%d) = %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestIllegalMoves(t *testing.T) {
+	g := New()
+	for _, mv := range []turn.Move{"a1", "d4", "i9", "e"} {
+		if _, err := g.Play(mv); !errors.Is(err, turn.ErrIllegal) {
+			t.Errorf("Play(%s) = %v, want an illegal move", mv, err)
+		}
+	}
+}
+
+func TestPass(t *testing.T) {
+	// Black takes b1, leaving white with nothing of its own: white passes
+	// and the game is over, all black.
+	g := Game{Discs: [2]uint64{bit(0, 0), bit(0, 1)}, Last: -1, Passed: -1}
+	next, err := g.Play("c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := next.(Game).Outcome()
+	if !out.Over || out.Winner != 0 || next.(Game).Count(0) != 3 {
+		t.Errorf("outcome %+v with %d black discs, want black winning 3 to 0", out, next.(Game).Count(0))
+	}
+}
+
+// solve is the exact disc margin for the seat to move under best play.
+func solve(g Game) int {
+	if g.Outcome().Over {
+		me := g.Turn
+		return g.Count(me) - g.Count(1-me)
+	}
+	best := -Squares
+	for _, mv := range g.Legal() {
+		next, _ := g.Play(mv)
+		ng := next.(Game)
+		score := solve(ng)
+		if ng.Turn != g.Turn {
+			score = -score
+		}
+		best = max(best, score)
+	}
+	return best
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// TestSearchEndgame plays random openings down to a few empty squares and
+// checks the hardest CPU never throws away the result best play gets.
+func TestSearchEndgame(t *testing.T) {
+	const empties = 8
+	r := turn.NewRandom(3)
+	for seed := range uint64(20) {
+		g := New()
+		for Squares-bits.OnesCount64(g.Discs[0]|g.Discs[1]) > empties && !g.Outcome().Over {
+			next, _ := g.Play(r.Choose(g))
+			g = next.(Game)
+		}
+		if g.Outcome().Over {
+			continue
+		}
+
+		mv := NewAI(turn.AIHard, 10*time.Second, seed).Choose(g)
+		next, err := g.Play(mv)
+		if err != nil {
+			t.Fatalf("seed %d: the CPU played %s: %v", seed, mv, err)
+		}
+		ng := next.(Game)
+		got := solve(ng)
+		if ng.Turn != g.Turn {
+			got = -got
+		}
+		if want := solve(g); sign(got) != sign(want) {
+			t.Errorf("seed %d: %s ends %+d, best play ends %+d", seed, mv, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package snake
+
+// AI difficulty levels.
+const (
+	AIEasy   = "easy"
+	AIMedium = "medium"
+	AIHard   = "hard"
+)
+
+// AI picks the direction Snakes[self] should take on the next tick.
+type AI interface {
+	NextDirection(st State, self int) string
+}
+
+// NewAI maps a difficulty level to its strategy.
+func NewAI(level string) AI {
+	switch level {
+	case AIHard:
+		return FloodFillAI{}
+	case AIMedium:
+		return BFSAI{}
+	default:
+		return GreedyAI{}
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Board helpers
+// ----------------------------------------------------------------------------------
+
+// obstacles marks every living body cell. Tail tips are left free because
+// they move away on the same tick the head moves in.
+func (st State) obstacles() map[Point]bool {
+	blocked := make(map[Point]bool)
+	for _, sn := range st.Snakes {
+		if sn.Dead {
+			continue
+		}
+		for i, p := range sn.Body {
+			if i == len(sn.Body)-1 && i > 0 {
+				continue
+			}
+			blocked[p] = true
+		}
+	}
+	return blocked
+}
+
+// safeMoves lists the directions that do not immediately kill snake self.
+func (st State) safeMoves(self int, blocked map[Point]bool) []string {
+	sn := st.Snakes[self]
+	moves := make([]string, 0, 3)
+	for _, d := range Directions {
+		if d == Opposite(sn.Heading) {
+			continue
+		}
+		next := Move(sn.Body[0], d)
+		if st.Inside(next) && !blocked[next] {
+			moves = append(moves, d)
+		}
+	}
+	return moves
+}
+
+// pathTo returns the first direction of a shortest path from `from` to `to`
+// through free cells, or "" when `to` is unreachable.
+func (st State) pathTo(from, to Point, blocked map[Point]bool) string {
+	type node struct {
+		At    Point
+		First string
+	}
+	seen := map[Point]bool{from: true}
+	queue := []node{{At: from}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, d := range Directions {
+			next := Move(n.At, d)
+			if seen[next] || !st.Inside(next) || blocked[next] {
+				continue
+			}
+			first := n.First
+			if first == "" {
+				first = d
+			}
+			if next == to {
+				return first
+			}
+			seen[next] = true
+			queue = append(queue, node{At: next, First: first})
+		}
+	}
+	return ""
+}
+
+// reachable counts the free cells connected to from, stopping at limit.
+func (st State) reachable(from Point, blocked map[Point]bool, limit int) int {
+	seen := map[Point]bool{from: true}
+	queue := []Point{from}
+	for len(queue) > 0 && len(seen) < limit {
+		c := queue[0]
+		queue = queue[1:]
+		for _, d := range Directions {
+			next := Move(c, d)
+			if seen[next] || !st.Inside(next) || blocked[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return len(seen)
+}
+
+// ----------------------------------------------------------------------------------
+// Strategies
+// ----------------------------------------------------------------------------------
+
+// GreedyAI heads straight for the food, only refusing moves that are fatal
+// right away. It happily walks into dead ends.
+type GreedyAI struct{}
+
+func (GreedyAI) NextDirection(st State, self int) string {
+	sn := st.Snakes[self]
+	moves := st.safeMoves(self, st.obstacles())
+	if len(moves) == 0 {
+		return sn.Heading
+	}
+
+	head := sn.Body[0]
+	best := moves[0]
+	for _, d := range moves[1:] {
+		if Manhattan(Move(head, d), st.Food.Position) < Manhattan(Move(head, best), st.Food.Position) {
+			best = d
+		}
+	}
+	return best
+}
+
+// BFSAI follows a shortest path around bodies to the food and falls back to
+// greedy when the food is walled off.
+type BFSAI struct{}
+
+func (BFSAI) NextDirection(st State, self int) string {
+	sn := st.Snakes[self]
+	if d := st.pathTo(sn.Body[0], st.Food.Position, st.obstacles()); d != "" && d != Opposite(sn.Heading) {
+		return d
+	}
+	return GreedyAI{}.NextDirection(st, self)
+}
+
+// FloodFillAI only takes the path to the food when the region it lands in
+// can still hold its body; otherwise it moves towards the largest open area.
+type FloodFillAI struct{}
+
+func (FloodFillAI) NextDirection(st State, self int) string {
+	sn := st.Snakes[self]
+	blocked := st.obstacles()
+	moves := st.safeMoves(self, blocked)
+	if len(moves) == 0 {
+		return sn.Heading
+	}
+
+	head := sn.Body[0]
+	need := len(sn.Body) + 1
+	room := func(d string) int {
+		next := Move(head, d)
+		blocked[next] = true
+		defer delete(blocked, next)
+		return st.reachable(next, blocked, need*2)
+	}
+
+	if d := st.pathTo(head, st.Food.Position, blocked); d != "" && d != Opposite(sn.Heading) && room(d) >= need {
+		return d
+	}
+
+	best, bestRoom := moves[0], room(moves[0])
+	for _, d := range moves[1:] {
+		r := room(d)
+		if r > bestRoom || (r == bestRoom && Manhattan(Move(head, d), st.Food.Position) < Manhattan(Move(head, best), st.Food.Position)) {
+			best, bestRoom = d, r
+		}
+	}
+	return best
+}
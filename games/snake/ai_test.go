@@ -0,0 +1,76 @@
+package snake
+
+import "testing"
+
+func TestAI(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		body  []Point
+		head  string
+		food  Point
+		want  []string // any of these
+	}{
+		{
+			name: "greedy heads for the food", level: AIEasy,
+			body: []Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}, head: Right,
+			food: Point{X: 5, Y: 1}, want: []string{Up},
+		},
+		{
+			name: "greedy never reverses", level: AIEasy,
+			body: []Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}, head: Right,
+			food: Point{X: 0, Y: 5}, want: []string{Up, Down},
+		},
+		{
+			name: "greedy avoids the wall", level: AIEasy,
+			body: []Point{{X: 9, Y: 5}, {X: 8, Y: 5}, {X: 7, Y: 5}}, head: Right,
+			food: Point{X: 9, Y: 9}, want: []string{Down},
+		},
+		{
+			// The food is just above, behind the snake's own body.
+			name: "bfs goes round the body", level: AIMedium,
+			body: []Point{{X: 5, Y: 5}, {X: 5, Y: 4}, {X: 4, Y: 4}, {X: 3, Y: 4}, {X: 2, Y: 4}}, head: Down,
+			food: Point{X: 4, Y: 3}, want: []string{Right},
+		},
+		{
+			// Left is the short way to the food, but the snake walls off that
+			// column and would not fit in it.
+			name: "flood fill stays out of a dead end", level: AIHard,
+			body: append(column(1, 0, 9), Point{X: 2, Y: 9}, Point{X: 3, Y: 9}), head: Up,
+			food: Point{X: 0, Y: 0}, want: []string{Right},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := field(tt.body, tt.head)
+			st.Food.Position = tt.food
+			got := NewAI(tt.level).NextDirection(st, 0)
+			ok := false
+			for _, w := range tt.want {
+				ok = ok || got == w
+			}
+			if !ok {
+				t.Errorf("%s AI went %s, want one of %v", tt.level, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAISnakeIgnoresInput(t *testing.T) {
+	st := field([]Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}, Right)
+	st.Snakes[0].AI = AIEasy
+	st.Food.Position = Point{X: 5, Y: 1}
+	next, _ := Step(st, Input{Turns: []string{Down}})
+	if next.Snakes[0].Heading != Up {
+		t.Errorf("AI snake heads %s, want %s towards the food", next.Snakes[0].Heading, Up)
+	}
+}
+
+// column is a body running down column x from y0 to y1, head first.
+func column(x, y0, y1 int) []Point {
+	var body []Point
+	for y := y0; y <= y1; y++ {
+		body = append(body, Point{X: x, Y: y})
+	}
+	return body
+}
@@ -0,0 +1,110 @@
+package snake
+
+import "time"
+
+// Food kinds. The zero value ("") is treated as normal food.
+const (
+	FoodNormal = "normal"
+	FoodGolden = "golden"
+	FoodShrink = "shrink"
+	FoodSlow   = "slow"
+	FoodGhost  = "ghost"
+	FoodPoison = "poison"
+)
+
+// FoodKind describes how a food item spawns and what it does to the snake
+// that eats it.
+type FoodKind struct {
+	Name   string
+	Weight int
+	TTL    time.Duration
+	Grows  bool
+	Effect func(s *Snake)
+}
+
+// FoodKinds is ordered so the weighted pick in randomFoodKind is stable.
+var FoodKinds = []FoodKind{
+	{Name: FoodNormal, Weight: 54, TTL: FoodTTL, Grows: true, Effect: func(s *Snake) {
+		s.Score++
+		s.Speed -= 0.05
+		s.Hunger = HungerReset
+	}},
+	{Name: FoodGolden, Weight: 10, TTL: 5 * time.Second, Grows: true, Effect: func(s *Snake) {
+		s.Score += 5
+		s.Hunger = HungerReset
+	}},
+	{Name: FoodShrink, Weight: 10, TTL: 8 * time.Second, Effect: func(s *Snake) {
+		s.Score++
+		s.Hunger = HungerReset
+		s.Body = s.Body[:max(len(s.Body)-3, MinLength)]
+	}},
+	{Name: FoodSlow, Weight: 8, TTL: 8 * time.Second, Grows: true, Effect: func(s *Snake) {
+		s.Score++
+		s.Hunger = HungerReset
+		s.SlowLeft = 8 * time.Second
+	}},
+	{Name: FoodGhost, Weight: 6, TTL: 6 * time.Second, Grows: true, Effect: func(s *Snake) {
+		s.Score++
+		s.Hunger = HungerReset
+		s.GhostLeft = 6 * time.Second
+	}},
+	{Name: FoodPoison, Weight: 12, TTL: 10 * time.Second, Effect: func(s *Snake) {
+		s.Score = max(s.Score-3, 0)
+		s.Hunger -= 10 * time.Second
+		if len(s.Body) > MinLength {
+			s.Body = s.Body[:len(s.Body)-1]
+		}
+	}},
+}
+
+// LookupFoodKind returns the kind called name, falling back to normal food.
+func LookupFoodKind(name string) FoodKind {
+	for _, k := range FoodKinds {
+		if k.Name == name {
+			return k
+		}
+	}
+	return FoodKinds[0]
+}
+
+func (s *State) randomFoodKind() FoodKind {
+	total := 0
+	for _, k := range FoodKinds {
+		total += k.Weight
+	}
+	n := s.intn(total)
+	for _, k := range FoodKinds {
+		if n < k.Weight {
+			return k
+		}
+		n -= k.Weight
+	}
+	return FoodKinds[0]
+}
+
+// placeFood puts a new random food item on a cell free of every living body.
+// On a full board the current food stays where it is with a fresh TTL.
+func (s *State) placeFood() {
+	occupied := s.occupied()
+	free := s.Width*s.Height - len(occupied)
+	if free <= 0 {
+		s.Food.TTL = LookupFoodKind(s.Food.Kind).TTL
+		return
+	}
+
+	n := s.intn(free)
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			p := Point{X: x, Y: y}
+			if occupied[p] {
+				continue
+			}
+			if n == 0 {
+				kind := s.randomFoodKind()
+				s.Food = Food{Position: p, Kind: kind.Name, TTL: kind.TTL}
+				return
+			}
+			n--
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package snake
+
+// DefaultQueueSize is how many turns a TurnQueue holds unless told otherwise.
+const DefaultQueueSize = 3
+
+// TurnQueue buffers the turns a player types faster than the snake moves,
+// so a quick up-then-left inside one tick becomes two consecutive turns
+// instead of only the last one. The caller pops one turn per Step.
+type TurnQueue struct {
+	Turns []string
+	Size  int
+}
+
+// NewTurnQueue returns an empty queue holding up to size turns. A size below
+// one falls back to DefaultQueueSize.
+func NewTurnQueue(size int) TurnQueue {
+	if size < 1 {
+		size = DefaultQueueSize
+	}
+	return TurnQueue{Size: size}
+}
+
+// Push queues dir for a snake currently heading towards heading. Turns are
+// checked against the last queued direction, or the heading when the queue
+// is empty: reversals and repeats are rejected, and so is anything once the
+// queue is full. It reports whether dir was queued.
+func (q *TurnQueue) Push(dir, heading string) bool {
+	last := heading
+	if len(q.Turns) > 0 {
+		last = q.Turns[len(q.Turns)-1]
+	}
+	if !valid(dir) || dir == last || dir == Opposite(last) || len(q.Turns) >= max(q.Size, 1) {
+		return false
+	}
+	q.Turns = append(q.Turns, dir)
+	return true
+}
+
+// Pop takes the next turn off the queue, or "" when it is empty.
+func (q *TurnQueue) Pop() string {
+	if len(q.Turns) == 0 {
+		return ""
+	}
+	dir := q.Turns[0]
+	q.Turns = q.Turns[1:]
+	return dir
+}
+
+// Clear drops every queued turn.
+func (q *TurnQueue) Clear() { q.Turns = nil }
@@ -0,0 +1,52 @@
+package snake
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTurnQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		heading string
+		push    []string
+		want    []string
+	}{
+		{name: "one turn", heading: Right, push: []string{Up}, want: []string{Up}},
+		{name: "two quick turns", heading: Right, push: []string{Up, Left}, want: []string{Up, Left}},
+		{name: "reversal", heading: Right, push: []string{Left}, want: nil},
+		{name: "reversal of the last queued", heading: Right, push: []string{Up, Down}, want: []string{Up}},
+		{name: "repeat", heading: Right, push: []string{Right, Up, Up}, want: []string{Up}},
+		{name: "unknown", heading: Right, push: []string{"", "north"}, want: nil},
+		{name: "full", size: 2, heading: Right, push: []string{Up, Left, Down}, want: []string{Up, Left}},
+		{name: "default size", size: 0, heading: Right, push: []string{Up, Left, Down, Right}, want: []string{Up, Left, Down}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewTurnQueue(tt.size)
+			for _, d := range tt.push {
+				q.Push(d, tt.heading)
+			}
+			var got []string
+			for d := q.Pop(); d != ""; d = q.Pop() {
+				got = append(got, d)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("popped %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTurnQueueClear(t *testing.T) {
+	q := NewTurnQueue(3)
+	q.Push(Up, Right)
+	q.Clear()
+	if d := q.Pop(); d != "" {
+		t.Errorf("Pop after Clear = %q, want nothing", d)
+	}
+	if !q.Push(Down, Right) {
+		t.Error("a cleared queue checks turns against the heading again")
+	}
+}
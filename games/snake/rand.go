@@ -0,0 +1,9 @@
+package snake
+
+import "gamics/internal/rng"
+
+// The engine carries its random generator inside State, in Rand, so that
+// Step stays a pure function of its arguments.
+
+// intn returns a number in [0, n). n <= 0 yields 0.
+func (s *State) intn(n int) int { return rng.Intn(&s.Rand, n) }
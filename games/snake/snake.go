@@ -0,0 +1,527 @@
+/*
+Package snake is the snake rules engine, free of any UI.
+
+A game is a State value. Step advances it by one movement tick and reports
+what happened as Events. Step is pure: the board size, the random generator
+and the game clock live inside State, so replaying the same inputs from the
+same state gives the same game.
+
+Time in the engine is game time. Every Step carries how much game time the
+tick lasted and hunger, food lifetimes and power-ups are kept as remaining
+durations that only count down inside Step. A paused or saved game therefore
+keeps its timers exactly where they were. The
+TUI, the bot runner and batch simulations all drive the game through these
+functions.
+*/
+package snake
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	FoodTTL     = 10 * time.Second
+	HungerReset = 30 * time.Second // game time a snake survives without eating
+	MinLength   = 3
+
+	slowMotionFactor = 1.75
+)
+
+// Directions.
+const (
+	Up    = "up"
+	Down  = "down"
+	Left  = "left"
+	Right = "right"
+)
+
+// Directions lists every direction in a fixed order.
+var Directions = []string{Up, Down, Left, Right}
+
+// Death reasons reported with EventDied.
+const (
+	DiedWall   = "wall"
+	DiedSelf   = "self"
+	DiedBody   = "body"
+	DiedHead   = "head"
+	DiedHunger = "hunger"
+)
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+type Point struct {
+	X int `yaml:"x" mapstructure:"x" json:"x"`
+	Y int `yaml:"y" mapstructure:"y" json:"y"`
+}
+
+type Snake struct {
+	Body      []Point       `yaml:"body"      mapstructure:"body"` // head first
+	Heading   string        `yaml:"heading"   mapstructure:"heading"`
+	Speed     float64       `yaml:"speed"     mapstructure:"speed"`
+	Hunger    time.Duration `yaml:"hunger"    mapstructure:"hunger"` // game time left before starving
+	Score     int           `yaml:"score"     mapstructure:"score"`
+	SlowLeft  time.Duration `yaml:"slowLeft"  mapstructure:"slowLeft"`
+	GhostLeft time.Duration `yaml:"ghostLeft" mapstructure:"ghostLeft"`
+	Dead      bool          `yaml:"dead"      mapstructure:"dead"`
+	AI        string        `yaml:"ai"        mapstructure:"ai"`        // difficulty level, empty for a human
+	Respawn   int           `yaml:"respawn"   mapstructure:"respawn"`   // ticks a dead snake waits to come back, 0 for never
+	RespawnIn int           `yaml:"respawnIn" mapstructure:"respawnIn"` // ticks left while waiting
+}
+
+type Food struct {
+	Position Point         `yaml:"position" mapstructure:"position"`
+	Kind     string        `yaml:"kind"     mapstructure:"kind"`
+	TTL      time.Duration `yaml:"ttl"      mapstructure:"ttl"` // game time left before it is replaced
+}
+
+type State struct {
+	Width  int           `yaml:"width"  mapstructure:"width"`
+	Height int           `yaml:"height" mapstructure:"height"`
+	Snakes []Snake       `yaml:"snakes" mapstructure:"snakes"`
+	Food   Food          `yaml:"food"   mapstructure:"food"`
+	Tick   int           `yaml:"tick"   mapstructure:"tick"`
+	Clock  time.Duration `yaml:"clock"  mapstructure:"clock"` // game time played so far
+	Rand   uint64        `yaml:"rand"   mapstructure:"rand"`
+}
+
+// Input is what the players do during one tick.
+type Input struct {
+	// Turns[i] is the direction requested for Snakes[i]. Empty, unknown or
+	// reversing directions keep the current heading. AI snakes ignore it.
+	Turns []string
+	// Elapsed is the game time the tick lasted, usually the TickInterval the
+	// caller waited before stepping.
+	Elapsed time.Duration
+}
+
+type EventKind string
+
+const (
+	EventAte         EventKind = "ate"
+	EventDied        EventKind = "died"
+	EventRespawned   EventKind = "respawned"
+	EventFoodSpawned EventKind = "foodSpawned"
+	EventFoodExpired EventKind = "foodExpired"
+)
+
+type Event struct {
+	Kind   EventKind
+	Snake  int    // index into State.Snakes, -1 for field events
+	Food   string // food kind for EventAte and food events
+	Reason string // death reason for EventDied
+}
+
+// SnakeConfig describes one snake of a new game.
+type SnakeConfig struct {
+	AI      string  // empty for a human-controlled snake
+	Respawn int     // ticks before a dead snake comes back, 0 for never
+	Body    []Point // starting body, head first; nil picks a spot
+	Heading string  // starting heading when Body is set
+}
+
+// Config describes a new game.
+type Config struct {
+	Width  int
+	Height int
+	Seed   uint64
+	Snakes []SnakeConfig
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New lays out a fresh game. Snakes with an explicit body start there,
+// respawning snakes start at a random free spot and the rest are lined up on
+// evenly spaced rows, alternating the side they start from.
+func New(cfg Config) State {
+	st := State{Width: cfg.Width, Height: cfg.Height, Rand: cfg.Seed}
+	lanes := 0
+	for _, sc := range cfg.Snakes {
+		if sc.Body == nil && sc.Respawn == 0 {
+			lanes++
+		}
+	}
+
+	lane := 0
+	for _, sc := range cfg.Snakes {
+		sn := Snake{AI: sc.AI, Respawn: sc.Respawn, Speed: 1, Hunger: HungerReset}
+		switch {
+		case sc.Body != nil:
+			sn.Body = append([]Point(nil), sc.Body...)
+			sn.Heading = sc.Heading
+		case sc.Respawn > 0:
+			sn.Dead, sn.RespawnIn = true, 1
+		default:
+			sn.Body, sn.Heading = laneBody(lane, lanes, cfg.Width, cfg.Height)
+			lane++
+		}
+		st.Snakes = append(st.Snakes, sn)
+	}
+
+	for i := range st.Snakes {
+		if st.Snakes[i].Dead {
+			st.respawn(i)
+		}
+	}
+	st.placeFood()
+	return st
+}
+
+func laneBody(i, n, w, h int) ([]Point, string) {
+	y := (i + 1) * h / (n + 1)
+	head, heading, back := Point{X: 5, Y: y}, Right, Left
+	if i%2 == 1 {
+		head, heading, back = Point{X: w - 6, Y: y}, Left, Right
+	}
+
+	body := []Point{head}
+	for len(body) < MinLength {
+		body = append(body, Move(body[len(body)-1], back))
+	}
+	return body, heading
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Step advances the game clock by in.Elapsed, then moves every living snake
+// one cell and resolves the tick:
+//   - a snake whose hunger runs out starves before it moves;
+//   - leaving the field or running into a body kills the snake;
+//   - two heads on the same cell, or two heads swapping cells, kill both;
+//   - a ghost snake passes through bodies but not through heads or walls;
+//   - the snake reaching the food eats it and a new one spawns;
+//   - food past its expiry is replaced.
+func Step(st State, in Input) (State, []Event) {
+	st = st.clone()
+	var events []Event
+
+	st.Clock += in.Elapsed
+	st.Food.TTL -= in.Elapsed
+	for i := range st.Snakes {
+		sn := &st.Snakes[i]
+		if sn.Dead {
+			continue
+		}
+		sn.SlowLeft = max(sn.SlowLeft-in.Elapsed, 0)
+		sn.GhostLeft = max(sn.GhostLeft-in.Elapsed, 0)
+		sn.Hunger -= in.Elapsed
+		if sn.Hunger <= 0 {
+			st.kill(i)
+			events = append(events, Event{Kind: EventDied, Snake: i, Reason: DiedHunger})
+		}
+	}
+
+	heads := make([]Point, len(st.Snakes))
+	moving := make([]bool, len(st.Snakes))
+	for i := range st.Snakes {
+		sn := &st.Snakes[i]
+		if sn.Dead || len(sn.Body) == 0 {
+			continue
+		}
+		turn := ""
+		if i < len(in.Turns) {
+			turn = in.Turns[i]
+		}
+		if sn.AI != "" {
+			turn = NewAI(sn.AI).NextDirection(st, i)
+		}
+		if valid(turn) && turn != Opposite(sn.Heading) {
+			sn.Heading = turn
+		}
+		heads[i], moving[i] = Move(sn.Body[0], sn.Heading), true
+	}
+
+	killed := make([]string, len(st.Snakes))
+	for i := range st.Snakes {
+		for j := i + 1; j < len(st.Snakes); j++ {
+			if moving[i] && moving[j] && heads[i] == st.Snakes[j].Body[0] && heads[j] == st.Snakes[i].Body[0] {
+				killed[i], killed[j] = DiedHead, DiedHead
+			}
+		}
+	}
+
+	// Move everybody first so body checks see where the tails went.
+	kind := LookupFoodKind(st.Food.Kind)
+	eater := -1
+	for i := range st.Snakes {
+		if !moving[i] {
+			continue
+		}
+		ate := heads[i] == st.Food.Position && eater == -1
+		if ate {
+			eater = i
+		}
+		st.Snakes[i].advance(heads[i], ate && kind.Grows)
+	}
+
+	for i := range st.Snakes {
+		if !moving[i] || killed[i] != "" {
+			continue
+		}
+		killed[i] = st.collision(i, heads)
+	}
+
+	if eater != -1 && killed[eater] == "" {
+		kind.Effect(&st.Snakes[eater])
+		events = append(events, Event{Kind: EventAte, Snake: eater, Food: kind.Name})
+		if st.Snakes[eater].Hunger <= 0 {
+			killed[eater] = DiedHunger
+		}
+	}
+
+	for i, reason := range killed {
+		if reason != "" {
+			st.kill(i)
+			events = append(events, Event{Kind: EventDied, Snake: i, Reason: reason})
+		}
+	}
+
+	for i := range st.Snakes {
+		sn := &st.Snakes[i]
+		if !sn.Dead || sn.Respawn == 0 || moving[i] {
+			continue
+		}
+		sn.RespawnIn--
+		if sn.RespawnIn <= 0 && st.respawn(i) {
+			events = append(events, Event{Kind: EventRespawned, Snake: i})
+		}
+	}
+
+	if eater != -1 {
+		st.placeFood()
+		events = append(events, Event{Kind: EventFoodSpawned, Snake: -1, Food: st.Food.Kind})
+	} else if st.Food.TTL <= 0 {
+		events = append(events, Event{Kind: EventFoodExpired, Snake: -1, Food: st.Food.Kind})
+		st.placeFood()
+		events = append(events, Event{Kind: EventFoodSpawned, Snake: -1, Food: st.Food.Kind})
+	}
+
+	st.Tick++
+	return st, events
+}
+
+// collision returns why snake i dies at its new head, or "" if it survives.
+func (st State) collision(i int, heads []Point) string {
+	head := heads[i]
+	if !st.Inside(head) {
+		return DiedWall
+	}
+	ghost := st.Snakes[i].Ghost()
+	for j, other := range st.Snakes {
+		if other.Dead || len(other.Body) == 0 {
+			continue
+		}
+		if j != i && other.Body[0] == head {
+			return DiedHead
+		}
+		if ghost {
+			continue
+		}
+		for _, p := range other.Body[1:] {
+			if p != head {
+				continue
+			}
+			if j == i {
+				return DiedSelf
+			}
+			return DiedBody
+		}
+	}
+	return ""
+}
+
+func (st *State) kill(i int) {
+	sn := &st.Snakes[i]
+	sn.Dead = true
+	if sn.Respawn > 0 {
+		sn.Body = nil
+		sn.RespawnIn = sn.Respawn
+	}
+}
+
+// respawn drops snake i as a fresh three-cell snake on a random free row,
+// facing the far side of the field. It reports false when no spot was found;
+// the snake then tries again on the next tick.
+func (st *State) respawn(i int) bool {
+	if st.Width < 10 || st.Height < 3 {
+		return false
+	}
+
+	occupied := st.occupied()
+	var avoid []Point
+	for j, other := range st.Snakes {
+		if j != i && !other.Dead && other.AI == "" && len(other.Body) > 0 {
+			avoid = append(avoid, other.Body[0])
+		}
+	}
+
+	for tries := 0; tries < 100; tries++ {
+		head := Point{X: 3 + st.intn(st.Width-6), Y: 1 + st.intn(st.Height-2)}
+		heading, back := Right, Left
+		if head.X >= st.Width/2 {
+			heading, back = Left, Right
+		}
+		near := false
+		for _, a := range avoid {
+			near = near || Manhattan(a, head) < 6
+		}
+		if near {
+			continue
+		}
+
+		body := []Point{head}
+		for len(body) < MinLength {
+			body = append(body, Move(body[len(body)-1], back))
+		}
+		free := true
+		for _, p := range body {
+			free = free && !occupied[p] && p != st.Food.Position
+		}
+		if !free {
+			continue
+		}
+
+		sn := &st.Snakes[i]
+		*sn = Snake{Body: body, Heading: heading, Speed: 1, Hunger: HungerReset, Score: sn.Score, AI: sn.AI, Respawn: sn.Respawn}
+		return true
+	}
+	st.Snakes[i].RespawnIn = 1
+	return false
+}
+
+// ----------------------------------------------------------------------------------
+// Helpers
+// ----------------------------------------------------------------------------------
+
+// advance moves sn so its head sits on head. When grow is set the tail stays
+// in place and the snake gets one segment longer.
+func (sn *Snake) advance(head Point, grow bool) {
+	if grow {
+		sn.Body = append([]Point{head}, sn.Body...)
+		return
+	}
+	copy(sn.Body[1:], sn.Body[:len(sn.Body)-1])
+	sn.Body[0] = head
+}
+
+// Alive reports whether the snake is on the field.
+func (sn Snake) Alive() bool { return !sn.Dead && len(sn.Body) > 0 }
+
+// Ghost reports whether the snake passes through bodies.
+func (sn Snake) Ghost() bool { return sn.GhostLeft > 0 }
+
+// Slow reports whether the snake is in slow motion.
+func (sn Snake) Slow() bool { return sn.SlowLeft > 0 }
+
+// Seconds rounds a timer up to whole seconds, the way players see it count
+// down: it shows 1 until it runs out.
+func Seconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// Inside reports whether p lies on the field.
+func (st State) Inside(p Point) bool {
+	return p.X >= 0 && p.X < st.Width && p.Y >= 0 && p.Y < st.Height
+}
+
+// TickInterval is how long one movement tick of snake i lasts. Snakes speed
+// up as they eat, bigger fields run faster and slow motion stretches the
+// tick.
+func (st State) TickInterval(i int) time.Duration {
+	sn := st.Snakes[i]
+	speed := sn.Speed
+	if speed <= 0 {
+		speed = 0.05
+	}
+	const (
+		baseMs  = 200.0
+		minMs   = 16.0
+		maxMs   = 1000.0
+		refDiag = 80.0
+	)
+	size := math.Hypot(float64(st.Width), float64(st.Height))
+	ms := speed * baseMs / (1.0 + size/refDiag)
+	if sn.Slow() {
+		ms *= slowMotionFactor
+	}
+	ms = math.Min(math.Max(ms, minMs), maxMs)
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (st State) occupied() map[Point]bool {
+	occupied := make(map[Point]bool)
+	for _, sn := range st.Snakes {
+		if sn.Dead {
+			continue
+		}
+		for _, p := range sn.Body {
+			occupied[p] = true
+		}
+	}
+	return occupied
+}
+
+// clone deep-copies the bodies so a step never mutates its input.
+func (st State) clone() State {
+	snakes := make([]Snake, len(st.Snakes))
+	copy(snakes, st.Snakes)
+	for i := range snakes {
+		snakes[i].Body = append([]Point(nil), snakes[i].Body...)
+	}
+	st.Snakes = snakes
+	return st
+}
+
+// Move returns p shifted one cell towards dir.
+func Move(p Point, dir string) Point {
+	switch dir {
+	case Up:
+		p.Y--
+	case Down:
+		p.Y++
+	case Left:
+		p.X--
+	case Right:
+		p.X++
+	}
+	return p
+}
+
+// Opposite returns the reverse of dir.
+func Opposite(dir string) string {
+	switch dir {
+	case Up:
+		return Down
+	case Down:
+		return Up
+	case Left:
+		return Right
+	case Right:
+		return Left
+	}
+	return ""
+}
+
+func valid(dir string) bool {
+	return dir == Up || dir == Down || dir == Left || dir == Right
+}
+
+// Manhattan is the grid distance between a and b.
+func Manhattan(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
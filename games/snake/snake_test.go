@@ -0,0 +1,227 @@
+package snake
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// field is a 10x10 game with one human snake and normal food far away in
+// the bottom right corner.
+func field(body []Point, heading string) State {
+	return State{
+		Width:  10,
+		Height: 10,
+		Snakes: []Snake{{Body: body, Heading: heading, Speed: 1, Hunger: HungerReset}},
+		Food:   Food{Position: Point{X: 9, Y: 9}, Kind: FoodNormal, TTL: FoodTTL},
+		Rand:   1,
+	}
+}
+
+func died(events []Event, i int) string {
+	for _, e := range events {
+		if e.Kind == EventDied && e.Snake == i {
+			return e.Reason
+		}
+	}
+	return ""
+}
+
+func has(events []Event, kind EventKind) bool {
+	return slices.ContainsFunc(events, func(e Event) bool { return e.Kind == kind })
+}
+
+func TestStep(t *testing.T) {
+	line := []Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}
+	tests := []struct {
+		name    string
+		st      State
+		turn    string
+		elapsed time.Duration
+		head    Point
+		length  int
+		died    string
+	}{
+		{name: "straight on", st: field(line, Right), head: Point{X: 6, Y: 5}, length: 3},
+		{name: "turn", st: field(line, Right), turn: Up, head: Point{X: 5, Y: 4}, length: 3},
+		{name: "reversal ignored", st: field(line, Right), turn: Left, head: Point{X: 6, Y: 5}, length: 3},
+		{name: "unknown turn ignored", st: field(line, Right), turn: "sideways", head: Point{X: 6, Y: 5}, length: 3},
+		{
+			name: "right wall",
+			st:   field([]Point{{X: 9, Y: 5}, {X: 8, Y: 5}, {X: 7, Y: 5}}, Right),
+			head: Point{X: 10, Y: 5}, length: 3, died: DiedWall,
+		},
+		{
+			name: "top wall",
+			st:   field([]Point{{X: 5, Y: 0}, {X: 5, Y: 1}, {X: 5, Y: 2}}, Up),
+			head: Point{X: 5, Y: -1}, length: 3, died: DiedWall,
+		},
+		{
+			name: "own body",
+			st:   field([]Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 4, Y: 6}, {X: 4, Y: 5}, {X: 4, Y: 4}}, Up),
+			turn: Left, head: Point{X: 4, Y: 5}, length: 5, died: DiedSelf,
+		},
+		{
+			name: "chasing the tail",
+			st:   field([]Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 4, Y: 6}, {X: 4, Y: 5}}, Up),
+			turn: Left, head: Point{X: 4, Y: 5}, length: 4,
+		},
+		{
+			name: "ghost passes through itself",
+			st: func() State {
+				st := field([]Point{{X: 5, Y: 5}, {X: 5, Y: 6}, {X: 4, Y: 6}, {X: 4, Y: 5}, {X: 4, Y: 4}}, Up)
+				st.Snakes[0].GhostLeft = time.Second
+				return st
+			}(),
+			turn: Left, head: Point{X: 4, Y: 5}, length: 5,
+		},
+		{
+			name: "starves before moving",
+			st: func() State {
+				st := field(line, Right)
+				st.Snakes[0].Hunger = 100 * time.Millisecond
+				return st
+			}(),
+			elapsed: 100 * time.Millisecond, head: Point{X: 5, Y: 5}, length: 3, died: DiedHunger,
+		},
+		{
+			name: "eats and grows",
+			st: func() State {
+				st := field(line, Right)
+				st.Food.Position = Point{X: 6, Y: 5}
+				return st
+			}(),
+			head: Point{X: 6, Y: 5}, length: 4,
+		},
+		{
+			name: "shrink food",
+			st: func() State {
+				st := field([]Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}, {X: 2, Y: 5}, {X: 1, Y: 5}}, Right)
+				st.Food = Food{Position: Point{X: 6, Y: 5}, Kind: FoodShrink, TTL: time.Second}
+				return st
+			}(),
+			head: Point{X: 6, Y: 5}, length: MinLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := slices.Clone(tt.st.Snakes[0].Body)
+			next, events := Step(tt.st, Input{Turns: []string{tt.turn}, Elapsed: tt.elapsed})
+
+			if !slices.Equal(tt.st.Snakes[0].Body, before) {
+				t.Errorf("Step changed its input: %v, was %v", tt.st.Snakes[0].Body, before)
+			}
+			sn := next.Snakes[0]
+			if got := died(events, 0); got != tt.died {
+				t.Errorf("died of %q, want %q", got, tt.died)
+			}
+			if sn.Dead != (tt.died != "") {
+				t.Errorf("Dead = %v, want %v", sn.Dead, tt.died != "")
+			}
+			if sn.Body[0] != tt.head {
+				t.Errorf("head at %v, want %v", sn.Body[0], tt.head)
+			}
+			if len(sn.Body) != tt.length {
+				t.Errorf("length %d, want %d", len(sn.Body), tt.length)
+			}
+			if next.Tick != tt.st.Tick+1 {
+				t.Errorf("tick %d, want %d", next.Tick, tt.st.Tick+1)
+			}
+		})
+	}
+}
+
+func TestStepHeadOn(t *testing.T) {
+	st := field([]Point{{X: 4, Y: 5}, {X: 3, Y: 5}, {X: 2, Y: 5}}, Right)
+	st.Snakes = append(st.Snakes, Snake{Body: []Point{{X: 5, Y: 5}, {X: 6, Y: 5}, {X: 7, Y: 5}}, Heading: Left, Speed: 1, Hunger: HungerReset})
+
+	_, events := Step(st, Input{})
+	for i := range st.Snakes {
+		if got := died(events, i); got != DiedHead {
+			t.Errorf("snake %d died of %q, want %q", i, got, DiedHead)
+		}
+	}
+}
+
+func TestStepFood(t *testing.T) {
+	t.Run("eaten food respawns on a free cell", func(t *testing.T) {
+		st := field([]Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}, Right)
+		st.Food.Position = Point{X: 6, Y: 5}
+		for seed := uint64(1); seed <= 50; seed++ {
+			st.Rand = seed
+			next, events := Step(st, Input{})
+			if !has(events, EventAte) || !has(events, EventFoodSpawned) {
+				t.Fatalf("seed %d: events %v, want ate and foodSpawned", seed, events)
+			}
+			if slices.Contains(next.Snakes[0].Body, next.Food.Position) {
+				t.Errorf("seed %d: food at %v is on the snake", seed, next.Food.Position)
+			}
+			if !next.Inside(next.Food.Position) {
+				t.Errorf("seed %d: food at %v is off the field", seed, next.Food.Position)
+			}
+			if next.Food.TTL != LookupFoodKind(next.Food.Kind).TTL {
+				t.Errorf("seed %d: fresh %s food has TTL %v", seed, next.Food.Kind, next.Food.TTL)
+			}
+		}
+	})
+
+	t.Run("full field keeps the food", func(t *testing.T) {
+		st := field(nil, Right)
+		st.Width, st.Height = 3, 1
+		st.Snakes[0].Body = []Point{{X: 2, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 0}}
+		st.Food = Food{Position: Point{X: 1, Y: 0}, Kind: FoodNormal, TTL: time.Millisecond}
+		st.placeFood()
+		if st.Food.Position != (Point{X: 1, Y: 0}) || st.Food.TTL != FoodTTL {
+			t.Errorf("food %+v, want it left in place with a fresh TTL", st.Food)
+		}
+	})
+
+	t.Run("TTL counts down and expires", func(t *testing.T) {
+		st := field([]Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}}, Right)
+		st.Food.TTL = 300 * time.Millisecond
+
+		next, events := Step(st, Input{Elapsed: 200 * time.Millisecond})
+		if has(events, EventFoodExpired) || next.Food.TTL != 100*time.Millisecond {
+			t.Fatalf("after 200ms: TTL %v, events %v", next.Food.TTL, events)
+		}
+		next, events = Step(next, Input{Elapsed: 200 * time.Millisecond})
+		if !has(events, EventFoodExpired) || !has(events, EventFoodSpawned) {
+			t.Errorf("after 400ms: events %v, want foodExpired then foodSpawned", events)
+		}
+		if next.Food.TTL <= 0 {
+			t.Errorf("replacement food has TTL %v", next.Food.TTL)
+		}
+	})
+}
+
+func TestStepDeterministic(t *testing.T) {
+	cfg := Config{Width: 20, Height: 12, Seed: 42, Snakes: []SnakeConfig{{AI: AIHard}, {AI: AIMedium}}}
+	a, b := New(cfg), New(cfg)
+	for range 200 {
+		a, _ = Step(a, Input{Elapsed: 100 * time.Millisecond})
+		b, _ = Step(b, Input{Elapsed: 100 * time.Millisecond})
+	}
+	if a.Food != b.Food || a.Rand != b.Rand || a.Snakes[0].Score != b.Snakes[0].Score {
+		t.Errorf("the same seed played out differently: %+v and %+v", a.Food, b.Food)
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want int
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{time.Second + time.Millisecond, 2},
+		{HungerReset, 30},
+	}
+	for _, tt := range tests {
+		if got := Seconds(tt.d); got != tt.want {
+			t.Errorf("Seconds(%v) = %d, want %d", tt.d, got, tt.want)
+		}
+	}
+}
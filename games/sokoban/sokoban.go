@@ -0,0 +1,233 @@
+/*
+Package sokoban is the warehouse-keeper puzzle, free of any UI: levels
+read from the standard XSB text format and the push rules played on them.
+
+A game is a State value and Move returns the next one, so undo and redo
+are stacks of states. Moves are written in LURD notation, lowercase for a
+step and uppercase for a push, as the solutions in XSB packs are.
+*/
+package sokoban
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Tile is the fixed floor plan of a cell.
+type Tile byte
+
+const (
+	Outside Tile = iota // beyond the walls, drawn blank
+	Floor
+	Wall
+	Goal
+)
+
+// Directions, in LURD notation.
+const (
+	Left  = 'l'
+	Up    = 'u'
+	Right = 'r'
+	Down  = 'd'
+)
+
+var steps = map[byte]Point{Left: {-1, 0}, Up: {0, -1}, Right: {1, 0}, Down: {0, 1}}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Point is a cell, 0, 0 being the top left.
+type Point struct{ X, Y int }
+
+func (p Point) add(q Point) Point { return Point{p.X + q.X, p.Y + q.Y} }
+
+// Level is one puzzle of a pack as it starts.
+type Level struct {
+	Title  string
+	Width  int
+	Height int
+	Tiles  []Tile // row by row
+	Boxes  []Point
+	Player Point
+}
+
+// State is a level being played.
+type State struct {
+	Player Point
+	Boxes  []Point
+	Moves  int
+	Pushes int
+	// Path is every move so far in LURD notation.
+	Path string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// Start is the level before the first move.
+func (l *Level) Start() State {
+	return State{Player: l.Player, Boxes: slices.Clone(l.Boxes)}
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Tile returns the tile at x, y; anything off the map is Outside.
+func (l *Level) Tile(x, y int) Tile {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return Outside
+	}
+	return l.Tiles[y*l.Width+x]
+}
+
+func (l *Level) open(p Point) bool {
+	t := l.Tile(p.X, p.Y)
+	return t == Floor || t == Goal
+}
+
+// Box returns the index of the box on p, or -1.
+func (s State) Box(p Point) int { return slices.Index(s.Boxes, p) }
+
+// Move walks the keeper one cell toward dir, pushing the box there if the
+// cell behind it is free. A move into a wall or a stuck box returns s as it
+// is and false.
+func (s State) Move(l *Level, dir byte) (State, bool) {
+	step, ok := steps[dir]
+	if !ok {
+		return s, false
+	}
+	to := s.Player.add(step)
+	if !l.open(to) {
+		return s, false
+	}
+	next := s
+	next.Player = to
+	next.Moves++
+	if b := s.Box(to); b >= 0 {
+		behind := to.add(step)
+		if !l.open(behind) || s.Box(behind) >= 0 {
+			return s, false
+		}
+		next.Boxes = slices.Clone(s.Boxes)
+		next.Boxes[b] = behind
+		next.Pushes++
+		dir -= 'a' - 'A'
+	}
+	next.Path += string(dir)
+	return next, true
+}
+
+// Solved reports whether every box sits on a goal.
+func (s State) Solved(l *Level) bool {
+	for _, b := range s.Boxes {
+		if l.Tile(b.X, b.Y) != Goal {
+			return false
+		}
+	}
+	return true
+}
+
+// BoxesHome counts the boxes on goals.
+func (s State) BoxesHome(l *Level) int {
+	n := 0
+	for _, b := range s.Boxes {
+		if l.Tile(b.X, b.Y) == Goal {
+			n++
+		}
+	}
+	return n
+}
+
+// ----------------------------------------------------------------------------------
+// Checks
+// ----------------------------------------------------------------------------------
+
+// seal marks the floor the keeper can't walk to as Outside and checks the
+// level can be played: walls all around, one box per goal, and every box
+// and goal inside.
+func (l *Level) seal() error {
+	inside := make([]bool, len(l.Tiles))
+	queue := []Point{l.Player}
+	inside[l.Player.Y*l.Width+l.Player.X] = true
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range steps {
+			q := p.add(d)
+			if q.X < 0 || q.Y < 0 || q.X >= l.Width || q.Y >= l.Height {
+				return fmt.Errorf("the walls don't close around the keeper")
+			}
+			i := q.Y*l.Width + q.X
+			if !inside[i] && l.Tiles[i] != Wall {
+				inside[i] = true
+				queue = append(queue, q)
+			}
+		}
+	}
+
+	goals := 0
+	for i, t := range l.Tiles {
+		if t == Wall {
+			continue
+		}
+		if !inside[i] {
+			if t == Goal {
+				return fmt.Errorf("a goal lies outside the walls")
+			}
+			l.Tiles[i] = Outside
+			continue
+		}
+		if t == Goal {
+			goals++
+		}
+	}
+	for _, b := range l.Boxes {
+		if !inside[b.Y*l.Width+b.X] {
+			return fmt.Errorf("a box lies outside the walls")
+		}
+	}
+	if len(l.Boxes) == 0 || len(l.Boxes) != goals {
+		return fmt.Errorf("%d boxes for %d goals", len(l.Boxes), goals)
+	}
+	return nil
+}
+
+// String draws the level as it starts, in XSB.
+func (l *Level) String() string {
+	return l.Start().XSB(l)
+}
+
+// XSB draws the level at s in XSB, with trailing floor trimmed.
+func (s State) XSB(l *Level) string {
+	var b strings.Builder
+	for y := 0; y < l.Height; y++ {
+		var row []byte
+		for x := 0; x < l.Width; x++ {
+			p := Point{x, y}
+			t := l.Tile(x, y)
+			c := byte(' ')
+			switch {
+			case t == Wall:
+				c = '#'
+			case p == s.Player && t == Goal:
+				c = '+'
+			case p == s.Player:
+				c = '@'
+			case s.Box(p) >= 0 && t == Goal:
+				c = '*'
+			case s.Box(p) >= 0:
+				c = '$'
+			case t == Goal:
+				c = '.'
+			}
+			row = append(row, c)
+		}
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
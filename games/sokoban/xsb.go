@@ -0,0 +1,245 @@
+package sokoban
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// PackExt is the file extension of level packs.
+const PackExt = ".xsb"
+
+//go:embed levels/*.xsb
+var builtin embed.FS
+
+// Pack is a file of levels.
+type Pack struct {
+	Name string
+	// File is the file name without its extension; records are kept by it,
+	// so they survive a pack changing its title.
+	File   string
+	Levels []Level
+	// Builtin is set on the packs shipped with gamics.
+	Builtin bool
+}
+
+// ----------------------------------------------------------------------------------
+// XSB
+// ----------------------------------------------------------------------------------
+
+// ParsePack reads levels in XSB: each level is a block of map rows, with
+// blank lines, comments (;) and metadata lines (Title: ...) between blocks.
+//
+//	#  wall         @  keeper       $  box
+//	.  goal         +  keeper on a goal
+//	*  box on goal  space, - or _  floor
+//
+// A level takes the title of a Title: line after its rows, or else of a
+// comment line right before or after them. A Title: line ahead of every
+// level names the pack; name is the pack's name otherwise.
+func ParsePack(name string, r io.Reader) (Pack, error) {
+	pack := Pack{Name: name, File: name}
+	var (
+		rows    []string
+		title   string // title of the level being read
+		pending string // comment right above the next level
+		last    = -1   // index of the level just closed, while its metadata follows
+		lineNo  int
+		start   int // line of the first row of the level being read
+	)
+
+	closeLevel := func() error {
+		if len(rows) == 0 {
+			return nil
+		}
+		lv, err := parseLevel(rows)
+		if err != nil {
+			return fmt.Errorf("level %d on line %d: %w", len(pack.Levels)+1, start, err)
+		}
+		lv.Title = title
+		pack.Levels = append(pack.Levels, lv)
+		rows, title, pending = nil, "", ""
+		last = len(pack.Levels) - 1
+		return nil
+	}
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case isRow(line):
+			if len(rows) == 0 {
+				title, pending, last = pending, "", -1
+				start = lineNo
+			}
+			rows = append(rows, line)
+			continue
+
+		case trimmed == "":
+			if err := closeLevel(); err != nil {
+				return Pack{}, err
+			}
+			pending, last = "", -1
+
+		case strings.HasPrefix(trimmed, ";"):
+			comment := strings.TrimSpace(strings.TrimPrefix(trimmed, ";"))
+			if err := closeLevel(); err != nil {
+				return Pack{}, err
+			}
+			if last >= 0 && pack.Levels[last].Title == "" {
+				pack.Levels[last].Title = comment
+			} else {
+				pending = comment
+			}
+
+		default:
+			if err := closeLevel(); err != nil {
+				return Pack{}, err
+			}
+			key, value, ok := strings.Cut(trimmed, ":")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "title") {
+				continue // Author:, Comment: and other metadata
+			}
+			value = strings.TrimSpace(value)
+			if last >= 0 {
+				pack.Levels[last].Title = value
+			} else if len(pack.Levels) == 0 {
+				pack.Name = value
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Pack{}, fmt.Errorf("could not read pack %s: %w", name, err)
+	}
+	if err := closeLevel(); err != nil {
+		return Pack{}, err
+	}
+	if len(pack.Levels) == 0 {
+		return Pack{}, fmt.Errorf("pack %s has no levels", name)
+	}
+	for i := range pack.Levels {
+		if pack.Levels[i].Title == "" {
+			pack.Levels[i].Title = fmt.Sprintf("Level %d", i+1)
+		}
+	}
+	return pack, nil
+}
+
+// isRow tells map rows from text: only map characters, and at least one
+// wall.
+func isRow(line string) bool {
+	return strings.Contains(line, "#") && strings.Trim(line, "#@+$*.-_ ") == ""
+}
+
+func parseLevel(rows []string) (Level, error) {
+	lv := Level{Height: len(rows), Player: Point{-1, -1}}
+	for _, r := range rows {
+		lv.Width = max(lv.Width, len(r))
+	}
+	lv.Tiles = make([]Tile, lv.Width*lv.Height)
+	for y, r := range rows {
+		for x := 0; x < lv.Width; x++ {
+			c := byte(' ')
+			if x < len(r) {
+				c = r[x]
+			}
+			t := Floor
+			switch c {
+			case '#':
+				t = Wall
+			case '.', '*', '+':
+				t = Goal
+			}
+			lv.Tiles[y*lv.Width+x] = t
+			switch c {
+			case '$', '*':
+				lv.Boxes = append(lv.Boxes, Point{x, y})
+			case '@', '+':
+				if lv.Player.X >= 0 {
+					return Level{}, errors.New("more than one keeper")
+				}
+				lv.Player = Point{x, y}
+			}
+		}
+	}
+	if lv.Player.X < 0 {
+		return Level{}, errors.New("no keeper")
+	}
+	if err := lv.seal(); err != nil {
+		return Level{}, err
+	}
+	return lv, nil
+}
+
+// ----------------------------------------------------------------------------------
+// Packs
+// ----------------------------------------------------------------------------------
+
+// Builtin returns the packs shipped with gamics.
+func Builtin() []Pack {
+	entries, err := builtin.ReadDir("levels")
+	if err != nil {
+		panic(err)
+	}
+	var packs []Pack
+	for _, e := range entries {
+		f, err := builtin.Open("levels/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		p, err := ParsePack(strings.TrimSuffix(e.Name(), PackExt), f)
+		f.Close()
+		if err != nil {
+			panic(fmt.Sprintf("built-in sokoban pack %s: %v", e.Name(), err))
+		}
+		p.Builtin = true
+		packs = append(packs, p)
+	}
+	return packs
+}
+
+// LoadDir reads every .xsb pack in dir, sorted by file name. A missing dir
+// has no packs. A pack that can't be read is left out and reported in the
+// error along with the others; the rest still load.
+func LoadDir(dir string) ([]Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list sokoban packs: %w", err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var (
+		packs []Pack
+		errs  []error
+	)
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(path.Ext(e.Name()), PackExt) {
+			continue
+		}
+		f, err := os.Open(path.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not open sokoban pack: %w", err))
+			continue
+		}
+		p, err := ParsePack(strings.TrimSuffix(e.Name(), path.Ext(e.Name())), f)
+		f.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sokoban pack %s: %w", e.Name(), err))
+			continue
+		}
+		packs = append(packs, p)
+	}
+	return packs, errors.Join(errs...)
+}
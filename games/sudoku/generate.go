@@ -0,0 +1,106 @@
+package sudoku
+
+import "math/rand/v2"
+
+// maxAttempts bounds how many full grids Generate carves before settling
+// for the closest puzzle it made.
+const maxAttempts = 400
+
+// Puzzle is a generated game: its givens, the one solution they lead to
+// and the grade of the givens.
+type Puzzle struct {
+	Givens     Grid
+	Solution   Grid
+	Difficulty Difficulty
+	// Technique is the hardest technique solving the givens takes.
+	Technique string
+}
+
+// NewPuzzle grades givens typed in or imported. It fails unless they have
+// exactly one solution.
+func NewPuzzle(givens Grid) (Puzzle, bool) {
+	if !Unique(givens) {
+		return Puzzle{}, false
+	}
+	sol, _ := Solve(givens)
+	d, tech := Grade(givens)
+	return Puzzle{Givens: givens, Solution: sol, Difficulty: d, Technique: tech}, true
+}
+
+// Generate makes a puzzle of difficulty d with a unique solution. It fills
+// a random grid, then empties cells in symmetric pairs as long as the
+// solution stays unique and the puzzle no more than a grade harder than d.
+// A puzzle that came out too hard gets cells back until it grades d.
+// Some grids never reach d, so it tries several and falls back to the
+// hardest puzzle it found below d.
+func Generate(d Difficulty, seed uint64) Puzzle {
+	rng := rand.New(rand.NewPCG(seed, seed^0x9E3779B97F4A7C15))
+	var p, best Puzzle
+	found := false
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		p = carve(randomGrid(rng), min(d+1, Expert), rng)
+		if p.Difficulty > d {
+			p = soften(p, d, rng)
+		}
+		if p.Difficulty == d {
+			return p
+		}
+		if p.Difficulty < d && (!found || p.Difficulty > best.Difficulty) {
+			best, found = p, true
+		}
+	}
+	if !found {
+		return p
+	}
+	return best
+}
+
+// randomGrid returns a random solved grid.
+func randomGrid(rng *rand.Rand) Grid {
+	s, _ := newSearch(Grid{}, 1)
+	s.rng = rng
+	s.run()
+	return s.first
+}
+
+func carve(sol Grid, d Difficulty, rng *rand.Rand) Puzzle {
+	g := sol
+	order := rng.Perm(Cells/2 + 1) // a cell and its mirror through the centre
+	for _, c := range order {
+		mirror := Cells - 1 - c
+		was := g
+		g[c], g[mirror] = 0, 0
+		if !Unique(g) {
+			g = was
+			continue
+		}
+		if grade, _ := Grade(g); grade > d {
+			g = was
+		}
+	}
+	grade, tech := Grade(g)
+	return Puzzle{Givens: g, Solution: sol, Difficulty: grade, Technique: tech}
+}
+
+// soften gives p back pairs of cells from its solution until it grades d.
+// A pair that makes it easier than d is taken back again.
+func soften(p Puzzle, d Difficulty, rng *rand.Rand) Puzzle {
+	g := p.Givens
+	for _, c := range rng.Perm(Cells/2 + 1) {
+		mirror := Cells - 1 - c
+		if g[c] != 0 && g[mirror] != 0 {
+			continue
+		}
+		was := g
+		g[c], g[mirror] = p.Solution[c], p.Solution[mirror]
+		grade, tech := Grade(g)
+		switch {
+		case grade == d:
+			return Puzzle{Givens: g, Solution: p.Solution, Difficulty: grade, Technique: tech}
+		case grade < d:
+			g = was
+		}
+	}
+	grade, tech := Grade(g)
+	return Puzzle{Givens: g, Solution: p.Solution, Difficulty: grade, Technique: tech}
+}
@@ -0,0 +1,517 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Difficulty grades a puzzle by the hardest technique solving it takes.
+type Difficulty int
+
+const (
+	Easy       Difficulty = iota // singles only
+	Medium                       // locked candidates, naked and hidden pairs
+	Hard                         // triples and X-wings
+	Expert                       // swordfish and XY-wings
+	Diabolical                   // beyond every technique known here
+)
+
+var difficultyNames = [...]string{"easy", "medium", "hard", "expert", "diabolical"}
+
+func (d Difficulty) String() string { return difficultyNames[d] }
+
+// Difficulties lists the grades the generator can aim for.
+var Difficulties = []Difficulty{Easy, Medium, Hard, Expert}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Removal takes Digit out of the candidates of Cell.
+type Removal struct{ Cell, Digit int }
+
+// Step is one deduction of the logical solver: a digit placed, or
+// candidates removed, with why in plain words.
+type Step struct {
+	Technique string
+	Level     Difficulty
+	// Cell and Digit are the placement; Cell is -1 for a step that only
+	// removes candidates.
+	Cell, Digit int
+	Removals    []Removal
+	Text        string
+}
+
+// technique looks for one deduction in the current candidates.
+type technique struct {
+	name  string
+	level Difficulty
+	find  func(l *logic) (Step, bool)
+}
+
+// techniques in the order people try them: the easiest that applies is
+// always the next step.
+var techniques = []technique{
+	{"Hidden single", Easy, hiddenSingle},
+	{"Naked single", Easy, nakedSingle},
+	{"Locked candidates", Medium, lockedCandidates},
+	{"Naked pair", Medium, nakedSubset(2)},
+	{"Hidden pair", Medium, hiddenSubset(2)},
+	{"Naked triple", Hard, nakedSubset(3)},
+	{"Hidden triple", Hard, hiddenSubset(3)},
+	{"X-wing", Hard, fish(2)},
+	{"Swordfish", Expert, fish(3)},
+	{"XY-wing", Expert, xyWing},
+}
+
+// logic is a grid being solved by hand: the digits placed and what each
+// empty cell may still hold.
+type logic struct {
+	grid Grid
+	cand [Cells]Digits
+}
+
+func newLogic(g Grid) *logic { return &logic{grid: g, cand: g.Candidates()} }
+
+// ----------------------------------------------------------------------------------
+// Solving
+// ----------------------------------------------------------------------------------
+
+// Hint returns the steps leading from g to its next placed digit: any
+// candidate removals needed first, then the placement itself. It reports
+// false when the techniques known here get stuck before that.
+func Hint(g Grid) ([]Step, bool) {
+	l := newLogic(g)
+	var steps []Step
+	for {
+		st, ok := l.next()
+		if !ok {
+			return steps, false
+		}
+		steps = append(steps, st)
+		l.apply(st)
+		if st.Cell >= 0 {
+			return steps, true
+		}
+	}
+}
+
+// Grade solves g by logic alone and returns its difficulty along with the
+// hardest technique it took. A puzzle the techniques can't finish is
+// Diabolical.
+func Grade(g Grid) (Difficulty, string) {
+	l := newLogic(g)
+	level, hardest := Easy, techniques[0].name
+	for l.grid.Empty() > 0 {
+		st, ok := l.next()
+		if !ok {
+			return Diabolical, "trial and error"
+		}
+		if st.Level > level {
+			level, hardest = st.Level, st.Technique
+		}
+		l.apply(st)
+	}
+	return level, hardest
+}
+
+func (l *logic) next() (Step, bool) {
+	for _, t := range techniques {
+		if st, ok := t.find(l); ok {
+			st.Technique, st.Level = t.name, t.level
+			return st, true
+		}
+	}
+	return Step{}, false
+}
+
+func (l *logic) apply(st Step) {
+	if st.Cell >= 0 {
+		l.place(st.Cell, st.Digit)
+	}
+	for _, r := range st.Removals {
+		l.cand[r.Cell] &^= 1 << r.Digit
+	}
+}
+
+func (l *logic) place(c, n int) {
+	l.grid[c] = n
+	l.cand[c] = 0
+	for _, p := range peers[c] {
+		l.cand[p] &^= 1 << n
+	}
+}
+
+// where lists the cells of unit u that may hold n.
+func (l *logic) where(u, n int) []int {
+	var out []int
+	for _, c := range units[u] {
+		if l.cand[c].Has(n) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// placement is a Step placing n in c.
+func placement(c, n int, text string) Step {
+	return Step{Cell: c, Digit: n, Text: text}
+}
+
+// elimination is a Step removing candidates, its text ending with what it
+// removes.
+func elimination(rs []Removal, text string) Step {
+	return Step{Cell: -1, Removals: rs, Text: text + " (" + describeRemovals(rs) + ")"}
+}
+
+func describeRemovals(rs []Removal) string {
+	byDigit := map[int][]string{}
+	var digits []int
+	for _, r := range rs {
+		if _, ok := byDigit[r.Digit]; !ok {
+			digits = append(digits, r.Digit)
+		}
+		byDigit[r.Digit] = append(byDigit[r.Digit], CellName(r.Cell))
+	}
+	slices.Sort(digits)
+	parts := make([]string, len(digits))
+	for i, n := range digits {
+		parts[i] = fmt.Sprintf("removes %d from %s", n, strings.Join(byDigit[n], ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ----------------------------------------------------------------------------------
+// Techniques
+// ----------------------------------------------------------------------------------
+
+// unitOrder visits the boxes before the rows and columns, where people
+// spot singles first.
+var unitOrder = func() []int {
+	var out []int
+	for u := 2 * Size; u < 3*Size; u++ {
+		out = append(out, u)
+	}
+	for u := 0; u < 2*Size; u++ {
+		out = append(out, u)
+	}
+	return out
+}()
+
+func hiddenSingle(l *logic) (Step, bool) {
+	for _, u := range unitOrder {
+		for n := 1; n <= Size; n++ {
+			if cells := l.where(u, n); len(cells) == 1 {
+				return placement(cells[0], n, fmt.Sprintf("%s is the only place left for %d in %s.", CellName(cells[0]), n, unitName(u))), true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+func nakedSingle(l *logic) (Step, bool) {
+	for c, d := range l.cand {
+		if d.Count() == 1 {
+			return placement(c, d.Lowest(), fmt.Sprintf("%s can only be %d: every other digit is already in its row, column or box.", CellName(c), d.Lowest())), true
+		}
+	}
+	return Step{}, false
+}
+
+// lockedCandidates finds a digit confined to one line within a box, which
+// leaves the rest of that line, or to one box within a line, which leaves
+// the rest of that box.
+func lockedCandidates(l *logic) (Step, bool) {
+	for u := 0; u < 3*Size; u++ {
+		for n := 1; n <= Size; n++ {
+			cells := l.where(u, n)
+			if len(cells) < 2 {
+				continue
+			}
+			// The other units all of cells share.
+			var shared []int
+			for _, v := range cellUnits[cells[0]] {
+				if v == u {
+					continue
+				}
+				all := true
+				for _, c := range cells[1:] {
+					all = all && slices.Contains(cellUnits[c][:], v)
+				}
+				if all {
+					shared = append(shared, v)
+				}
+			}
+			for _, v := range shared {
+				var rs []Removal
+				for _, c := range units[v] {
+					if l.cand[c].Has(n) && !slices.Contains(cells, c) {
+						rs = append(rs, Removal{c, n})
+					}
+				}
+				if len(rs) > 0 {
+					text := fmt.Sprintf("In %s, %d can only go in %s, so it can't go anywhere else in %s", unitName(u), n, unitName(v), unitName(v))
+					return elimination(rs, text), true
+				}
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// nakedSubset finds size cells of a unit holding only size digits between
+// them: those digits leave every other cell of the unit.
+func nakedSubset(size int) func(l *logic) (Step, bool) {
+	return func(l *logic) (Step, bool) {
+		for u := 0; u < 3*Size; u++ {
+			var open []int
+			for _, c := range units[u] {
+				if n := l.cand[c].Count(); n >= 2 && n <= size {
+					open = append(open, c)
+				}
+			}
+			var found Step
+			ok := combinations(len(open), size, func(idx []int) bool {
+				var union Digits
+				cells := make([]int, size)
+				for i, k := range idx {
+					cells[i] = open[k]
+					union |= l.cand[open[k]]
+				}
+				if union.Count() != size {
+					return false
+				}
+				var rs []Removal
+				for _, c := range units[u] {
+					if slices.Contains(cells, c) {
+						continue
+					}
+					for _, n := range (l.cand[c] & union).List() {
+						rs = append(rs, Removal{c, n})
+					}
+				}
+				if len(rs) == 0 {
+					return false
+				}
+				text := fmt.Sprintf("%s hold %s between them in %s, so no other cell of %s can", cellList(cells), digitList(union.List()), unitName(u), unitName(u))
+				found = elimination(rs, text)
+				return true
+			})
+			if ok {
+				return found, true
+			}
+		}
+		return Step{}, false
+	}
+}
+
+// hiddenSubset finds size digits that fit only size cells of a unit: those
+// cells hold nothing else.
+func hiddenSubset(size int) func(l *logic) (Step, bool) {
+	return func(l *logic) (Step, bool) {
+		for u := 0; u < 3*Size; u++ {
+			var digits []int
+			for n := 1; n <= Size; n++ {
+				if k := len(l.where(u, n)); k >= 2 && k <= size {
+					digits = append(digits, n)
+				}
+			}
+			var found Step
+			ok := combinations(len(digits), size, func(idx []int) bool {
+				var set Digits
+				var cells []int
+				for _, k := range idx {
+					set |= 1 << digits[k]
+					for _, c := range l.where(u, digits[k]) {
+						if !slices.Contains(cells, c) {
+							cells = append(cells, c)
+						}
+					}
+				}
+				if len(cells) != size {
+					return false
+				}
+				slices.Sort(cells)
+				var rs []Removal
+				for _, c := range cells {
+					for _, n := range (l.cand[c] &^ set).List() {
+						rs = append(rs, Removal{c, n})
+					}
+				}
+				if len(rs) == 0 {
+					return false
+				}
+				text := fmt.Sprintf("In %s, %s only fit %s, so those cells hold nothing else", unitName(u), digitList(set.List()), cellList(cells))
+				found = elimination(rs, text)
+				return true
+			})
+			if ok {
+				return found, true
+			}
+		}
+		return Step{}, false
+	}
+}
+
+// fish finds a digit that, in size rows, fits only the same size columns:
+// one of those rows has it in each column, so it leaves the rest of those
+// columns. The same goes with rows and columns swapped. Size 2 is the
+// X-wing, size 3 the swordfish.
+func fish(size int) func(l *logic) (Step, bool) {
+	return func(l *logic) (Step, bool) {
+		for n := 1; n <= Size; n++ {
+			for _, base := range []int{0, Size} { // rows, then columns
+				cover := Size - base
+				var lines []int
+				for u := base; u < base+Size; u++ {
+					if k := len(l.where(u, n)); k >= 2 && k <= size {
+						lines = append(lines, u)
+					}
+				}
+				var found Step
+				ok := combinations(len(lines), size, func(idx []int) bool {
+					chosen := make([]int, size)
+					var covers []int
+					for i, k := range idx {
+						chosen[i] = lines[k]
+						for _, c := range l.where(lines[k], n) {
+							v := cellUnits[c][cover/Size]
+							if !slices.Contains(covers, v) {
+								covers = append(covers, v)
+							}
+						}
+					}
+					if len(covers) != size {
+						return false
+					}
+					slices.Sort(covers)
+					var rs []Removal
+					for _, v := range covers {
+						for _, c := range l.where(v, n) {
+							if !slices.Contains(chosen, cellUnits[c][base/Size]) {
+								rs = append(rs, Removal{c, n})
+							}
+						}
+					}
+					if len(rs) == 0 {
+						return false
+					}
+					text := fmt.Sprintf("In %s, %d only fits %s, so it can't go anywhere else in them", unitList(chosen), n, unitList(covers))
+					found = elimination(rs, text)
+					return true
+				})
+				if ok {
+					return found, true
+				}
+			}
+		}
+		return Step{}, false
+	}
+}
+
+// xyWing finds a pivot cell that is x or y, seeing one cell that is x or z
+// and another that is y or z: whichever the pivot is, one of the two is z,
+// so no cell seeing both of them can be.
+func xyWing(l *logic) (Step, bool) {
+	for p, pc := range l.cand {
+		if pc.Count() != 2 {
+			continue
+		}
+		for _, a := range peers[p] {
+			ac := l.cand[a]
+			if ac.Count() != 2 || ac == pc || (ac&pc).Count() != 1 {
+				continue
+			}
+			z := (ac &^ pc).Lowest()
+			want := (pc &^ ac) | 1<<z // the other pincer is y or z
+			for _, b := range peers[p] {
+				if b == a || l.cand[b] != want {
+					continue
+				}
+				var rs []Removal
+				for c := range l.cand {
+					if c != p && l.cand[c].Has(z) && Sees(c, a) && Sees(c, b) {
+						rs = append(rs, Removal{c, z})
+					}
+				}
+				if len(rs) == 0 {
+					continue
+				}
+				x, y := (pc & ac).Lowest(), (pc &^ ac).Lowest()
+				text := fmt.Sprintf("%s is %d or %d; if %d, %s is %d, and if %d, %s is %d. Either way a cell seeing both can't be %d",
+					CellName(p), x, y, x, CellName(a), z, y, CellName(b), z, z)
+				return elimination(rs, text), true
+			}
+		}
+	}
+	return Step{}, false
+}
+
+// ----------------------------------------------------------------------------------
+// Helpers
+// ----------------------------------------------------------------------------------
+
+// combinations calls fn with every k-subset of 0..n-1, in order, until fn
+// returns true; it reports whether one did.
+func combinations(n, k int, fn func(idx []int) bool) bool {
+	if k > n {
+		return false
+	}
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		if fn(idx) {
+			return true
+		}
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return false
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+func cellList(cells []int) string {
+	names := make([]string, len(cells))
+	for i, c := range cells {
+		names[i] = CellName(c)
+	}
+	return joinAnd(names)
+}
+
+func digitList(digits []int) string {
+	names := make([]string, len(digits))
+	for i, n := range digits {
+		names[i] = fmt.Sprint(n)
+	}
+	return joinAnd(names)
+}
+
+// unitList names lines of one kind together, "rows 2 and 7".
+func unitList(us []int) string {
+	kind, base := "rows", 0
+	if us[0] >= Size {
+		kind, base = "columns", Size
+	}
+	names := make([]string, len(us))
+	for i, u := range us {
+		names[i] = fmt.Sprint(u - base + 1)
+	}
+	return kind + " " + joinAnd(names)
+}
+
+func joinAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
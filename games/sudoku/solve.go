@@ -0,0 +1,103 @@
+package sudoku
+
+import "math/rand/v2"
+
+// ----------------------------------------------------------------------------------
+// Backtracking solver
+// ----------------------------------------------------------------------------------
+
+// search is a depth-first search over the empty cells, always branching on
+// the cell with the fewest candidates.
+type search struct {
+	grid              Grid
+	rows, cols, boxes [Size]Digits // digits already placed in each unit
+	found             int
+	limit             int
+	first             Grid
+	rng               *rand.Rand // shuffles the digits tried, when set
+}
+
+func newSearch(g Grid, limit int) (*search, bool) {
+	s := &search{grid: g, limit: limit}
+	for c, v := range g {
+		if v == 0 {
+			continue
+		}
+		bit := Digits(1) << v
+		if (s.rows[Row(c)]|s.cols[Col(c)]|s.boxes[Box(c)])&bit != 0 {
+			return nil, false
+		}
+		s.rows[Row(c)] |= bit
+		s.cols[Col(c)] |= bit
+		s.boxes[Box(c)] |= bit
+	}
+	return s, true
+}
+
+// Count returns how many solutions g has, stopping at limit.
+func Count(g Grid, limit int) int {
+	s, ok := newSearch(g, limit)
+	if !ok {
+		return 0
+	}
+	s.run()
+	return s.found
+}
+
+// Solve returns a solution of g, the first one found when there are
+// several.
+func Solve(g Grid) (Grid, bool) {
+	s, ok := newSearch(g, 1)
+	if !ok {
+		return Grid{}, false
+	}
+	s.run()
+	return s.first, s.found > 0
+}
+
+// Unique reports whether g has exactly one solution.
+func Unique(g Grid) bool { return Count(g, 2) == 1 }
+
+func (s *search) run() {
+	best, bestCand := -1, Digits(0)
+	for c, v := range s.grid {
+		if v != 0 {
+			continue
+		}
+		cand := AllDigits &^ (s.rows[Row(c)] | s.cols[Col(c)] | s.boxes[Box(c)])
+		if best < 0 || cand.Count() < bestCand.Count() {
+			best, bestCand = c, cand
+			if cand.Count() <= 1 {
+				break
+			}
+		}
+	}
+	if best < 0 {
+		if s.found == 0 {
+			s.first = s.grid
+		}
+		s.found++
+		return
+	}
+
+	digits := bestCand.List()
+	if s.rng != nil {
+		s.rng.Shuffle(len(digits), func(i, j int) { digits[i], digits[j] = digits[j], digits[i] })
+	}
+	r, col, b := Row(best), Col(best), Box(best)
+	for _, n := range digits {
+		bit := Digits(1) << n
+		s.grid[best] = n
+		s.rows[r] |= bit
+		s.cols[col] |= bit
+		s.boxes[b] |= bit
+		s.run()
+		s.rows[r] &^= bit
+		s.cols[col] &^= bit
+		s.boxes[b] &^= bit
+		s.grid[best] = 0
+		if s.found >= s.limit {
+			return
+		}
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Package sudoku is the puzzle engine of Sudoku, free of any UI: grids and
+their 81-character form, a backtracking solver that counts solutions, a
+logical solver that works the way people do and explains each step, the
+difficulty grading built on it, and a generator of puzzles with a unique
+solution.
+*/
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+const (
+	Size  = 9
+	Cells = Size * Size
+)
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Grid holds the digits of a board row by row, 0 for an empty cell.
+type Grid [Cells]int
+
+// Digits is a set of digits 1 to 9, bit n standing for digit n.
+type Digits uint16
+
+// AllDigits is every digit.
+const AllDigits Digits = 0x3FE
+
+// ----------------------------------------------------------------------------------
+// Units and peers
+// ----------------------------------------------------------------------------------
+
+// A unit is a row, a column or a box: units 0-8 are the rows, 9-17 the
+// columns and 18-26 the boxes.
+var (
+	units     [27][Size]int
+	cellUnits [Cells][3]int
+	peers     [Cells][20]int
+)
+
+func init() {
+	for i := 0; i < Size; i++ {
+		for j := 0; j < Size; j++ {
+			units[i][j] = i*Size + j
+			units[Size+i][j] = j*Size + i
+			units[2*Size+i][j] = (i/3*3+j/3)*Size + i%3*3 + j%3
+		}
+	}
+	for c := 0; c < Cells; c++ {
+		cellUnits[c] = [3]int{Row(c), Size + Col(c), 2*Size + Box(c)}
+		n := 0
+		for p := 0; p < Cells; p++ {
+			if p != c && (Row(p) == Row(c) || Col(p) == Col(c) || Box(p) == Box(c)) {
+				peers[c][n] = p
+				n++
+			}
+		}
+	}
+}
+
+// Row, Col and Box number the row, column and box of cell c from 0.
+func Row(c int) int { return c / Size }
+func Col(c int) int { return c % Size }
+func Box(c int) int { return Row(c)/3*3 + Col(c)/3 }
+
+// CellName names a cell the way players write it, "r3c7".
+func CellName(c int) string { return fmt.Sprintf("r%dc%d", Row(c)+1, Col(c)+1) }
+
+func unitName(u int) string {
+	switch {
+	case u < Size:
+		return fmt.Sprintf("row %d", u+1)
+	case u < 2*Size:
+		return fmt.Sprintf("column %d", u-Size+1)
+	default:
+		return fmt.Sprintf("box %d", u-2*Size+1)
+	}
+}
+
+// Sees reports whether cells a and b share a unit.
+func Sees(a, b int) bool {
+	return a != b && (Row(a) == Row(b) || Col(a) == Col(b) || Box(a) == Box(b))
+}
+
+// ----------------------------------------------------------------------------------
+// Digits
+// ----------------------------------------------------------------------------------
+
+// Has reports whether digit n is in the set.
+func (d Digits) Has(n int) bool { return d&(1<<n) != 0 }
+
+// Count is the size of the set.
+func (d Digits) Count() int { return bits.OnesCount16(uint16(d)) }
+
+// Lowest is the smallest digit in the set, 0 when empty.
+func (d Digits) Lowest() int {
+	if d == 0 {
+		return 0
+	}
+	return bits.TrailingZeros16(uint16(d))
+}
+
+// List returns the digits in ascending order.
+func (d Digits) List() []int {
+	var out []int
+	for n := 1; n <= Size; n++ {
+		if d.Has(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (d Digits) String() string {
+	var b strings.Builder
+	for _, n := range d.List() {
+		b.WriteByte(byte('0' + n))
+	}
+	return b.String()
+}
+
+// ----------------------------------------------------------------------------------
+// Grids
+// ----------------------------------------------------------------------------------
+
+// Parse reads a grid in its 81-character form: digits row by row, with '.'
+// or '0' for empty cells. Whitespace is ignored, so a grid pasted as nine
+// lines reads the same.
+func Parse(s string) (Grid, error) {
+	var g Grid
+	n := 0
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if n == Cells {
+			return Grid{}, fmt.Errorf("puzzle has more than %d cells", Cells)
+		}
+		switch {
+		case r >= '1' && r <= '9':
+			g[n] = int(r - '0')
+		case r == '.' || r == '0':
+		default:
+			return Grid{}, fmt.Errorf("puzzle cell %d is %q; use 1-9, or . or 0 for empty", n+1, r)
+		}
+		n++
+	}
+	if n != Cells {
+		return Grid{}, fmt.Errorf("puzzle has %d cells, want %d", n, Cells)
+	}
+	for c, bad := range g.Conflicts() {
+		if bad {
+			return Grid{}, fmt.Errorf("puzzle repeats %d around %s", g[c], CellName(c))
+		}
+	}
+	return g, nil
+}
+
+// String is the 81-character form of g, '.' for empty cells.
+func (g Grid) String() string {
+	b := make([]byte, Cells)
+	for c, v := range g {
+		b[c] = '.'
+		if v != 0 {
+			b[c] = byte('0' + v)
+		}
+	}
+	return string(b)
+}
+
+// Empty counts the empty cells.
+func (g Grid) Empty() int {
+	n := 0
+	for _, v := range g {
+		if v == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Conflicts marks the cells whose digit repeats in one of their units.
+func (g Grid) Conflicts() [Cells]bool {
+	var out [Cells]bool
+	for c, v := range g {
+		if v == 0 {
+			continue
+		}
+		for _, p := range peers[c] {
+			if g[p] == v {
+				out[c] = true
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Solved reports whether g is full and breaks no rule.
+func (g Grid) Solved() bool {
+	if g.Empty() > 0 {
+		return false
+	}
+	for _, bad := range g.Conflicts() {
+		if bad {
+			return false
+		}
+	}
+	return true
+}
+
+// Candidates lists, for each empty cell, the digits none of its peers
+// holds. Filled cells have none.
+func (g Grid) Candidates() [Cells]Digits {
+	var out [Cells]Digits
+	for c, v := range g {
+		if v != 0 {
+			continue
+		}
+		d := AllDigits
+		for _, p := range peers[c] {
+			d &^= 1 << g[p]
+		}
+		out[c] = d
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package tetris
+
+// Kind is a tetromino, or None for an empty cell.
+type Kind byte
+
+const (
+	None Kind = iota
+	I
+	O
+	T
+	S
+	Z
+	J
+	L
+)
+
+// Kinds lists the seven tetrominoes, the contents of one bag.
+var Kinds = []Kind{I, O, T, S, Z, J, L}
+
+func (k Kind) String() string { return " IOTSZJL"[k : k+1] }
+
+// Point is a cell, row 0 being the top of the hidden rows.
+type Point struct{ X, Y int }
+
+// shape is a tetromino in its spawn orientation, within a size×size box.
+type shape struct {
+	size  int
+	cells [4]Point
+}
+
+var shapes = [...]shape{
+	I: {4, [4]Point{{0, 1}, {1, 1}, {2, 1}, {3, 1}}},
+	O: {2, [4]Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}}},
+	T: {3, [4]Point{{1, 0}, {0, 1}, {1, 1}, {2, 1}}},
+	S: {3, [4]Point{{1, 0}, {2, 0}, {0, 1}, {1, 1}}},
+	Z: {3, [4]Point{{0, 0}, {1, 0}, {1, 1}, {2, 1}}},
+	J: {3, [4]Point{{0, 0}, {0, 1}, {1, 1}, {2, 1}}},
+	L: {3, [4]Point{{2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+}
+
+// Piece is a tetromino on the board: its box's top left corner is at X, Y
+// and it is turned Rot quarter turns clockwise from spawn.
+type Piece struct {
+	Kind Kind
+	Rot  int
+	X, Y int
+}
+
+// Cells returns the board cells the piece covers. Rotating inside the box
+// is exactly how the Super Rotation System turns pieces before kicks.
+func (p Piece) Cells() [4]Point {
+	sh := shapes[p.Kind]
+	var out [4]Point
+	for i, c := range sh.cells {
+		for r := 0; r < p.Rot; r++ {
+			c = Point{sh.size - 1 - c.Y, c.X}
+		}
+		out[i] = Point{p.X + c.X, p.Y + c.Y}
+	}
+	return out
+}
+
+// Shape returns the cells of k at spawn, relative to its box, for drawing
+// the hold slot and the queue.
+func Shape(k Kind) [4]Point { return shapes[k].cells }
+
+// kicks are the SRS wall kick tests for turning from one orientation to
+// the next, indexed [from][to], with y growing downwards. The first test is
+// the plain rotation.
+var (
+	kicksJLSTZ = [4][4][]Point{
+		0: {1: {{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}}, 3: {{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}}},
+		1: {0: {{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}}, 2: {{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}}},
+		2: {1: {{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}}, 3: {{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}}},
+		3: {2: {{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}, 0: {{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}},
+	}
+	kicksI = [4][4][]Point{
+		0: {1: {{0, 0}, {-2, 0}, {1, 0}, {-2, 1}, {1, -2}}, 3: {{0, 0}, {-1, 0}, {2, 0}, {-1, -2}, {2, 1}}},
+		1: {0: {{0, 0}, {2, 0}, {-1, 0}, {2, -1}, {-1, 2}}, 2: {{0, 0}, {-1, 0}, {2, 0}, {-1, -2}, {2, 1}}},
+		2: {1: {{0, 0}, {1, 0}, {-2, 0}, {1, 2}, {-2, -1}}, 3: {{0, 0}, {2, 0}, {-1, 0}, {2, -1}, {-1, 2}}},
+		3: {2: {{0, 0}, {-2, 0}, {1, 0}, {-2, 1}, {1, -2}}, 0: {{0, 0}, {1, 0}, {-2, 0}, {1, 2}, {-2, -1}}},
+	}
+)
+
+func kicks(k Kind, from, to int) []Point {
+	switch k {
+	case O:
+		return []Point{{0, 0}}
+	case I:
+		return kicksI[from][to]
+	default:
+		return kicksJLSTZ[from][to]
+	}
+}
@@ -0,0 +1,460 @@
+/*
+Package tetris is a guideline Tetris engine, free of any UI: a 7-bag
+randomizer, the Super Rotation System with wall kicks, hold, lock delay and
+level-based gravity, in three modes.
+
+Like the snake engine it keeps game time: Advance moves the game forward by
+a duration and the player's actions go through Apply, both returning a new
+State. Gravity, lock delay and the mode clocks only run inside Advance, so a
+paused game stands still.
+*/
+package tetris
+
+import (
+	"fmt"
+	"gamics/internal/rng"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	Cols = 10
+	// Rows counts the Hidden rows above the field where pieces spawn.
+	Rows    = 22
+	Hidden  = 2
+	Visible = Rows - Hidden
+
+	LockDelay  = 500 * time.Millisecond
+	MaxResets  = 15 // moves and turns that restart the lock delay of one piece
+	QueueShown = 5  // pieces of the queue a player gets to see
+)
+
+// Modes and their goals.
+const (
+	Marathon = "marathon" // climb the levels up to MarathonLines
+	Sprint   = "sprint"   // clear SprintLines as fast as possible
+	Ultra    = "ultra"    // score as much as possible in UltraTime
+
+	MarathonLines = 150
+	SprintLines   = 40
+	UltraTime     = 2 * time.Minute
+)
+
+// Modes lists every mode in menu order.
+var Modes = []string{Marathon, Sprint, Ultra}
+
+// Action is one player input.
+type Action int
+
+const (
+	MoveLeft Action = iota
+	MoveRight
+	RotateCW
+	RotateCCW
+	SoftDrop
+	HardDrop
+	Hold
+)
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Clear describes what the last locked piece scored.
+type Clear struct {
+	Name   string // "Tetris", "T-Spin Double"... empty for nothing special
+	Lines  int
+	Points int
+	Combo  int
+}
+
+// State is a game of Tetris.
+type State struct {
+	Mode  string
+	Board [Rows][Cols]Kind
+	Piece Piece
+	Queue []Kind
+	Held  Kind
+	// HoldUsed is set once the piece in play came out of, or went into,
+	// the hold slot; it can't be held again.
+	HoldUsed bool
+	Rand     uint64
+
+	Level, Lines, Score int
+	Elapsed             time.Duration // game time played
+
+	Over bool
+	// Done is set when the game ended by reaching the mode's goal rather
+	// than topping out.
+	Done   bool
+	Reason string
+
+	Last  Clear // what the last lock scored
+	Locks int   // pieces locked so far; tells a fresh Last apart
+
+	fallLeft   time.Duration // until gravity pulls the piece one row down
+	lockLeft   time.Duration // until the grounded piece locks
+	resets     int
+	lowest     int  // lowest row the piece reached, for lock delay resets
+	lastRotate bool // the last successful input was a turn, for T-spins
+	streak     int  // consecutive locks that cleared lines
+	b2b        bool // the last clear was a Tetris or a T-spin
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New starts a game of mode. The seed fixes every piece it deals.
+func New(mode string, seed uint64) State {
+	s := State{Mode: mode, Level: 1, Rand: seed}
+	s.fill()
+	s.spawn(s.pop())
+	return s
+}
+
+// ----------------------------------------------------------------------------------
+// Queries
+// ----------------------------------------------------------------------------------
+
+// Next returns the next n pieces of the queue.
+func (s State) Next(n int) []Kind { return s.Queue[:min(n, len(s.Queue))] }
+
+// Ghost is where the piece would land if hard dropped.
+func (s State) Ghost() Piece {
+	p := s.Piece
+	for s.fits(Piece{p.Kind, p.Rot, p.X, p.Y + 1}) {
+		p.Y++
+	}
+	return p
+}
+
+// Gravity is how long a piece takes to fall one row at level, following the
+// guideline curve.
+func Gravity(level int) time.Duration {
+	secs := math.Pow(0.8-float64(level-1)*0.007, float64(level-1))
+	return time.Duration(secs * float64(time.Second))
+}
+
+// TimeLeft is what is left of an Ultra game's clock.
+func (s State) TimeLeft() time.Duration {
+	return max(UltraTime-s.Elapsed, 0)
+}
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Apply plays one input. Inputs that cannot be played, like moving into a
+// wall, change nothing.
+func (s State) Apply(a Action) State {
+	if s.Over {
+		return s
+	}
+	s.Queue = slices.Clone(s.Queue)
+	switch a {
+	case MoveLeft:
+		s.shift(-1)
+	case MoveRight:
+		s.shift(1)
+	case RotateCW:
+		s.rotate(1)
+	case RotateCCW:
+		s.rotate(3)
+	case SoftDrop:
+		if s.drop() {
+			s.Score++
+			s.fallLeft = Gravity(s.Level)
+		}
+	case HardDrop:
+		for s.drop() {
+			s.Score += 2
+		}
+		s.lock()
+	case Hold:
+		if s.HoldUsed {
+			break
+		}
+		held := s.Held
+		s.Held = s.Piece.Kind
+		if held == None {
+			held = s.pop()
+		}
+		s.spawn(held)
+		s.HoldUsed = true
+	}
+	return s
+}
+
+// Advance runs gravity, lock delay and the mode clock for d of game time.
+func (s State) Advance(d time.Duration) State {
+	if s.Over {
+		return s
+	}
+	s.Queue = slices.Clone(s.Queue)
+	s.Elapsed += d
+	for d > 0 && !s.Over {
+		if s.grounded() {
+			if d < s.lockLeft {
+				s.lockLeft -= d
+				break
+			}
+			d -= s.lockLeft
+			s.lock()
+			continue
+		}
+		if d < s.fallLeft {
+			s.fallLeft -= d
+			break
+		}
+		d -= s.fallLeft
+		s.drop()
+		s.fallLeft = Gravity(s.Level)
+	}
+	if s.Mode == Ultra && !s.Over && s.Elapsed >= UltraTime {
+		s.end(true, "time's up")
+	}
+	return s
+}
+
+func (s *State) shift(dx int) {
+	p := s.Piece
+	p.X += dx
+	if s.fits(p) {
+		s.Piece = p
+		s.lastRotate = false
+		s.moved()
+	}
+}
+
+// rotate turns the piece by quarter turns clockwise, trying the SRS kicks
+// in order.
+func (s *State) rotate(turns int) {
+	from := s.Piece.Rot
+	to := (from + turns) % 4
+	for _, k := range kicks(s.Piece.Kind, from, to) {
+		p := Piece{s.Piece.Kind, to, s.Piece.X + k.X, s.Piece.Y + k.Y}
+		if s.fits(p) {
+			s.Piece = p
+			s.lastRotate = true
+			s.moved()
+			return
+		}
+	}
+}
+
+// moved restarts the lock delay of a grounded piece, a limited number of
+// times, and notices when the piece reached a new lowest row.
+func (s *State) moved() {
+	if s.Piece.Y > s.lowest {
+		s.lowest, s.resets = s.Piece.Y, 0
+		s.lockLeft = LockDelay
+		return
+	}
+	if s.resets < MaxResets {
+		s.resets++
+		s.lockLeft = LockDelay
+	}
+}
+
+// drop moves the piece one row down if it can.
+func (s *State) drop() bool {
+	p := s.Piece
+	p.Y++
+	if !s.fits(p) {
+		return false
+	}
+	s.Piece = p
+	s.lastRotate = false
+	if p.Y > s.lowest {
+		s.lowest, s.resets = p.Y, 0
+		s.lockLeft = LockDelay
+	}
+	return true
+}
+
+func (s State) grounded() bool {
+	p := s.Piece
+	p.Y++
+	return !s.fits(p)
+}
+
+func (s State) fits(p Piece) bool {
+	for _, c := range p.Cells() {
+		if c.X < 0 || c.X >= Cols || c.Y < 0 || c.Y >= Rows || s.Board[c.Y][c.X] != None {
+			return false
+		}
+	}
+	return true
+}
+
+// lock sets the piece into the board, clears lines, scores them and deals
+// the next piece.
+func (s *State) lock() {
+	tspin := s.isTSpin()
+	above := true
+	for _, c := range s.Piece.Cells() {
+		s.Board[c.Y][c.X] = s.Piece.Kind
+		if c.Y >= Hidden {
+			above = false
+		}
+	}
+	s.Locks++
+	if above {
+		s.end(false, "locked out above the field")
+		return
+	}
+
+	lines := s.clearLines()
+	s.score(lines, tspin)
+	s.Lines += lines
+	if s.Mode == Marathon {
+		s.Level = 1 + s.Lines/10
+	}
+
+	switch {
+	case s.Mode == Sprint && s.Lines >= SprintLines:
+		s.end(true, fmt.Sprintf("%d lines cleared", SprintLines))
+	case s.Mode == Marathon && s.Lines >= MarathonLines:
+		s.end(true, "marathon complete")
+	default:
+		s.spawn(s.pop())
+	}
+}
+
+// isTSpin applies the three-corner rule: a T that last turned into place
+// with three of the four cells diagonal to its centre blocked.
+func (s State) isTSpin() bool {
+	if s.Piece.Kind != T || !s.lastRotate {
+		return false
+	}
+	cx, cy := s.Piece.X+1, s.Piece.Y+1
+	blocked := 0
+	for _, d := range []Point{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		x, y := cx+d.X, cy+d.Y
+		if x < 0 || x >= Cols || y < 0 || y >= Rows || s.Board[y][x] != None {
+			blocked++
+		}
+	}
+	return blocked >= 3
+}
+
+func (s *State) clearLines() int {
+	n := 0
+	for y := Rows - 1; y >= 0; y-- {
+		full := true
+		for x := 0; x < Cols; x++ {
+			if s.Board[y][x] == None {
+				full = false
+				break
+			}
+		}
+		if !full {
+			if n > 0 {
+				s.Board[y+n] = s.Board[y]
+			}
+			continue
+		}
+		n++
+	}
+	for y := 0; y < n; y++ {
+		s.Board[y] = [Cols]Kind{}
+	}
+	return n
+}
+
+var (
+	linePoints  = []int{0, 100, 300, 500, 800}
+	tspinPoints = []int{400, 800, 1200, 1600}
+	lineNames   = []string{"", "Single", "Double", "Triple", "Tetris"}
+)
+
+// score awards a lock by the guideline table: Tetrises and T-spin clears
+// are difficult, and back to back difficult clears earn half again.
+// Consecutive clears add a combo bonus.
+func (s *State) score(lines int, tspin bool) {
+	c := Clear{Lines: lines}
+	base := linePoints[lines]
+	if tspin {
+		base = tspinPoints[lines]
+		c.Name = strings.TrimSpace("T-Spin " + lineNames[lines])
+	} else if lines > 0 {
+		c.Name = lineNames[lines]
+	}
+
+	if lines == 0 {
+		s.streak = 0
+		c.Points = base * s.Level
+		s.Score += c.Points
+		s.Last = c
+		return
+	}
+
+	difficult := tspin || lines == 4
+	points := base * s.Level
+	if difficult && s.b2b {
+		points = points * 3 / 2
+		c.Name = "Back-to-Back " + c.Name
+	}
+	s.b2b = difficult
+	s.streak++
+	if c.Combo = s.streak - 1; c.Combo > 0 {
+		points += 50 * c.Combo * s.Level
+	}
+	c.Points = points
+	s.Score += points
+	s.Last = c
+}
+
+// spawn puts k at the top of the field and lets it fall one row, as the
+// guideline does. A piece that cannot appear ends the game.
+func (s *State) spawn(k Kind) {
+	x := 3
+	if k == O {
+		x = 4
+	}
+	s.Piece = Piece{Kind: k, X: x}
+	s.HoldUsed = false
+	s.lastRotate = false
+	if !s.fits(s.Piece) {
+		s.end(false, "blocked out")
+		return
+	}
+	s.lowest, s.resets = s.Piece.Y, 0
+	s.lockLeft = LockDelay
+	s.fallLeft = Gravity(s.Level)
+	s.drop()
+}
+
+func (s *State) end(done bool, reason string) {
+	s.Over, s.Done, s.Reason = true, done, reason
+}
+
+// ----------------------------------------------------------------------------------
+// Randomizer
+// ----------------------------------------------------------------------------------
+
+// pop takes the next piece of the queue.
+func (s *State) pop() Kind {
+	k := s.Queue[0]
+	s.Queue = s.Queue[1:]
+	s.fill()
+	return k
+}
+
+// fill deals whole shuffled bags of the seven pieces until the queue shows
+// at least a bag ahead.
+func (s *State) fill() {
+	for len(s.Queue) < len(Kinds) {
+		bag := slices.Clone(Kinds)
+		for i := len(bag) - 1; i > 0; i-- {
+			j := s.intn(i + 1)
+			bag[i], bag[j] = bag[j], bag[i]
+		}
+		s.Queue = append(s.Queue, bag...)
+	}
+}
+
+func (s *State) intn(n int) int { return rng.Intn(&s.Rand, n) }
@@ -0,0 +1,214 @@
+package tetris
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRotationsAreQuarterTurns(t *testing.T) {
+	for _, k := range Kinds {
+		spawn := Piece{Kind: k}.Cells()
+		for rot := range 4 {
+			cells := Piece{Kind: k, Rot: rot}.Cells()
+			size := shapes[k].size
+			for _, c := range cells {
+				if c.X < 0 || c.X >= size || c.Y < 0 || c.Y >= size {
+					t.Errorf("%s turned %d times leaves its box: %v", k, rot, cells)
+				}
+			}
+			if k == O && !sameCells(cells, spawn) {
+				t.Errorf("O turned %d times moved: %v", rot, cells)
+			}
+		}
+	}
+	// The T points up, right, down and left in turn.
+	want := [4][4]Point{
+		{{1, 0}, {0, 1}, {1, 1}, {2, 1}},
+		{{1, 0}, {1, 1}, {2, 1}, {1, 2}},
+		{{0, 1}, {1, 1}, {2, 1}, {1, 2}},
+		{{1, 0}, {0, 1}, {1, 1}, {1, 2}},
+	}
+	for rot, w := range want {
+		if got := (Piece{Kind: T, Rot: rot}).Cells(); !sameCells(got, w) {
+			t.Errorf("T turned %d times = %v, want %v", rot, got, w)
+		}
+	}
+}
+
+func sameCells(a, b [4]Point) bool {
+	less := func(p, q Point) int { return (p.Y-q.Y)*Cols + p.X - q.X }
+	slices.SortFunc(a[:], less)
+	slices.SortFunc(b[:], less)
+	return a == b
+}
+
+func TestSevenBag(t *testing.T) {
+	s := New(Marathon, 42)
+	dealt := []Kind{s.Piece.Kind}
+	for len(dealt) < 4*len(Kinds) {
+		dealt = append(dealt, s.pop())
+	}
+	for i := 0; i < len(dealt); i += len(Kinds) {
+		bag := slices.Clone(dealt[i : i+len(Kinds)])
+		slices.Sort(bag)
+		if !slices.Equal(bag, Kinds) {
+			t.Errorf("bag %d = %v, want one of each piece", i/len(Kinds), dealt[i:i+len(Kinds)])
+		}
+	}
+	if a, b := New(Marathon, 42), New(Marathon, 42); a.Piece != b.Piece || !slices.Equal(a.Queue, b.Queue) {
+		t.Error("the same seed dealt different pieces")
+	}
+}
+
+// empty returns a game with nothing on the board and piece p in play.
+func empty(p Piece) State {
+	s := New(Marathon, 1)
+	s.Board = [Rows][Cols]Kind{}
+	s.Piece = p
+	return s
+}
+
+// fillRow sets the cells of row y, but for the columns in gaps.
+func (s *State) fillRow(y int, gaps ...int) {
+	for x := range Cols {
+		if !slices.Contains(gaps, x) {
+			s.Board[y][x] = Z
+		}
+	}
+}
+
+func TestWallKicks(t *testing.T) {
+	tests := []struct {
+		name  string
+		piece Piece
+		turn  Action
+		want  Piece
+	}{
+		{
+			name: "T off the left wall", turn: RotateCW,
+			piece: Piece{Kind: T, Rot: 1, X: -1, Y: 10},
+			want:  Piece{Kind: T, Rot: 2, X: 0, Y: 10},
+		},
+		{
+			name: "T off the right wall", turn: RotateCCW,
+			piece: Piece{Kind: T, Rot: 3, X: Cols - 2, Y: 10},
+			want:  Piece{Kind: T, Rot: 2, X: Cols - 3, Y: 10},
+		},
+		{
+			name: "I off the left wall", turn: RotateCW,
+			piece: Piece{Kind: I, Rot: 3, X: -1, Y: 10},
+			want:  Piece{Kind: I, Rot: 0, X: 0, Y: 10},
+		},
+		{
+			name: "I off the right wall", turn: RotateCCW,
+			piece: Piece{Kind: I, Rot: 1, X: Cols - 3, Y: 10},
+			want:  Piece{Kind: I, Rot: 0, X: Cols - 4, Y: 10},
+		},
+		{
+			name: "O stays put", turn: RotateCW,
+			piece: Piece{Kind: O, X: 4, Y: 10},
+			want:  Piece{Kind: O, Rot: 1, X: 4, Y: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := empty(tt.piece).Apply(tt.turn).Piece; got != tt.want {
+				t.Errorf("turned to %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// tSlot is a T-spin double slot at the bottom of the field: the T fits in
+// pointing down, under an overhang at its top left if there is one.
+func tSlot(p Piece, overhang bool) State {
+	s := empty(p)
+	s.fillRow(Rows-1, 4)
+	s.fillRow(Rows-2, 3, 4, 5)
+	if overhang {
+		s.Board[Rows-3][3] = Z
+	}
+	return s
+}
+
+func TestTSpin(t *testing.T) {
+	tests := []struct {
+		name     string
+		piece    Piece
+		overhang bool
+		inputs   []Action
+		want     string
+		points   int
+	}{
+		{
+			name:  "turned into the slot",
+			piece: Piece{Kind: T, Rot: 1, X: 3, Y: Rows - 3}, overhang: true,
+			inputs: []Action{RotateCW, HardDrop},
+			want:   "T-Spin Double", points: 1200,
+		},
+		{
+			// Set straight into the slot, as if it slid in; no turn, no spin.
+			name:  "not turned",
+			piece: Piece{Kind: T, Rot: 2, X: 3, Y: Rows - 3}, overhang: true,
+			inputs: []Action{HardDrop},
+			want:   "Double", points: 300,
+		},
+		{
+			// Two corners blocked are not enough.
+			name:   "no overhang",
+			piece:  Piece{Kind: T, Rot: 1, X: 3, Y: Rows - 3},
+			inputs: []Action{RotateCW, HardDrop},
+			want:   "Double", points: 300,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tSlot(tt.piece, tt.overhang)
+			for _, a := range tt.inputs {
+				s = s.Apply(a)
+			}
+			if s.Last.Name != tt.want || s.Last.Lines != 2 || s.Last.Points != tt.points {
+				t.Errorf("last clear = %+v, want %s for %d", s.Last, tt.want, tt.points)
+			}
+		})
+	}
+}
+
+func TestBackToBack(t *testing.T) {
+	// Four rows open in the first column take a vertical I each time.
+	well := func(s State) State {
+		for y := Rows - 4; y < Rows; y++ {
+			s.fillRow(y, 0)
+		}
+		s.Piece = Piece{Kind: I, Rot: 1, X: -2, Y: Rows - 4}
+		return s
+	}
+	s := well(empty(Piece{}))
+	s = s.Apply(HardDrop)
+	if s.Last.Name != "Tetris" || s.Last.Points != 800 {
+		t.Fatalf("first clear = %+v, want a Tetris for 800", s.Last)
+	}
+	s = well(s).Apply(HardDrop)
+	if s.Last.Name != "Back-to-Back Tetris" || s.Last.Combo != 1 || s.Last.Points != 800*3/2+50 {
+		t.Errorf("second clear = %+v, want a back to back Tetris with a combo", s.Last)
+	}
+	if s.Lines != 8 || s.Board != ([Rows][Cols]Kind{}) {
+		t.Errorf("%d lines cleared, board left %v", s.Lines, s.Board)
+	}
+}
+
+func TestHold(t *testing.T) {
+	s := New(Marathon, 7)
+	first, next := s.Piece.Kind, s.Queue[0]
+	s = s.Apply(Hold)
+	if s.Held != first || s.Piece.Kind != next {
+		t.Fatalf("held %s playing %s, want %s playing %s", s.Held, s.Piece.Kind, first, next)
+	}
+	if again := s.Apply(Hold); again.Held != first || again.Piece.Kind != next {
+		t.Error("a piece was held twice")
+	}
+	s = s.Apply(HardDrop).Apply(Hold)
+	if s.Piece.Kind != first {
+		t.Errorf("hold after a lock gave %s, want %s back", s.Piece.Kind, first)
+	}
+}
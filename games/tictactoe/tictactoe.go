@@ -0,0 +1,182 @@
+/*
+Package tictactoe is the rules of Tic-Tac-Toe and its minimax opponent.
+
+Seat 0 plays X and moves first, seat 1 plays O. A move names a cell by
+column and row, "a1" being the top left corner and "c3" the bottom right.
+*/
+package tictactoe
+
+import (
+	"fmt"
+	"gamics/games/turn"
+)
+
+const Size = 3
+
+// Marks of the two seats.
+const (
+	X = "X"
+	O = "O"
+)
+
+// lines are every row, column and diagonal, as cell indexes.
+var lines = [][3]int{
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8},
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8},
+	{0, 4, 8}, {2, 4, 6},
+}
+
+// Game is a Tic-Tac-Toe position. It implements turn.Game.
+type Game struct {
+	// Cells holds seat+1 of the mark in each cell, 0 when empty, row by row.
+	Cells [Size * Size]int
+	Turn  int
+}
+
+// New returns the empty board with X to move.
+func New() Game { return Game{} }
+
+// Mark is the mark of a seat.
+func Mark(seat int) string {
+	if seat == 0 {
+		return X
+	}
+	return O
+}
+
+// CellMove names the cell at row, col (0-based).
+func CellMove(row, col int) turn.Move {
+	return turn.Move(fmt.Sprintf("%c%d", 'a'+col, row+1))
+}
+
+// ParseMove reads a move back into a cell index.
+func ParseMove(m turn.Move) (int, error) {
+	if len(m) != 2 || m[0] < 'a' || m[0] >= 'a'+Size || m[1] < '1' || m[1] >= '1'+Size {
+		return 0, fmt.Errorf("%w: %q is not a cell", turn.ErrIllegal, m)
+	}
+	return int(m[1]-'1')*Size + int(m[0]-'a'), nil
+}
+
+func (g Game) Players() int { return 2 }
+func (g Game) ToMove() int  { return g.Turn }
+
+func (g Game) Legal() []turn.Move {
+	if g.Outcome().Over {
+		return nil
+	}
+	moves := make([]turn.Move, 0, Size*Size)
+	for i, c := range g.Cells {
+		if c == 0 {
+			moves = append(moves, CellMove(i/Size, i%Size))
+		}
+	}
+	return moves
+}
+
+func (g Game) Play(m turn.Move) (turn.Game, error) {
+	i, err := ParseMove(m)
+	if err != nil {
+		return g, err
+	}
+	if g.Cells[i] != 0 {
+		return g, fmt.Errorf("%w: %s is taken", turn.ErrIllegal, m)
+	}
+	g.Cells[i] = g.Turn + 1
+	g.Turn = 1 - g.Turn
+	return g, nil
+}
+
+func (g Game) Outcome() turn.Outcome {
+	if line, ok := g.WinLine(); ok {
+		return turn.Outcome{Over: true, Winner: g.Cells[line[0]] - 1, Reason: "three in a row"}
+	}
+	for _, c := range g.Cells {
+		if c == 0 {
+			return turn.Outcome{}
+		}
+	}
+	return turn.Outcome{Over: true, Winner: turn.Draw, Reason: "the board is full"}
+}
+
+// WinLine returns the three cells of a completed line, if any.
+func (g Game) WinLine() ([3]int, bool) {
+	for _, l := range lines {
+		if c := g.Cells[l[0]]; c != 0 && c == g.Cells[l[1]] && c == g.Cells[l[2]] {
+			return l, true
+		}
+	}
+	return [3]int{}, false
+}
+
+// ----------------------------------------------------------------------------------
+// AI
+// ----------------------------------------------------------------------------------
+
+// NewAI maps a difficulty level to its strategy: easy plays at random,
+// medium is minimax that slips one move in three, hard never loses.
+func NewAI(level string, seed uint64) turn.AI {
+	r := turn.NewRandom(seed)
+	switch level {
+	case turn.AIHard:
+		return Minimax{r: r}
+	case turn.AIMedium:
+		return Minimax{r: r, mistakes: 3}
+	default:
+		return r
+	}
+}
+
+// Minimax searches the whole game tree. A quick win scores higher than a
+// slow one and a slow loss higher than a quick one; among equal moves one
+// is picked at random so that games vary.
+type Minimax struct {
+	r        *turn.Random
+	mistakes int // play at random one move in mistakes, 0 never
+}
+
+func (ai Minimax) Choose(tg turn.Game) turn.Move {
+	g := tg.(Game)
+	if ai.mistakes > 0 && ai.r.Intn(ai.mistakes) == 0 {
+		return ai.r.Choose(g)
+	}
+
+	var best []turn.Move
+	bestScore := -1 << 30
+	for _, m := range g.Legal() {
+		next, _ := g.Play(m)
+		score := -negamax(next.(Game), 1)
+		switch {
+		case score > bestScore:
+			bestScore, best = score, []turn.Move{m}
+		case score == bestScore:
+			best = append(best, m)
+		}
+	}
+	return best[ai.r.Intn(len(best))]
+}
+
+// negamax scores g for the seat to move.
+func negamax(g Game, depth int) int {
+	if o := g.Outcome(); o.Over {
+		switch o.Winner {
+		case turn.Draw:
+			return 0
+		case g.Turn:
+			return 10 - depth
+		default:
+			return depth - 10
+		}
+	}
+
+	best := -1 << 30
+	for i, c := range g.Cells {
+		if c != 0 {
+			continue
+		}
+		next := g
+		next.Cells[i] = g.Turn + 1
+		next.Turn = 1 - g.Turn
+		best = max(best, -negamax(next, depth+1))
+	}
+	return best
+}
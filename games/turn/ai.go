@@ -0,0 +1,30 @@
+package turn
+
+import "math/rand/v2"
+
+// AI picks the move of the seat to move. The game passed in is never over.
+type AI interface {
+	Choose(g Game) Move
+}
+
+// Random plays any legal move. Games use it as their easiest level.
+type Random struct {
+	rng *rand.Rand
+}
+
+// NewRandom returns a Random that plays the same moves for the same seed.
+func NewRandom(seed uint64) *Random {
+	return &Random{rng: rand.New(rand.NewPCG(seed, seed^0x9E3779B97F4A7C15))}
+}
+
+// Choose plays any legal move, or the empty Move when there is none.
+func (r *Random) Choose(g Game) Move {
+	moves := g.Legal()
+	if len(moves) == 0 {
+		return ""
+	}
+	return moves[r.rng.IntN(len(moves))]
+}
+
+// Intn returns a number in [0, n), for AIs that mix in some chance.
+func (r *Random) Intn(n int) int { return r.rng.IntN(n) }
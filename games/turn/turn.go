@@ -0,0 +1,161 @@
+/*
+Package turn is the shared engine of turn-based games, free of any UI.
+
+A game implements Game: it says whose turn it is, which moves are legal and
+what state a move leads to. Game values are immutable, Play returns the next
+state and leaves the receiver alone, so a Match can keep every state it went
+through. That is what gives every game undo and a move history for free.
+
+Moves are strings in the notation of their game ("b2" for Tic-Tac-Toe,
+"11-15" for Checkers), which is also how they are shown in the history and
+sent over the network.
+
+A Match puts players in the seats of a game. A seat is either a human,
+playing from the keyboard (hot seat when there are several of them), or a
+CPU, which gets its moves from an AI.
+*/
+package turn
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Draw is the Outcome.Winner of a game nobody won.
+const Draw = -1
+
+// AI difficulty levels. Games map them to their own strategies.
+const (
+	AIEasy   = "easy"
+	AIMedium = "medium"
+	AIHard   = "hard"
+)
+
+var (
+	ErrOver    = errors.New("the game is over")
+	ErrIllegal = errors.New("illegal move")
+)
+
+// Move is a move in the notation of its game.
+type Move string
+
+// Game is one state of a turn game.
+type Game interface {
+	// Players is how many seats the game has.
+	Players() int
+	// ToMove is the seat whose turn it is.
+	ToMove() int
+	// Legal lists the moves of the seat to move, empty once the game is over.
+	Legal() []Move
+	// Play returns the state after m. It fails with ErrIllegal, possibly
+	// wrapped with a reason, when m is not a legal move.
+	Play(m Move) (Game, error)
+	// Outcome says whether the game is over and who won.
+	Outcome() Outcome
+}
+
+// Outcome is the result of a game.
+type Outcome struct {
+	Over   bool
+	Winner int    // seat of the winner, or Draw
+	Reason string // how it ended, e.g. "three in a row"
+}
+
+// Player sits in one seat of a match.
+type Player struct {
+	Name string
+	AI   string // difficulty level of a CPU player, empty for a human
+}
+
+// CPU reports whether the player is played by the computer.
+func (p Player) CPU() bool { return p.AI != "" }
+
+// Record is one move of the history.
+type Record struct {
+	Seat int
+	Move Move
+}
+
+// ----------------------------------------------------------------------------------
+// Match
+// ----------------------------------------------------------------------------------
+
+// Match is a game being played by its players. It is a value: Play and Undo
+// return a new match and never change the receiver.
+type Match struct {
+	Players []Player
+	History []Record
+
+	states []Game // states[i] is the game after i moves
+}
+
+// NewMatch seats players at start. There must be one player per seat.
+func NewMatch(start Game, players []Player) (Match, error) {
+	if len(players) != start.Players() {
+		return Match{}, fmt.Errorf("the game needs %d players, got %d", start.Players(), len(players))
+	}
+	return Match{Players: slices.Clone(players), states: []Game{start}}, nil
+}
+
+// Game is the current state.
+func (m Match) Game() Game { return m.states[len(m.states)-1] }
+
+// ToMove is the player whose turn it is.
+func (m Match) ToMove() Player { return m.Players[m.Game().ToMove()] }
+
+// Outcome is the outcome of the current state.
+func (m Match) Outcome() Outcome { return m.Game().Outcome() }
+
+// Play makes the move m for the player to move.
+func (m Match) Play(mv Move) (Match, error) {
+	g := m.Game()
+	if g.Outcome().Over {
+		return m, ErrOver
+	}
+	if !slices.Contains(g.Legal(), mv) {
+		return m, fmt.Errorf("%w: %s", ErrIllegal, mv)
+	}
+	next, err := g.Play(mv)
+	if err != nil {
+		return m, err
+	}
+
+	m.History = append(slices.Clip(m.History), Record{Seat: g.ToMove(), Move: mv})
+	m.states = append(slices.Clip(m.states), next)
+	return m, nil
+}
+
+// CanUndo reports whether Undo would take anything back.
+func (m Match) CanUndo() bool {
+	for i := len(m.History) - 1; i >= 0; i-- {
+		if !m.Players[m.History[i].Seat].CPU() {
+			return true
+		}
+	}
+	return false
+}
+
+// Undo takes back the last move of a human and every CPU move made after
+// it, so that it is that human's turn again. In a hot-seat match that is
+// simply the last move.
+func (m Match) Undo() Match {
+	if !m.CanUndo() {
+		return m
+	}
+	n := len(m.History)
+	for n > 0 {
+		n--
+		if !m.Players[m.History[n].Seat].CPU() {
+			break
+		}
+	}
+	m.History = slices.Clip(m.History[:n])
+	m.states = slices.Clip(m.states[:n+1])
+	return m
+}
+
+// Rematch starts over from the initial state with the same players.
+func (m Match) Rematch() Match {
+	return Match{Players: m.Players, states: m.states[:1:1]}
+}
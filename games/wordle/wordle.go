@@ -0,0 +1,211 @@
+/*
+Package wordle is the five-letter word game, free of any UI: the bundled
+word lists, the scoring of a guess, hard mode and the shareable result.
+
+The lists ship inside the binary so the game plays offline. answers.txt
+holds the everyday words answers are drawn from; guesses.txt every other
+five-letter word accepted as a guess, rare and dialect words included, so
+a real word is seldom turned down.
+*/
+package wordle
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+const (
+	Length     = 5
+	MaxGuesses = 6
+)
+
+// Mark is what a letter of a guess revealed.
+type Mark byte
+
+const (
+	Unknown Mark = iota // not guessed yet, for the keyboard
+	Absent
+	Present // in the word, elsewhere
+	Correct // in the word, right here
+)
+
+var (
+	//go:embed words/answers.txt
+	answersFile string
+	//go:embed words/guesses.txt
+	guessesFile string
+
+	// answers is in a fixed shuffled order, so the words picked on
+	// consecutive days don't run through the alphabet.
+	answers []string
+	valid   = map[string]bool{}
+)
+
+func init() {
+	answers = strings.Fields(answersFile)
+	rand.New(rand.NewPCG(5, 5)).Shuffle(len(answers), func(i, j int) { answers[i], answers[j] = answers[j], answers[i] })
+	for _, w := range answers {
+		valid[w] = true
+	}
+	for _, w := range strings.Fields(guessesFile) {
+		valid[w] = true
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Game is one word to find. It is a plain value so a game in progress can
+// be saved and continued.
+type Game struct {
+	Answer  string   `yaml:"answer"  mapstructure:"answer"`
+	Guesses []string `yaml:"guesses" mapstructure:"guesses"`
+	// Hard makes every revealed hint binding on later guesses.
+	Hard bool `yaml:"hard" mapstructure:"hard"`
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// New starts a game on answer.
+func New(answer string, hard bool) Game {
+	return Game{Answer: answer, Hard: hard}
+}
+
+// Answer picks the answer seed stands for. The same seed always gives the
+// same word, which is how every player gets the same daily word.
+func Answer(seed uint64) string {
+	return answers[seed%uint64(len(answers))]
+}
+
+// Valid reports whether w is accepted as a guess.
+func Valid(w string) bool { return valid[strings.ToLower(w)] }
+
+// ----------------------------------------------------------------------------------
+// Rules
+// ----------------------------------------------------------------------------------
+
+// Score marks guess against answer. A letter guessed more often than the
+// answer has it is Present only as many times as the answer has it left
+// over from the Correct ones.
+func Score(guess, answer string) [Length]Mark {
+	var marks [Length]Mark
+	left := map[byte]int{}
+	for i := 0; i < Length; i++ {
+		if guess[i] == answer[i] {
+			marks[i] = Correct
+		} else {
+			left[answer[i]]++
+		}
+	}
+	for i := 0; i < Length; i++ {
+		if marks[i] == Correct {
+			continue
+		}
+		marks[i] = Absent
+		if left[guess[i]] > 0 {
+			marks[i] = Present
+			left[guess[i]]--
+		}
+	}
+	return marks
+}
+
+// Marks returns the marks of the i-th guess.
+func (g Game) Marks(i int) [Length]Mark { return Score(g.Guesses[i], g.Answer) }
+
+// Won reports whether the last guess was the answer.
+func (g Game) Won() bool {
+	return len(g.Guesses) > 0 && g.Guesses[len(g.Guesses)-1] == g.Answer
+}
+
+// Over reports whether the game is won or out of guesses.
+func (g Game) Over() bool { return g.Won() || len(g.Guesses) >= MaxGuesses }
+
+// Guess plays w. It fails, leaving the game as it is, on words that are not
+// in the lists and, in hard mode, on guesses ignoring a hint.
+func (g Game) Guess(w string) (Game, error) {
+	w = strings.ToLower(w)
+	switch {
+	case g.Over():
+		return g, errors.New("the game is over")
+	case len(w) < Length:
+		return g, errors.New("not enough letters")
+	case len(w) > Length:
+		return g, errors.New("too many letters")
+	case !valid[w]:
+		return g, errors.New("not in word list")
+	}
+	if g.Hard {
+		if err := g.followsHints(w); err != nil {
+			return g, err
+		}
+	}
+	g.Guesses = append(append([]string(nil), g.Guesses...), w)
+	return g, nil
+}
+
+// followsHints checks w keeps every green letter in place and uses every
+// yellow one.
+func (g Game) followsHints(w string) error {
+	for _, prev := range g.Guesses {
+		marks := Score(prev, g.Answer)
+		for i, m := range marks {
+			if m == Correct && w[i] != prev[i] {
+				return fmt.Errorf("%s letter must be %c", ordinals[i], prev[i]-'a'+'A')
+			}
+		}
+		need := map[byte]int{}
+		for i, m := range marks {
+			if m == Present || m == Correct {
+				need[prev[i]]++
+			}
+		}
+		for i := 0; i < Length; i++ {
+			if need[prev[i]] > strings.Count(w, string(prev[i])) {
+				return fmt.Errorf("guess must contain %c", prev[i]-'a'+'A')
+			}
+		}
+	}
+	return nil
+}
+
+var ordinals = [Length]string{"1st", "2nd", "3rd", "4th", "5th"}
+
+// Keyboard returns the best mark each letter earned so far.
+func (g Game) Keyboard() map[byte]Mark {
+	keys := map[byte]Mark{}
+	for i, w := range g.Guesses {
+		for j, m := range g.Marks(i) {
+			keys[w[j]] = max(keys[w[j]], m)
+		}
+	}
+	return keys
+}
+
+// Share is the result as an emoji grid that gives no letter away, under
+// a "<title> 4/6" line; X stands for a lost game and * for hard mode.
+func (g Game) Share(title string) string {
+	score := "X"
+	if g.Won() {
+		score = fmt.Sprint(len(g.Guesses))
+	}
+	head := fmt.Sprintf("%s %s/%d", title, score, MaxGuesses)
+	if g.Hard {
+		head += "*"
+	}
+	rows := []string{head, ""}
+	for i := range g.Guesses {
+		var b strings.Builder
+		for _, m := range g.Marks(i) {
+			b.WriteString(map[Mark]string{Absent: "⬛", Present: "🟨", Correct: "🟩"}[m])
+		}
+		rows = append(rows, b.String())
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,54 @@
+package wordle
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWordLists(t *testing.T) {
+	word := regexp.MustCompile(`^[a-z]{5}$`)
+	guesses := strings.Fields(guessesFile)
+	if len(guesses) < 10000 {
+		t.Errorf("only %d extra guesses", len(guesses))
+	}
+
+	answer := map[string]bool{}
+	for _, w := range answers {
+		if !word.MatchString(w) || answer[w] {
+			t.Errorf("answer %q is malformed or listed twice", w)
+		}
+		answer[w] = true
+		if !Valid(w) {
+			t.Errorf("answer %q is not a valid guess", w)
+		}
+	}
+	for i, w := range guesses {
+		if !word.MatchString(w) || answer[w] || i > 0 && guesses[i-1] >= w {
+			t.Errorf("guess %q is malformed, an answer or out of order", w)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		guess, answer string
+		want          string // c correct, p present, a absent
+	}{
+		{"crane", "crane", "ccccc"},
+		{"stale", "crane", "aacac"},
+		{"speed", "abide", "aapap"},
+		{"eerie", "crane", "aapac"},
+		{"llama", "hello", "ppaaa"},
+	}
+	code := map[Mark]byte{Correct: 'c', Present: 'p', Absent: 'a'}
+	for _, tt := range tests {
+		var got []byte
+		for _, m := range Score(tt.guess, tt.answer) {
+			got = append(got, code[m])
+		}
+		if string(got) != tt.want {
+			t.Errorf("Score(%s, %s) = %s, want %s", tt.guess, tt.answer, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package lockfile
+
+import "os"
+
+// Elsewhere only the process-wide mutex guards updates.
+
+func lock(*os.File) error { return nil }
+
+func unlock(*os.File) error { return nil }
@@ -0,0 +1,65 @@
+/*
+Package lockfile serialises updates of the files every player of a machine
+shares, such as the daily and all-time leaderboards. An update loads the
+file, changes it and writes it back; without a lock two sessions of one
+server, or two gamics processes, could each write back what they loaded
+and lose the other's change.
+*/
+package lockfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// mu keeps the updates of one process in turn; the lock file does the same
+// between processes.
+var mu sync.Mutex
+
+// Update runs fn while holding the lock on name: a process-wide mutex and
+// an exclusive lock on name+".lock", created along with its directory if
+// need be. fn should load, change and Replace name.
+func Update(name string, fn func() error) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return fmt.Errorf("could not create %s: %w", filepath.Dir(name), err)
+	}
+	f, err := os.OpenFile(name+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open lock file: %w", err)
+	}
+	defer f.Close()
+	if err := lock(f); err != nil {
+		return fmt.Errorf("could not lock %s: %w", name, err)
+	}
+	defer unlock(f)
+	return fn()
+}
+
+// Replace has write create a temporary file next to name, with the same
+// extension, and renames it over name once written, so readers see either
+// the old file or the new one and never half of it.
+func Replace(name string, write func(tmp string) error) error {
+	ext := filepath.Ext(name)
+	f, err := os.CreateTemp(filepath.Dir(name), "."+strings.TrimSuffix(filepath.Base(name), ext)+"-*"+ext)
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	tmp := f.Name()
+	f.Close()
+	os.Chmod(tmp, 0644) // CreateTemp makes it private to its owner
+	if err := write(tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not replace %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+/*
+Package rng is the random generator the game engines carry inside their
+state, so that a step stays a pure function of its arguments: the same state
+and input always produce the same next state. It is splitmix64, which is
+small, fast and serialises as a single integer.
+*/
+package rng
+
+// Next advances the generator state and returns the next number.
+func Next(state *uint64) uint64 {
+	*state += 0x9E3779B97F4A7C15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+// Intn advances the generator state and returns a number in [0, n).
+// n <= 0 yields 0.
+func Intn(state *uint64, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(Next(state) % uint64(n))
+}
@@ -0,0 +1,152 @@
+/*
+Package leaderboard keeps the all-time best results of a game, shared by
+every player of the machine. A game may keep several boards, one per mode.
+Boards live next to the player directories:
+
+	.gamics/leaderboards/<game>/<board>.yaml
+
+Only the best MaxEntries results are kept, several of them possibly by the
+same player. Submissions hold a lock on the board, so players finishing at
+once don't drop each other's results.
+*/
+package leaderboard
+
+import (
+	"errors"
+	"fmt"
+	"gamics/internal/lockfile"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// MaxEntries is how many results a board keeps.
+const MaxEntries = 10
+
+// Order says what ranks a board.
+type Order int
+
+const (
+	// ByScore ranks the highest score first; ties go to the faster result.
+	ByScore Order = iota
+	// ByTime ranks the fastest result first; ties go to the higher score.
+	ByTime
+)
+
+// Entry is one result.
+type Entry struct {
+	Player string        `yaml:"player" mapstructure:"player"`
+	Score  int           `yaml:"score"  mapstructure:"score"`
+	Time   time.Duration `yaml:"time"   mapstructure:"time"`
+	Lines  int           `yaml:"lines"  mapstructure:"lines"`
+	When   time.Time     `yaml:"when"   mapstructure:"when"`
+}
+
+// Board is the best results of one game mode.
+type Board struct {
+	Game    string
+	Name    string
+	Order   Order
+	Entries []Entry
+}
+
+// Load reads a board below root (usually ".gamics"). A board nobody made
+// it onto yet is empty.
+func Load(root, game, name string, order Order) (Board, error) {
+	b := Board{Game: game, Name: name, Order: order}
+	cfg := viper.New()
+	cfg.SetConfigFile(boardPath(root, game, name))
+	if err := cfg.ReadInConfig(); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return b, nil
+		}
+		return b, fmt.Errorf("could not read leaderboard: %w", err)
+	}
+	if err := cfg.UnmarshalKey("entries", &b.Entries); err != nil {
+		return b, fmt.Errorf("could not parse leaderboard: %w", err)
+	}
+	b.sort()
+	return b, nil
+}
+
+// Submit adds e to the board and reports its 1-based rank, 0 when it did
+// not make the board.
+func Submit(root, game, name string, order Order, e Entry) (int, error) {
+	if e.When.IsZero() {
+		e.When = time.Now().UTC()
+	}
+	rank := 0
+	err := lockfile.Update(boardPath(root, game, name), func() error {
+		b, err := Load(root, game, name, order)
+		if err != nil {
+			return err
+		}
+		b.Entries = append(b.Entries, e)
+		b.sort()
+
+		for i, x := range b.Entries {
+			if x == e {
+				rank = i + 1
+				break
+			}
+		}
+		if rank > MaxEntries {
+			rank = 0
+			return nil
+		}
+		if len(b.Entries) > MaxEntries {
+			b.Entries = b.Entries[:MaxEntries]
+		}
+		return save(root, b)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return rank, nil
+}
+
+// Best returns player's best entry on the board.
+func (b Board) Best(player string) (Entry, bool) {
+	for _, e := range b.Entries {
+		if e.Player == player {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// sort orders the board by its Order; equal results keep who got there
+// first ahead.
+func (b *Board) sort() {
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		a, c := b.Entries[i], b.Entries[j]
+		switch {
+		case b.Order == ByTime && a.Time != c.Time:
+			return a.Time < c.Time
+		case a.Score != c.Score:
+			return a.Score > c.Score
+		case a.Time != c.Time:
+			return a.Time < c.Time
+		}
+		return a.When.Before(c.When)
+	})
+}
+
+// save writes b over its file. Callers hold the board's lock.
+func save(root string, b Board) error {
+	cfg := viper.New()
+	cfg.Set("entries", b.Entries)
+	return lockfile.Replace(boardPath(root, b.Game, b.Name), func(tmp string) error {
+		if err := cfg.WriteConfigAs(tmp); err != nil {
+			return fmt.Errorf("could not write leaderboard: %w", err)
+		}
+		return nil
+	})
+}
+
+func boardPath(root, game, name string) string {
+	return path.Join(root, "leaderboards", game, name+".yaml")
+}
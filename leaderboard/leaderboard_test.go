@@ -0,0 +1,67 @@
+package leaderboard
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubmitConcurrent(t *testing.T) {
+	root := t.TempDir()
+	const players = 20
+
+	var wg sync.WaitGroup
+	for i := range players {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("p%02d", i)
+			if _, err := Submit(root, "tetris", "marathon", ByScore, Entry{Player: name, Score: i}); err != nil {
+				t.Errorf("Submit(%s): %v", name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	b, err := Load(root, "tetris", "marathon", ByScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Entries) != MaxEntries {
+		t.Fatalf("board has %d entries, want %d", len(b.Entries), MaxEntries)
+	}
+	for i, e := range b.Entries {
+		if want := players - 1 - i; e.Score != want {
+			t.Errorf("entry %d scores %d, want %d: a result was lost", i+1, e.Score, want)
+		}
+	}
+}
+
+func TestSubmitRank(t *testing.T) {
+	root := t.TempDir()
+	submit := func(player string, d time.Duration) int {
+		t.Helper()
+		rank, err := Submit(root, "tetris", "sprint", ByTime, Entry{Player: player, Time: d})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rank
+	}
+	for i := range MaxEntries {
+		submit(fmt.Sprintf("p%02d", i), time.Duration(i+1)*time.Minute)
+	}
+	if rank := submit("slow", time.Hour); rank != 0 {
+		t.Errorf("a result off the board ranked %d", rank)
+	}
+	if rank := submit("fast", time.Second); rank != 1 {
+		t.Errorf("the fastest result ranked %d, want 1", rank)
+	}
+	b, err := Load(root, "tetris", "sprint", ByTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Best("slow"); ok {
+		t.Error("a result off the board was kept")
+	}
+}
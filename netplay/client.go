@@ -0,0 +1,84 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client is one player's connection to a server.
+type Client struct {
+	Name string
+
+	conn net.Conn
+	wmu  sync.Mutex
+	enc  *json.Encoder
+	in   chan Message
+}
+
+// Dial connects to the server at addr and introduces the player as name.
+// It returns once the server welcomed the player or refused the name.
+func Dial(addr, name string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %w", addr, err)
+	}
+
+	c := &Client{Name: name, conn: conn, enc: json.NewEncoder(conn), in: make(chan Message, 64)}
+	if err := c.Send(Message{Type: TypeHello, Name: name}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 4096), maxLineBytes)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	if !sc.Scan() {
+		conn.Close()
+		return nil, fmt.Errorf("server closed the connection during the handshake")
+	}
+	var welcome Message
+	if err := json.Unmarshal(sc.Bytes(), &welcome); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("malformed handshake: %w", err)
+	}
+	if welcome.Type != TypeWelcome {
+		conn.Close()
+		return nil, fmt.Errorf("server refused to join: %s", welcome.Text)
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	go c.read(sc)
+	return c, nil
+}
+
+func (c *Client) read(sc *bufio.Scanner) {
+	defer close(c.in)
+	for sc.Scan() {
+		var m Message
+		if json.Unmarshal(sc.Bytes(), &m) == nil {
+			c.in <- m
+		}
+	}
+}
+
+// Messages delivers everything the server sends. The channel closes when
+// the connection does.
+func (c *Client) Messages() <-chan Message { return c.in }
+
+// Send writes one message to the server.
+func (c *Client) Send(m Message) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := c.enc.Encode(m); err != nil {
+		return fmt.Errorf("could not send %s: %w", m.Type, err)
+	}
+	return nil
+}
+
+// Close hangs up.
+func (c *Client) Close() error { return c.conn.Close() }
@@ -0,0 +1,170 @@
+/*
+Package netplay lets several gamics processes play together over a local
+network.
+
+One process runs the server (gamics serve); players connect to it with
+gamics join. They talk over plain TCP with JSON lines: every message is one
+JSON object on its own line, in both directions. All messages share the
+Message shape; "type" says which fields are meaningful.
+
+Handshake
+
+	→ {"type":"hello","name":"ana"}
+	← {"type":"welcome","name":"ana"}
+	← {"type":"lobbies","lobbies":[{"name":"ana's room","game":"snake","players":["ana"]}]}
+
+Names must be unique on the server; a taken name is answered with an
+"error" message and the connection is closed.
+
+Lobbies
+
+	→ {"type":"list"}
+	→ {"type":"create","lobby":"friday","game":"snake"}
+	→ {"type":"join","lobby":"friday"}
+	→ {"type":"ready"}
+	→ {"type":"leave"}
+	← {"type":"lobby","lobby":"friday","game":"snake","players":["ana","bo"],
+	   "ready":[true,false],"wins":[1,0],"running":false}
+
+The creator of a lobby joins it right away. Every change of a lobby is sent
+to its members as a "lobby" message. A game starts once at least two
+players are in and everybody is ready; the "ready" flags reset after each
+round. Joining a running game is refused.
+
+Chat
+
+	→ {"type":"chat","text":"gl hf"}
+	← {"type":"chat","from":"ana","text":"gl hf"}
+
+Chat goes to the sender's lobby, or to every player outside a lobby.
+
+Tick games (snake)
+
+	← {"type":"start","game":"snake","seed":42,"width":60,"height":20,"players":["ana","bo"]}
+	→ {"type":"turn","direction":"up"}
+	← {"type":"tick","tick":1,"turns":["up",""],"elapsed_ms":183}
+	← {"type":"end","winner":"ana","reason":"round"}
+
+The server owns the clock. Turns are queued per player, and every tick the
+server pops at most one per player and broadcasts them; ticks are numbered
+from 1. Each peer builds the round with NewSnakeRound from the "start"
+message and applies every "tick" with snake.Step, so all of them see the
+same game. "winner" is empty for a draw.
+
+Turn games
+
+	← {"type":"start","game":"ticTacToe","seed":42,"players":["ana","bo"]}
+	→ {"type":"move","move":"b2","turn":1}
+	← {"type":"move","from":"ana","seq":1,"move":"b2","turn":1}
+	→ {"type":"end","winner":"ana","reason":"three in a row"}
+	← {"type":"end","winner":"ana","reason":"three in a row"}
+
+Any other game is a turn game for two players, relayed move by move: the
+server only enforces the turn order and numbers the moves from 1. "turn" is
+the index in "players" of who moves next, as the mover's game says; it is
+not always the other player, since in some games a player without a move
+passes. The payload of "move" belongs to the game; gamics sends the move in
+the notation of its game, as a string. Peers check every move and the turn
+that follows it against their own copy of the game.
+
+When its game is over, each peer sends an "end" naming the winner, empty
+for a draw. The server ends the game once both did: when they name the
+same winner it counts the win and forwards the result to the lobby,
+otherwise nobody wins and the reason is ReasonDisagree.
+
+Errors
+
+	← {"type":"error","text":"lobby \"friday\" is full"}
+*/
+package netplay
+
+import (
+	"encoding/json"
+	"gamics/games/snake"
+)
+
+// DefaultAddr is where gamics serve listens unless told otherwise.
+const DefaultAddr = ":7777"
+
+// Snake rounds are played on a fixed field so that every peer, whatever
+// its terminal size, runs the same game.
+const (
+	SnakeWidth  = 60
+	SnakeHeight = 20
+	MaxPlayers  = 4
+	// TurnPlayers is how many players a turn game takes.
+	TurnPlayers = 2
+)
+
+// ReasonDisagree is the reason of a turn game that ended without a winner
+// because the peers named different ones.
+const ReasonDisagree = "the players disagree on the result"
+
+// Message types.
+const (
+	TypeHello   = "hello"
+	TypeWelcome = "welcome"
+	TypeList    = "list"
+	TypeLobbies = "lobbies"
+	TypeCreate  = "create"
+	TypeJoin    = "join"
+	TypeLeave   = "leave"
+	TypeReady   = "ready"
+	TypeLobby   = "lobby"
+	TypeChat    = "chat"
+	TypeStart   = "start"
+	TypeTurn    = "turn"
+	TypeTick    = "tick"
+	TypeMove    = "move"
+	TypeEnd     = "end"
+	TypeError   = "error"
+)
+
+// GameSnake is the only tick game; every other game is a turn game.
+const GameSnake = "snake"
+
+// Message is every line of the protocol.
+type Message struct {
+	Type      string          `json:"type"`
+	Name      string          `json:"name,omitempty"`
+	Lobby     string          `json:"lobby,omitempty"`
+	Game      string          `json:"game,omitempty"`
+	Lobbies   []LobbyInfo     `json:"lobbies,omitempty"`
+	Players   []string        `json:"players,omitempty"`
+	Ready     []bool          `json:"ready,omitempty"`
+	Wins      []int           `json:"wins,omitempty"`
+	Running   bool            `json:"running,omitempty"`
+	From      string          `json:"from,omitempty"`
+	Text      string          `json:"text,omitempty"`
+	Seed      uint64          `json:"seed,omitempty"`
+	Width     int             `json:"width,omitempty"`
+	Height    int             `json:"height,omitempty"`
+	Direction string          `json:"direction,omitempty"`
+	Tick      int             `json:"tick,omitempty"`
+	Turns     []string        `json:"turns,omitempty"`
+	ElapsedMs int64           `json:"elapsed_ms,omitempty"`
+	Turn      int             `json:"turn,omitempty"`
+	Seq       int             `json:"seq,omitempty"`
+	Move      json.RawMessage `json:"move,omitempty"`
+	Winner    string          `json:"winner,omitempty"`
+	Reason    string          `json:"reason,omitempty"`
+}
+
+// LobbyInfo is one entry of a "lobbies" message.
+type LobbyInfo struct {
+	Name    string   `json:"name"`
+	Game    string   `json:"game"`
+	Players []string `json:"players"`
+	Running bool     `json:"running,omitempty"`
+}
+
+// NewSnakeRound builds the round described by a "start" message. Server and
+// clients all call it, so they start from the same state.
+func NewSnakeRound(start Message) snake.State {
+	return snake.New(snake.Config{
+		Width:  start.Width,
+		Height: start.Height,
+		Seed:   start.Seed,
+		Snakes: make([]snake.SnakeConfig, len(start.Players)),
+	})
+}
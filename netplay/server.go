@@ -0,0 +1,595 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gamics/games/snake"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	writeTimeout = 5 * time.Second
+	maxLineBytes = 64 * 1024
+	maxChatRunes = 300
+	outboxSize   = 256 // messages queued for a peer before it is dropped
+)
+
+// Server hosts the lobbies. All of its state is guarded by mu; the tick
+// loops of running snake rounds take the same lock for every tick. Nothing
+// is written to a connection under mu: messages are queued on the outbox of
+// each peer and written by the peer's own writer, so a slow peer only holds
+// up itself.
+type Server struct {
+	Log *log.Logger // nil discards the log
+
+	mu      sync.Mutex
+	players map[string]*peer
+	lobbies map[string]*lobby
+}
+
+type peer struct {
+	name  string
+	conn  net.Conn
+	lobby *lobby
+
+	omu     sync.Mutex
+	out     chan Message
+	closed  bool
+	written chan struct{} // closed once the writer is done
+}
+
+type lobby struct {
+	name    string
+	game    string
+	players []*peer
+	ready   []bool
+	wins    []int
+	running bool
+
+	// tick games
+	round snake.State
+	turns []snake.TurnQueue
+	stop  chan struct{}
+
+	// turn games
+	turn   int
+	seq    int
+	claims []*Message // the "end" each player sent, by seat
+}
+
+// NewServer returns a server with no lobbies.
+func NewServer() *Server {
+	return &Server{players: make(map[string]*peer), lobbies: make(map[string]*lobby)}
+}
+
+// ListenAndServe listens on addr and serves until the listener fails.
+func ListenAndServe(addr string, logger *log.Logger) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %w", addr, err)
+	}
+	s := NewServer()
+	s.Log = logger
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until it is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.logf("listening on %s", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("could not accept connection: %w", err)
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Connections
+// ----------------------------------------------------------------------------------
+func (s *Server) handle(conn net.Conn) {
+	p := &peer{conn: conn, out: make(chan Message, outboxSize), written: make(chan struct{})}
+	go p.write()
+	defer func() {
+		// Flush what is queued, such as the error that ends a handshake.
+		p.close()
+		<-p.written
+		conn.Close()
+	}()
+
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 4096), maxLineBytes)
+
+	// The first line must be a hello with a free name.
+	if !sc.Scan() {
+		return
+	}
+	var hello Message
+	if err := json.Unmarshal(sc.Bytes(), &hello); err != nil || hello.Type != TypeHello {
+		p.send(Message{Type: TypeError, Text: "expected hello"})
+		return
+	}
+	if err := s.register(p, strings.TrimSpace(hello.Name)); err != nil {
+		p.send(Message{Type: TypeError, Text: err.Error()})
+		return
+	}
+	defer s.unregister(p)
+
+	for sc.Scan() {
+		var m Message
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			p.send(Message{Type: TypeError, Text: "malformed message"})
+			continue
+		}
+		s.dispatch(p, m)
+	}
+}
+
+func (s *Server) register(p *peer, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if _, taken := s.players[name]; taken {
+		return fmt.Errorf("name %q is taken", name)
+	}
+	p.name = name
+	s.players[name] = p
+	s.logf("%s connected from %s", name, p.conn.RemoteAddr())
+
+	p.send(Message{Type: TypeWelcome, Name: name})
+	p.send(s.lobbiesMsg())
+	return nil
+}
+
+func (s *Server) unregister(p *peer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leave(p)
+	delete(s.players, p.name)
+	s.logf("%s disconnected", p.name)
+}
+
+// send queues m for the peer without blocking. A peer whose outbox is full
+// cannot keep up and is disconnected; its reader then unregisters it.
+func (p *peer) send(m Message) {
+	p.omu.Lock()
+	defer p.omu.Unlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.out <- m:
+	default:
+		p.closed = true
+		close(p.out)
+		p.conn.Close()
+	}
+}
+
+// close stops queueing; the writer goes on until the outbox is empty.
+func (p *peer) close() {
+	p.omu.Lock()
+	defer p.omu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.out)
+	}
+}
+
+// write writes the outbox to the connection, flushing whenever it is
+// empty so a burst goes out in few writes. After a failed write the rest is
+// dropped and the connection closed.
+func (p *peer) write() {
+	defer close(p.written)
+	w := bufio.NewWriter(p.conn)
+	enc := json.NewEncoder(w)
+	failed := false
+	for m := range p.out {
+		if failed {
+			continue
+		}
+		p.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		err := enc.Encode(m)
+		if err == nil && len(p.out) == 0 {
+			err = w.Flush()
+		}
+		if err != nil {
+			failed = true
+			p.conn.Close()
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Dispatch
+// ----------------------------------------------------------------------------------
+func (s *Server) dispatch(p *peer, m Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	switch m.Type {
+	case TypeList:
+		p.send(s.lobbiesMsg())
+	case TypeCreate:
+		err = s.create(p, strings.TrimSpace(m.Lobby), m.Game)
+	case TypeJoin:
+		err = s.join(p, m.Lobby)
+	case TypeLeave:
+		s.leave(p)
+		p.send(s.lobbiesMsg())
+	case TypeReady:
+		err = s.ready(p)
+	case TypeChat:
+		s.chat(p, m.Text)
+	case TypeTurn:
+		err = s.queueTurn(p, m.Direction)
+	case TypeMove:
+		err = s.move(p, m)
+	case TypeEnd:
+		err = s.endTurnGame(p, m)
+	default:
+		err = fmt.Errorf("unknown message type %q", m.Type)
+	}
+	if err != nil {
+		p.send(Message{Type: TypeError, Text: err.Error()})
+	}
+}
+
+func (s *Server) lobbiesMsg() Message {
+	infos := make([]LobbyInfo, 0, len(s.lobbies))
+	for _, l := range s.lobbies {
+		infos = append(infos, LobbyInfo{Name: l.name, Game: l.game, Players: l.names(), Running: l.running})
+	}
+	return Message{Type: TypeLobbies, Lobbies: infos}
+}
+
+// broadcastLobbies refreshes the lobby list of every player outside a lobby.
+func (s *Server) broadcastLobbies() {
+	msg := s.lobbiesMsg()
+	for _, p := range s.players {
+		if p.lobby == nil {
+			p.send(msg)
+		}
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Lobbies
+// ----------------------------------------------------------------------------------
+func (s *Server) create(p *peer, name, game string) error {
+	if name == "" {
+		return errors.New("lobby name is required")
+	}
+	if game == "" {
+		game = GameSnake
+	}
+	if _, taken := s.lobbies[name]; taken {
+		return fmt.Errorf("lobby %q already exists", name)
+	}
+	s.leave(p)
+	s.lobbies[name] = &lobby{name: name, game: game}
+	s.logf("%s created lobby %q (%s)", p.name, name, game)
+	return s.join(p, name)
+}
+
+func (s *Server) join(p *peer, name string) error {
+	l, ok := s.lobbies[name]
+	switch {
+	case !ok:
+		return fmt.Errorf("lobby %q does not exist", name)
+	case p.lobby == l:
+		return nil
+	case l.running:
+		return fmt.Errorf("lobby %q is playing", name)
+	case len(l.players) >= l.capacity():
+		return fmt.Errorf("lobby %q is full", name)
+	}
+	s.leave(p)
+	l.players = append(l.players, p)
+	l.ready = append(l.ready, false)
+	l.wins = append(l.wins, 0)
+	p.lobby = l
+	s.broadcastLobby(l)
+	s.broadcastLobbies()
+	return nil
+}
+
+// leave takes p out of its lobby. A running game ends when too few players
+// remain; an empty lobby is removed.
+func (s *Server) leave(p *peer) {
+	l := p.lobby
+	if l == nil {
+		return
+	}
+	i := l.index(p)
+	l.players = append(l.players[:i], l.players[i+1:]...)
+	l.ready = append(l.ready[:i], l.ready[i+1:]...)
+	l.wins = append(l.wins[:i], l.wins[i+1:]...)
+	p.lobby = nil
+
+	if len(l.players) == 0 {
+		s.stopRound(l)
+		delete(s.lobbies, l.name)
+		s.broadcastLobbies()
+		return
+	}
+	if l.running {
+		// The game cannot go on with a hole in the player order.
+		winner := ""
+		if len(l.players) == 1 {
+			winner = l.players[0].name
+			l.wins[0]++
+		}
+		s.finish(l, Message{Type: TypeEnd, Winner: winner, Reason: p.name + " left"})
+		return
+	}
+	s.broadcastLobby(l)
+	s.broadcastLobbies()
+}
+
+func (s *Server) ready(p *peer) error {
+	l := p.lobby
+	if l == nil {
+		return errors.New("not in a lobby")
+	}
+	if l.running {
+		return nil
+	}
+	l.ready[l.index(p)] = true
+	s.broadcastLobby(l)
+
+	if len(l.players) < 2 {
+		return nil
+	}
+	for _, r := range l.ready {
+		if !r {
+			return nil
+		}
+	}
+	s.start(l)
+	return nil
+}
+
+func (s *Server) chat(p *peer, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if r := []rune(text); len(r) > maxChatRunes {
+		text = string(r[:maxChatRunes])
+	}
+	msg := Message{Type: TypeChat, From: p.name, Text: text}
+	for _, other := range s.players {
+		if other.lobby == p.lobby {
+			other.send(msg)
+		}
+	}
+}
+
+func (s *Server) broadcastLobby(l *lobby) {
+	l.broadcast(Message{Type: TypeLobby, Lobby: l.name, Game: l.game, Players: l.names(),
+		Ready: append([]bool(nil), l.ready...), Wins: append([]int(nil), l.wins...), Running: l.running})
+}
+
+func (l *lobby) broadcast(m Message) {
+	for _, p := range l.players {
+		p.send(m)
+	}
+}
+
+// capacity is how many players the game of l takes.
+func (l *lobby) capacity() int {
+	if l.game == GameSnake {
+		return MaxPlayers
+	}
+	return TurnPlayers
+}
+
+func (l *lobby) names() []string {
+	names := make([]string, 0, len(l.players))
+	for _, p := range l.players {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+func (l *lobby) index(p *peer) int {
+	for i, other := range l.players {
+		if other == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// ----------------------------------------------------------------------------------
+// Games
+// ----------------------------------------------------------------------------------
+func (s *Server) start(l *lobby) {
+	start := Message{Type: TypeStart, Game: l.game, Seed: uint64(time.Now().UnixNano()), Players: l.names()}
+	l.running = true
+	l.turn, l.seq = 0, 0
+	l.claims = make([]*Message, len(l.players))
+
+	if l.game == GameSnake {
+		start.Width, start.Height = SnakeWidth, SnakeHeight
+		l.round = NewSnakeRound(start)
+		l.turns = make([]snake.TurnQueue, len(l.players))
+		for i := range l.turns {
+			l.turns[i] = snake.NewTurnQueue(snake.DefaultQueueSize)
+		}
+		l.stop = make(chan struct{})
+		go s.tickLoop(l, l.stop)
+	}
+
+	s.logf("lobby %q started %s with %v", l.name, l.game, start.Players)
+	l.broadcast(start)
+	s.broadcastLobby(l)
+	s.broadcastLobbies()
+}
+
+// finish ends the running game of l with end and resets the ready flags.
+func (s *Server) finish(l *lobby, end Message) {
+	s.stopRound(l)
+	l.running = false
+	for i := range l.ready {
+		l.ready[i] = false
+	}
+	l.broadcast(end)
+	s.broadcastLobby(l)
+	s.broadcastLobbies()
+}
+
+func (s *Server) stopRound(l *lobby) {
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+}
+
+// tickLoop drives a snake round: it sleeps the shared tick interval, pops
+// one queued turn per player, broadcasts the tick and steps its own copy of
+// the round to find out when it is over.
+func (s *Server) tickLoop(l *lobby, stop <-chan struct{}) {
+	for {
+		s.mu.Lock()
+		wait := snakePace(l.round)
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+
+		s.mu.Lock()
+		select {
+		case <-stop:
+			s.mu.Unlock()
+			return
+		default:
+		}
+		turns := make([]string, len(l.turns))
+		for i := range l.turns {
+			turns[i] = l.turns[i].Pop()
+		}
+		tick := Message{Type: TypeTick, Tick: l.round.Tick + 1, Turns: turns, ElapsedMs: wait.Milliseconds()}
+		l.round, _ = snake.Step(l.round, snake.Input{Turns: turns, Elapsed: wait})
+		l.broadcast(tick)
+
+		alive := make([]int, 0, len(l.round.Snakes))
+		for i, sn := range l.round.Snakes {
+			if sn.Alive() {
+				alive = append(alive, i)
+			}
+		}
+		if len(alive) <= 1 {
+			end := Message{Type: TypeEnd, Reason: "round"}
+			if len(alive) == 1 {
+				end.Winner = l.players[alive[0]].name
+				l.wins[alive[0]]++
+			}
+			s.finish(l, end)
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+	}
+}
+
+// snakePace is the shared tick interval of a round: the fastest snake sets
+// the speed and any slow-motion pickup slows the whole field. It is always
+// a whole number of milliseconds so "elapsed_ms" carries it exactly.
+func snakePace(st snake.State) time.Duration {
+	pace := snake.Snake{Speed: 1}
+	for _, sn := range st.Snakes {
+		if !sn.Alive() {
+			continue
+		}
+		pace.Speed = min(pace.Speed, sn.Speed)
+		pace.SlowLeft = max(pace.SlowLeft, sn.SlowLeft)
+	}
+	field := snake.State{Width: st.Width, Height: st.Height, Snakes: []snake.Snake{pace}}
+	return field.TickInterval(0).Truncate(time.Millisecond)
+}
+
+func (s *Server) queueTurn(p *peer, dir string) error {
+	l := p.lobby
+	if l == nil || !l.running || l.game != GameSnake {
+		return errors.New("no snake round running")
+	}
+	i := l.index(p)
+	l.turns[i].Push(dir, l.round.Snakes[i].Heading)
+	return nil
+}
+
+// move relays the move of the player whose turn it is. The mover says who
+// moves next: most games alternate, but in some a player without a move
+// passes and the other goes again.
+func (s *Server) move(p *peer, m Message) error {
+	l := p.lobby
+	if l == nil || !l.running || l.game == GameSnake {
+		return errors.New("no turn game running")
+	}
+	if l.index(p) != l.turn {
+		return errors.New("not your turn")
+	}
+	if m.Turn < 0 || m.Turn >= len(l.players) {
+		return fmt.Errorf("no player %d to move next", m.Turn)
+	}
+	l.seq++
+	l.turn = m.Turn
+	l.broadcast(Message{Type: TypeMove, From: p.name, Seq: l.seq, Move: m.Move, Turn: l.turn})
+	return nil
+}
+
+// endTurnGame takes a player's word on how the game ended. The game ends
+// once every player has sent theirs; they must name the same winner, or
+// nobody wins.
+func (s *Server) endTurnGame(p *peer, m Message) error {
+	l := p.lobby
+	if l == nil || !l.running || l.game == GameSnake {
+		return errors.New("no turn game running")
+	}
+	winner := -1
+	if m.Winner != "" {
+		if winner = slices.Index(l.names(), m.Winner); winner < 0 {
+			return fmt.Errorf("%q is not playing", m.Winner)
+		}
+	}
+	l.claims[l.index(p)] = &Message{Winner: m.Winner, Reason: m.Reason}
+	for _, c := range l.claims {
+		if c == nil {
+			return nil // waiting for the others
+		}
+		if c.Winner != m.Winner {
+			s.finish(l, Message{Type: TypeEnd, Reason: ReasonDisagree})
+			return nil
+		}
+	}
+	if winner >= 0 {
+		l.wins[winner]++
+	}
+	s.finish(l, Message{Type: TypeEnd, Winner: m.Winner, Reason: l.claims[0].Reason})
+	return nil
+}
@@ -0,0 +1,250 @@
+package netplay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"gamics/games/snake"
+	"net"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+const waitFor = 5 * time.Second
+
+// serve starts a server on a free loopback port.
+func serve(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go NewServer().Serve(l)
+	t.Cleanup(func() { l.Close() })
+	return l.Addr().String()
+}
+
+func dial(t *testing.T, addr, name string) *Client {
+	t.Helper()
+	c, err := Dial(addr, name, waitFor)
+	if err != nil {
+		t.Fatalf("Dial(%s): %v", name, err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func send(t *testing.T, c *Client, m Message) {
+	t.Helper()
+	if err := c.Send(m); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expect skips messages until one of type typ arrives and returns it.
+func expect(t *testing.T, c *Client, typ string) Message {
+	t.Helper()
+	timeout := time.After(waitFor)
+	for {
+		select {
+		case m, ok := <-c.Messages():
+			if !ok {
+				t.Fatalf("%s: connection closed waiting for %q", c.Name, typ)
+			}
+			if m.Type == typ {
+				return m
+			}
+		case <-timeout:
+			t.Fatalf("%s: no %q message", c.Name, typ)
+		}
+	}
+}
+
+// expectLobby waits for the lobby update that satisfies ok.
+func expectLobby(t *testing.T, c *Client, ok func(Message) bool) Message {
+	t.Helper()
+	for {
+		if m := expect(t, c, TypeLobby); ok(m) {
+			return m
+		}
+	}
+}
+
+// startGame has ana create a lobby for game, bo join it and both get
+// ready, and returns the "start" message each of them got.
+func startGame(t *testing.T, game string) (ana, bo *Client, start Message) {
+	t.Helper()
+	addr := serve(t)
+	ana, bo = dial(t, addr, "ana"), dial(t, addr, "bo")
+
+	send(t, ana, Message{Type: TypeCreate, Lobby: "friday", Game: game})
+	expectLobby(t, ana, func(m Message) bool { return slices.Equal(m.Players, []string{"ana"}) })
+	send(t, bo, Message{Type: TypeJoin, Lobby: "friday"})
+	expectLobby(t, bo, func(m Message) bool { return slices.Equal(m.Players, []string{"ana", "bo"}) })
+	expectLobby(t, ana, func(m Message) bool { return len(m.Players) == 2 })
+
+	send(t, ana, Message{Type: TypeReady})
+	send(t, bo, Message{Type: TypeReady})
+	start = expect(t, ana, TypeStart)
+	if other := expect(t, bo, TypeStart); other.Seed != start.Seed || !slices.Equal(other.Players, start.Players) {
+		t.Fatalf("players got different starts: %+v and %+v", start, other)
+	}
+	if start.Game != game || !slices.Equal(start.Players, []string{"ana", "bo"}) {
+		t.Fatalf("start = %+v", start)
+	}
+	return ana, bo, start
+}
+
+func TestHandshake(t *testing.T) {
+	addr := serve(t)
+	ana := dial(t, addr, "ana")
+	expect(t, ana, TypeLobbies)
+
+	if _, err := Dial(addr, "ana", waitFor); err == nil {
+		t.Error("a second ana was let in")
+	}
+	if _, err := Dial(addr, " ", waitFor); err == nil {
+		t.Error("a blank name was let in")
+	}
+}
+
+func TestSnakeRound(t *testing.T) {
+	ana, bo, start := startGame(t, GameSnake)
+	if start.Width != SnakeWidth || start.Height != SnakeHeight {
+		t.Fatalf("field %dx%d, want %dx%d", start.Width, start.Height, SnakeWidth, SnakeHeight)
+	}
+
+	// ana's snake starts a third of the way down, so turning up runs it
+	// into the wall in a few ticks.
+	send(t, ana, Message{Type: TypeTurn, Direction: "up"})
+	round := NewSnakeRound(start)
+	for {
+		select {
+		case m := <-bo.Messages():
+			switch m.Type {
+			case TypeTick:
+				if m.Tick != round.Tick+1 {
+					t.Fatalf("tick %d after tick %d", m.Tick, round.Tick)
+				}
+				round = stepRound(round, m)
+			case TypeEnd:
+				if m.Winner != "bo" || m.Reason != "round" {
+					t.Fatalf("end = %+v, want bo winning the round", m)
+				}
+				if round.Snakes[0].Alive() || !round.Snakes[1].Alive() {
+					t.Errorf("bo's copy of the round disagrees with the server: %+v", round.Snakes)
+				}
+				expectLobby(t, ana, func(m Message) bool { return slices.Equal(m.Wins, []int{0, 1}) && !m.Running })
+				return
+			}
+		case <-time.After(waitFor):
+			t.Fatal("the round did not end")
+		}
+	}
+}
+
+func stepRound(st snake.State, tick Message) snake.State {
+	st, _ = snake.Step(st, snake.Input{Turns: tick.Turns, Elapsed: time.Duration(tick.ElapsedMs) * time.Millisecond})
+	return st
+}
+
+func TestTurnGame(t *testing.T) {
+	ana, bo, _ := startGame(t, "ticTacToe")
+
+	move := func(c *Client, mv string, next int) {
+		t.Helper()
+		payload, _ := json.Marshal(mv)
+		send(t, c, Message{Type: TypeMove, Move: payload, Turn: next})
+		for _, peer := range []*Client{ana, bo} {
+			m := expect(t, peer, TypeMove)
+			var got string
+			if json.Unmarshal(m.Move, &got); got != mv || m.From != c.Name || m.Turn != next {
+				t.Fatalf("%s got %+v, want %s's %s", peer.Name, m, c.Name, mv)
+			}
+		}
+	}
+
+	move(ana, "b2", 1)
+	send(t, ana, Message{Type: TypeMove, Move: json.RawMessage(`"a1"`), Turn: 1})
+	if m := expect(t, ana, TypeError); m.Text != "not your turn" {
+		t.Errorf("move out of turn: %q", m.Text)
+	}
+	move(bo, "a1", 0)
+
+	// ana claims a win; the game goes on until bo agrees.
+	send(t, ana, Message{Type: TypeEnd, Winner: "ana", Reason: "three in a row"})
+	send(t, bo, Message{Type: TypeEnd, Winner: "ana", Reason: "three in a row"})
+	for _, c := range []*Client{ana, bo} {
+		if m := expect(t, c, TypeEnd); m.Winner != "ana" || m.Reason != "three in a row" {
+			t.Errorf("%s got end %+v", c.Name, m)
+		}
+	}
+	expectLobby(t, bo, func(m Message) bool { return slices.Equal(m.Wins, []int{1, 0}) && !m.Running })
+
+	// A rematch the players disagree on has no winner.
+	send(t, ana, Message{Type: TypeReady})
+	send(t, bo, Message{Type: TypeReady})
+	expect(t, ana, TypeStart)
+	send(t, ana, Message{Type: TypeEnd, Winner: "ana"})
+	send(t, bo, Message{Type: TypeEnd, Winner: "bo"})
+	if m := expect(t, ana, TypeEnd); m.Winner != "" || m.Reason != ReasonDisagree {
+		t.Errorf("disputed end = %+v", m)
+	}
+	expectLobby(t, ana, func(m Message) bool { return slices.Equal(m.Wins, []int{1, 0}) && !m.Running })
+}
+
+func TestTurnGameIsForTwo(t *testing.T) {
+	addr := serve(t)
+	ana, bo, cy := dial(t, addr, "ana"), dial(t, addr, "bo"), dial(t, addr, "cy")
+	send(t, ana, Message{Type: TypeCreate, Lobby: "friday", Game: "checkers"})
+	expect(t, ana, TypeLobby)
+	send(t, bo, Message{Type: TypeJoin, Lobby: "friday"})
+	expect(t, bo, TypeLobby)
+	send(t, cy, Message{Type: TypeJoin, Lobby: "friday"})
+	if m := expect(t, cy, TypeError); m.Text != `lobby "friday" is full` {
+		t.Errorf("third player: %q", m.Text)
+	}
+}
+
+// TestSlowPeer checks that a peer which stops reading is dropped without
+// holding up the others.
+func TestSlowPeer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	s := NewServer()
+	go s.Serve(l)
+	ana := dial(t, l.Addr().String(), "ana")
+
+	// A pipe has no buffer: once the peer stops reading, every write blocks.
+	conn, slow := net.Pipe()
+	defer slow.Close()
+	go s.handle(conn)
+	json.NewEncoder(slow).Encode(Message{Type: TypeHello, Name: "slow"})
+	if !bufio.NewScanner(slow).Scan() {
+		t.Fatal("no welcome")
+	}
+
+	// Outside a lobby, chat goes to everybody, the slow peer included.
+	for i := range outboxSize + 10 {
+		send(t, ana, Message{Type: TypeChat, Text: "hi"})
+		if m := expect(t, ana, TypeChat); m.From != "ana" {
+			t.Fatalf("chat %d came back as %+v", i, m)
+		}
+	}
+
+	slow.SetReadDeadline(time.Now().Add(waitFor))
+	buf := make([]byte, 4096)
+	for {
+		if _, err := slow.Read(buf); err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				t.Fatal("the slow peer is still connected")
+			}
+			return
+		}
+	}
+}
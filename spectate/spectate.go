@@ -0,0 +1,211 @@
+/*
+Package spectate lets other processes on the same machine watch a game.
+
+A running game opens a Broadcaster on a unix socket and publishes a frame
+whenever what it shows changes. Watchers connect with Dial and receive
+every frame as one JSON line; a watcher that joins late gets the latest
+frame right away. Frames are whole snapshots, so a watcher that cannot
+keep up simply misses some of them.
+
+The socket of a player lives at SocketPath(root, player), so that
+"gamics watch <player>" can find it.
+*/
+package spectate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	watcherBuffer = 16
+	writeTimeout  = 2 * time.Second
+	maxFrameBytes = 1 << 20
+)
+
+// SocketPath is where the games of player are broadcast.
+func SocketPath(root, player string) string {
+	return path.Join(root, player, "watch.sock")
+}
+
+// ErrBusy is returned by Listen when another live game already owns the
+// socket.
+var ErrBusy = errors.New("another game is already broadcasting")
+
+// ----------------------------------------------------------------------------------
+// Broadcaster
+// ----------------------------------------------------------------------------------
+
+// Broadcaster sends frames to every connected watcher.
+type Broadcaster struct {
+	path string
+	l    net.Listener
+
+	mu       sync.Mutex
+	last     []byte
+	watchers map[chan []byte]struct{}
+	closed   bool
+}
+
+// Listen opens the socket at sockPath. A socket left behind by a game that
+// is gone is replaced.
+func Listen(sockPath string) (*Broadcaster, error) {
+	l, err := net.Listen("unix", sockPath)
+	if err != nil {
+		if conn, dialErr := net.Dial("unix", sockPath); dialErr == nil {
+			conn.Close()
+			return nil, ErrBusy
+		}
+		os.Remove(sockPath)
+		if l, err = net.Listen("unix", sockPath); err != nil {
+			return nil, fmt.Errorf("could not listen on %s: %w", sockPath, err)
+		}
+	}
+
+	b := &Broadcaster{path: sockPath, l: l, watchers: make(map[chan []byte]struct{})}
+	go b.accept()
+	return b, nil
+}
+
+func (b *Broadcaster) accept() {
+	for {
+		conn, err := b.l.Accept()
+		if err != nil {
+			return
+		}
+
+		ch := make(chan []byte, watcherBuffer)
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if b.last != nil {
+			ch <- b.last
+		}
+		b.watchers[ch] = struct{}{}
+		b.mu.Unlock()
+
+		go b.serve(conn, ch)
+	}
+}
+
+// serve writes frames to one watcher until it goes away or the broadcast
+// ends.
+func (b *Broadcaster) serve(conn net.Conn, ch chan []byte) {
+	defer conn.Close()
+	for frame := range ch {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := conn.Write(frame); err != nil {
+			b.drop(ch)
+			return
+		}
+	}
+}
+
+func (b *Broadcaster) drop(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.watchers[ch]; ok {
+		delete(b.watchers, ch)
+		close(ch)
+	}
+}
+
+// Publish sends v, encoded as JSON, to every watcher. A frame equal to the
+// previous one is not sent again.
+func (b *Broadcaster) Publish(v any) error {
+	frame, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not encode frame: %w", err)
+	}
+	frame = append(frame, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed || bytes.Equal(frame, b.last) {
+		return nil
+	}
+	b.last = frame
+	for ch := range b.watchers {
+		select {
+		case ch <- frame:
+		default: // too slow, it gets a later frame
+		}
+	}
+	return nil
+}
+
+// Watchers is how many watchers are connected.
+func (b *Broadcaster) Watchers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.watchers)
+}
+
+// Close ends the broadcast: watchers are disconnected and the socket is
+// removed.
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for ch := range b.watchers {
+		delete(b.watchers, ch)
+		close(ch)
+	}
+	b.mu.Unlock()
+
+	err := b.l.Close()
+	os.Remove(b.path)
+	return err
+}
+
+// ----------------------------------------------------------------------------------
+// Watcher
+// ----------------------------------------------------------------------------------
+
+// Watcher receives the frames of one broadcast.
+type Watcher struct {
+	conn   net.Conn
+	frames chan json.RawMessage
+}
+
+// Dial connects to the broadcast at sockPath.
+func Dial(sockPath string) (*Watcher, error) {
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %w", sockPath, err)
+	}
+
+	w := &Watcher{conn: conn, frames: make(chan json.RawMessage, watcherBuffer)}
+	go w.read()
+	return w, nil
+}
+
+func (w *Watcher) read() {
+	defer close(w.frames)
+	sc := bufio.NewScanner(w.conn)
+	sc.Buffer(make([]byte, 64*1024), maxFrameBytes)
+	for sc.Scan() {
+		w.frames <- json.RawMessage(bytes.Clone(sc.Bytes()))
+	}
+}
+
+// Frames delivers every frame received. The channel closes when the
+// broadcast ends.
+func (w *Watcher) Frames() <-chan json.RawMessage { return w.frames }
+
+// Close stops watching.
+func (w *Watcher) Close() error { return w.conn.Close() }
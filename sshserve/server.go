@@ -0,0 +1,351 @@
+/*
+Package sshserve serves Bubble Tea programs over SSH.
+
+Every SSH session that asks for a terminal and a shell gets its own
+program, built for the authenticated user. The size of the client's pty is
+delivered to the program as a tea.WindowSizeMsg, first when the shell
+starts and again on every resize. Sessions without a pty, and exec
+requests, are refused.
+
+Each program gets a lipgloss renderer of its own, set up for the client's
+terminal from its TERM and the environment it sends, rather than for the
+terminal the server was started from.
+
+The server never runs a real shell: the only thing a user can do over SSH
+is play.
+*/
+package sshserve
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"golang.org/x/crypto/ssh"
+)
+
+// Server accepts SSH connections and runs one program per session.
+type Server struct {
+	// Password and PublicKey authenticate a user; a nil callback disables
+	// that method. They may be called from several connections at once.
+	Password  func(user, password string) bool
+	PublicKey func(user string, key ssh.PublicKey) bool
+
+	// NewModel builds the program of an authenticated user, who sees it
+	// through r.
+	NewModel func(user string, r *lipgloss.Renderer) tea.Model
+
+	Log *log.Logger // nil discards the log
+
+	config *ssh.ServerConfig
+}
+
+// NewServer returns a server that identifies itself with the host key at
+// hostKeyPath, generating an ed25519 key there on first use.
+func NewServer(hostKeyPath string) (*Server, error) {
+	signer, err := loadHostKey(hostKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	s.config = &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if s.Password == nil || !s.Password(conn.User(), string(password)) {
+				return nil, fmt.Errorf("password rejected for %s", conn.User())
+			}
+			return nil, nil
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if s.PublicKey == nil || !s.PublicKey(conn.User(), key) {
+				return nil, fmt.Errorf("public key rejected for %s", conn.User())
+			}
+			return nil, nil
+		},
+	}
+	s.config.AddHostKey(signer)
+	return s, nil
+}
+
+// ListenAndServe listens on addr and serves until the listener fails.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("could not listen on %s: %w", addr, err)
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until it is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.logf("listening on %s", l.Addr())
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("could not accept connection: %w", err)
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Connections
+// ----------------------------------------------------------------------------------
+func (s *Server) handle(nc net.Conn) {
+	defer nc.Close()
+
+	conn, chans, reqs, err := ssh.NewServerConn(nc, s.config)
+	if err != nil {
+		s.logf("%s: handshake failed: %v", nc.RemoteAddr(), err)
+		return
+	}
+	defer conn.Close()
+	s.logf("%s: %s connected", conn.RemoteAddr(), conn.User())
+	defer s.logf("%s: %s disconnected", conn.RemoteAddr(), conn.User())
+
+	go ssh.DiscardRequests(reqs)
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			nch.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, requests, err := nch.Accept()
+		if err != nil {
+			s.logf("%s: could not accept session: %v", conn.RemoteAddr(), err)
+			continue
+		}
+		go s.session(conn.User(), ch, requests)
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Sessions
+// ----------------------------------------------------------------------------------
+
+// ptyRequest is the payload of "pty-req" (RFC 4254, section 6.2).
+type ptyRequest struct {
+	Term    string
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+	Modes   string
+}
+
+// envRequest is the payload of "env" (RFC 4254, section 6.4).
+type envRequest struct {
+	Name  string
+	Value string
+}
+
+// windowChange is the payload of "window-change" (RFC 4254, section 6.7).
+type windowChange struct {
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+}
+
+func (s *Server) session(user string, ch ssh.Channel, requests <-chan *ssh.Request) {
+	defer ch.Close()
+
+	var (
+		mu      sync.Mutex
+		size    tea.WindowSizeMsg
+		hasPty  bool
+		env     sessionEnv
+		program *tea.Program
+		done    = make(chan struct{})
+	)
+	resize := func(cols, rows uint32) {
+		mu.Lock()
+		defer mu.Unlock()
+		size = tea.WindowSizeMsg{Width: int(cols), Height: int(rows)}
+		if p := program; p != nil {
+			go p.Send(size)
+		}
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case req, ok := <-requests:
+			if !ok {
+				// The client hung up; stop the program if it is still running.
+				mu.Lock()
+				if program != nil {
+					program.Kill()
+				}
+				mu.Unlock()
+				return
+			}
+
+			switch req.Type {
+			case "pty-req":
+				var pty ptyRequest
+				if err := ssh.Unmarshal(req.Payload, &pty); err != nil {
+					req.Reply(false, nil)
+					continue
+				}
+				hasPty = true
+				env = append(env, "TERM="+pty.Term)
+				resize(pty.Columns, pty.Rows)
+				req.Reply(true, nil)
+
+			case "env":
+				var e envRequest
+				ok := ssh.Unmarshal(req.Payload, &e) == nil && e.Name != "TERM"
+				if ok {
+					env = append(env, e.Name+"="+e.Value)
+				}
+				if req.WantReply {
+					req.Reply(ok, nil)
+				}
+
+			case "window-change":
+				var wc windowChange
+				if err := ssh.Unmarshal(req.Payload, &wc); err == nil {
+					resize(wc.Columns, wc.Rows)
+				}
+
+			case "shell":
+				mu.Lock()
+				started := program != nil
+				mu.Unlock()
+				if started {
+					req.Reply(false, nil)
+					continue
+				}
+				if !hasPty {
+					req.Reply(true, nil)
+					fmt.Fprint(ch.Stderr(), "gamics needs a terminal, connect with ssh -t\r\n")
+					exit(ch, 1)
+					return
+				}
+				req.Reply(true, nil)
+
+				mu.Lock()
+				program = tea.NewProgram(s.NewModel(user, MakeRenderer(ch, env)),
+					tea.WithInput(ch),
+					tea.WithOutput(ch),
+					tea.WithAltScreen(),
+					tea.WithoutSignalHandler(),
+				)
+				p, initial := program, size
+				mu.Unlock()
+
+				go p.Send(initial)
+				go func() {
+					var status uint32
+					if _, err := p.Run(); err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+						s.logf("%s: program failed: %v", user, err)
+						status = 1
+					}
+					exit(ch, status)
+					close(done)
+				}()
+
+			default:
+				// exec, subsystems and the like
+				if req.WantReply {
+					req.Reply(false, nil)
+				}
+			}
+		}
+	}
+}
+
+// MakeRenderer returns a renderer for a session that writes to w, its
+// colors picked from the TERM, COLORTERM and the like in environ. The
+// client's terminal cannot be queried from here, so its background is
+// taken from COLORFGBG, or else assumed dark.
+func MakeRenderer(w io.Writer, environ []string) *lipgloss.Renderer {
+	return lipgloss.NewRenderer(w,
+		termenv.WithEnvironment(sessionEnv(environ)),
+		termenv.WithUnsafe(),
+		termenv.WithColorCache(true),
+	)
+}
+
+// sessionEnv is the environment a client sent, as KEY=value pairs, later
+// ones winning.
+type sessionEnv []string
+
+func (e sessionEnv) Environ() []string { return e }
+
+func (e sessionEnv) Getenv(key string) string {
+	for i := len(e) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(e[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// exit reports the exit status and closes the channel.
+func exit(ch ssh.Channel, status uint32) {
+	ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+	ch.Close()
+}
+
+// ----------------------------------------------------------------------------------
+// Host key
+// ----------------------------------------------------------------------------------
+func loadHostKey(keyPath string) (ssh.Signer, error) {
+	pemBytes, err := os.ReadFile(keyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if pemBytes, err = generateHostKey(keyPath); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read host key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse host key %s: %w", keyPath, err)
+	}
+	return signer, nil
+}
+
+func generateHostKey(keyPath string) ([]byte, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate host key: %w", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "gamics host key")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode host key: %w", err)
+	}
+	pemBytes := pem.EncodeToMemory(block)
+
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0755); err != nil {
+		return nil, fmt.Errorf("could not create host key directory: %w", err)
+	}
+	if err := os.WriteFile(keyPath, pemBytes, 0600); err != nil {
+		return nil, fmt.Errorf("could not write host key: %w", err)
+	}
+	return pemBytes, nil
+}
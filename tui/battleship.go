@@ -0,0 +1,489 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/battleship"
+	"gamics/games/turn"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	battleshipTitle    = "Battleship"
+	battleshipCPUDelay = 600 * time.Millisecond // lets the player see their shot land first
+	// Squares are drawn bsCellWidth wide after a row label of
+	// bsLabelWidth.
+	bsCellWidth  = 3
+	bsLabelWidth = 3
+)
+
+var (
+	bsWaterStyle = lipgloss.NewStyle().Background(lipgloss.Color("#1B3A5C")).Foreground(lipgloss.Color("#5C7FA3"))
+	bsShipStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#1B3A5C")).Foreground(lipgloss.Color("#B0B0B0"))
+	bsMissStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#1B3A5C")).Foreground(lipgloss.Color("#FFFFFF"))
+	bsHitStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#1B3A5C")).Foreground(lipgloss.Color("#FF8C00")).Bold(true)
+	bsSunkStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#7A1F1F")).Foreground(lipgloss.Color("#FFD0D0")).Bold(true)
+	bsGoodStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#2E7D32")).Foreground(lipgloss.Color("#FFFFFF"))
+	bsBadStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#B00020")).Foreground(lipgloss.Color("#FFFFFF"))
+)
+
+func init() {
+	initTableGames[BATTLESHIP_UI] = func() tea.Cmd { return func() tea.Msg { return battleshipStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type battleshipStartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// BattleshipModel is a game against the CPU: the fleet and level are
+// picked from menus, the player places their ships by hand or at random,
+// then both sides take turns firing a shot.
+type BattleshipModel struct {
+	Status   string // "fleet", "level", "place", "playing", "over"
+	Menu     Options
+	Fleet    battleship.Fleet
+	Level    string
+	Mine     battleship.Board // the player's ocean, fired at by the CPU
+	Theirs   battleship.Board // the CPU's ocean
+	Cursor   battleship.Point
+	Vertical bool // the ship being placed runs down
+	// Shots are the last shot of each side, "" before their first.
+	Shots   [2]string
+	Message string
+	Best    bool // the win took the fewest shots yet
+	Seed    uint64
+	Loop    gameLoop
+	User    string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewBattleshipModel lists the fleets.
+func NewBattleshipModel(user string) (BattleshipModel, error) {
+	bm := BattleshipModel{Status: "fleet", User: user, Fleet: battleship.Classic, Loop: gameLoop{Name: BATTLESHIP_UI}}
+	var err error
+	bm.Menu, err = bm.fleetMenu()
+	return bm, err
+}
+
+func (bm *BattleshipModel) fleetMenu() (Options, error) {
+	var items []Option
+	cursor := 0
+	for i, f := range battleship.Fleets {
+		if f.Name == bm.Fleet.Name {
+			cursor = i
+		}
+		info := fmt.Sprintf("%d×%d · %d ships", f.Size, f.Size, len(f.Ships))
+		if f.NoTouch {
+			info += " · ships may not touch"
+		}
+		line, err := bsRecordLine(bm.User, f)
+		if err != nil {
+			return Options{}, err
+		}
+		if line != "" {
+			info += " · " + line
+		}
+		items = append(items, Option{Text: fmt.Sprintf("%-8s %s", f.Name, turnMutedStyle.Render(info)), Action: func(m model) model {
+			m.battleship.Fleet = f
+			m.battleship.Status = "level"
+			m.battleship.Menu = m.battleship.levelMenu()
+			return m
+		}})
+	}
+	return Options{Prompt: "Battleship. Which fleet?", Items: items, Cursor: cursor}, nil
+}
+
+func (bm *BattleshipModel) levelMenu() Options {
+	about := map[string]string{
+		turn.AIEasy:   "fires at random, then around its hits",
+		turn.AIMedium: "fires where ships are likely to be",
+		turn.AIHard:   "fires where ships are most likely to be",
+	}
+	var items []Option
+	cursor := 0
+	for i, level := range []string{turn.AIEasy, turn.AIMedium, turn.AIHard} {
+		if level == bm.Level {
+			cursor = i
+		}
+		items = append(items, Option{Text: fmt.Sprintf("%-7s %s", level, turnMutedStyle.Render(about[level])), Action: func(m model) model {
+			m.battleship.Level = level
+			m.battleship.place()
+			return m
+		}})
+	}
+	return Options{Prompt: fmt.Sprintf("%s fleet. How good is the CPU?", bm.Fleet.Name), Items: items, Cursor: cursor}
+}
+
+// place deals the CPU's fleet and lets the player place theirs.
+func (bm *BattleshipModel) place() {
+	bm.Seed = uint64(time.Now().UnixNano())
+	r := rand.New(rand.NewPCG(bm.Seed, bm.Seed>>1))
+	bm.Theirs = battleship.NewBoard(bm.Fleet).RandomFleet(r)
+	bm.Mine = battleship.NewBoard(bm.Fleet)
+	bm.Status, bm.Cursor, bm.Vertical = "place", battleship.Point{}, false
+	bm.Shots, bm.Message, bm.Best = [2]string{}, "", false
+	bm.Loop.Stop()
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) BattleshipUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	bm := &m.battleship
+
+	switch msg := msg.(type) {
+	case battleshipStartMsg:
+		m.battleship, m.err = NewBattleshipModel(m.user())
+		return m, nil
+
+	case loopMsg:
+		if !bm.Loop.Owns(msg) || msg.Timer != timerCPU || bm.Status != "playing" {
+			return m, nil
+		}
+		m.err = bm.cpuFire()
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			bm.Loop.Stop()
+			switch bm.Status {
+			case "fleet":
+				m.currentUI = LIST_GAMES_UI
+			case "level":
+				bm.Status = "fleet"
+				bm.Menu, m.err = bm.fleetMenu()
+			default:
+				bm.Status, bm.Menu = "level", bm.levelMenu()
+			}
+			return m, nil
+		case "q":
+			if bm.Status != "place" && bm.Status != "playing" {
+				return m, tea.Quit
+			}
+		}
+
+		switch bm.Status {
+		case "fleet", "level":
+			updateOptions(&bm.Menu, key, &m)
+		case "place":
+			bm.updatePlace(key)
+		case "playing":
+			var cmd tea.Cmd
+			cmd, m.err = bm.updatePlaying(key)
+			return m, cmd
+		case "over":
+			if key == "n" || key == "enter" {
+				bm.place()
+			}
+		}
+	}
+	return m, nil
+}
+
+// moveCursor moves the cursor with the arrow keys, wrapping at the edges.
+func (bm *BattleshipModel) moveCursor(key string) bool {
+	size := bm.Fleet.Size
+	switch key {
+	case "up", "k":
+		bm.Cursor.Row = (bm.Cursor.Row + size - 1) % size
+	case "down", "j":
+		bm.Cursor.Row = (bm.Cursor.Row + 1) % size
+	case "left", "h":
+		bm.Cursor.Col = (bm.Cursor.Col + size - 1) % size
+	case "right", "l":
+		bm.Cursor.Col = (bm.Cursor.Col + 1) % size
+	default:
+		return false
+	}
+	return true
+}
+
+func (bm *BattleshipModel) updatePlace(key string) {
+	if bm.moveCursor(key) {
+		return
+	}
+	bm.Message = ""
+	switch key {
+	case "r":
+		bm.Vertical = !bm.Vertical
+	case "x":
+		r := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), bm.Seed))
+		if bm.Mine.Placed() {
+			bm.Mine = battleship.NewBoard(bm.Fleet) // place them all again
+		}
+		bm.Mine = bm.Mine.RandomFleet(r)
+	case "u":
+		bm.Mine = bm.Mine.Unplace()
+	case "enter", " ":
+		if bm.Mine.Placed() {
+			bm.Status, bm.Cursor = "playing", battleship.Point{}
+			return
+		}
+		next, err := bm.Mine.Place(battleship.Placement{Point: bm.Cursor, Vertical: bm.Vertical})
+		if err != nil {
+			bm.Message = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "."
+			return
+		}
+		bm.Mine = next
+	}
+}
+
+// updatePlaying fires at the square under the cursor, then lets the CPU
+// answer after a moment.
+func (bm *BattleshipModel) updatePlaying(key string) (tea.Cmd, error) {
+	if bm.moveCursor(key) || (key != "enter" && key != " ") {
+		return nil, nil
+	}
+	if bm.Theirs.Shots > bm.Mine.Shots {
+		return nil, nil // the CPU is aiming
+	}
+	next, shot, err := bm.Theirs.Fire(bm.Cursor)
+	if err != nil {
+		bm.Message = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "."
+		return nil, nil
+	}
+	bm.Theirs, bm.Message = next, ""
+	bm.Shots[0] = bsShotLine("You", bm.Fleet, shot)
+	if bm.Theirs.AllSunk() {
+		return nil, bm.finish(true)
+	}
+	bm.Loop.Start()
+	return bm.Loop.After(timerCPU, battleshipCPUDelay), nil
+}
+
+// cpuFire takes the CPU's shot at the player's fleet.
+func (bm *BattleshipModel) cpuFire() error {
+	ai := battleship.NewAI(bm.Level, bm.Seed+uint64(bm.Mine.Shots))
+	next, shot, err := bm.Mine.Fire(ai.Target(bm.Mine.Sight()))
+	if err != nil {
+		bm.Message = err.Error()
+		return nil
+	}
+	bm.Mine = next
+	bm.Shots[1] = bsShotLine("The CPU", bm.Fleet, shot)
+	if bm.Mine.AllSunk() {
+		return bm.finish(false)
+	}
+	return nil
+}
+
+// bsShotLine tells what a shot found, e.g. "You fired at B7: hit!".
+func bsShotLine(who string, fleet battleship.Fleet, shot battleship.Shot) string {
+	line := fmt.Sprintf("%s fired at %s: ", who, shot.At)
+	switch shot.Mark {
+	case battleship.Miss:
+		return line + "miss."
+	case battleship.Hit:
+		return line + "hit!"
+	}
+	return line + fmt.Sprintf("sank the %s!", fleet.Ships[shot.Ship].Name)
+}
+
+// ----------------------------------------------------------------------------------
+// Record
+// ----------------------------------------------------------------------------------
+
+// finish ends the game and counts it in profile.yaml as
+// battleship-<fleet>-wins or -losses, keeping the fewest shots a win took
+// in battleship-<fleet>-best.
+func (bm *BattleshipModel) finish(won bool) error {
+	bm.Status = "over"
+	bm.Loop.Stop()
+	prefix := "battleship-" + strings.ToLower(bm.Fleet.Name) + "-"
+	return updateProfile(bm.User, func(v *viper.Viper) {
+		if !won {
+			v.Set(prefix+"losses", v.GetInt(prefix+"losses")+1)
+			return
+		}
+		v.Set(prefix+"wins", v.GetInt(prefix+"wins")+1)
+		if best := v.GetInt(prefix + "best"); best == 0 || bm.Theirs.Shots < best {
+			v.Set(prefix+"best", bm.Theirs.Shots)
+			bm.Best = true
+		}
+	})
+}
+
+func bsRecordLine(user string, f battleship.Fleet) (string, error) {
+	p, err := loadProfile(user)
+	if err != nil {
+		return "", err
+	}
+	prefix := "battleship-" + strings.ToLower(f.Name) + "-"
+	wins, losses := p.GetInt(prefix+"wins"), p.GetInt(prefix+"losses")
+	if wins+losses == 0 {
+		return "", nil
+	}
+	line := fmt.Sprintf("won %d of %d", wins, wins+losses)
+	if best := p.GetInt(prefix + "best"); best > 0 {
+		line += fmt.Sprintf(", best %d shots", best)
+	}
+	return line, nil
+}
+
+func (m model) BattleshipView() string {
+	bm := m.battleship
+	switch bm.Status {
+	case "":
+		return ""
+	case "fleet", "level":
+		return viewOptions(bm.Menu, m.terminal)
+	}
+
+	var body, status, help string
+	switch bm.Status {
+	case "place":
+		body = lipgloss.JoinHorizontal(lipgloss.Top,
+			bsOcean("Your fleet", bm.Mine, true, &bm.Cursor, bm.ghost()), "   ", bm.placeList())
+		status = turnErrorStyle.Render(bm.Message)
+		help = "arrows move · r rotate · enter place · u take back · x place the rest at random · esc back"
+		if bm.Mine.Placed() {
+			status = turnStatusStyle.Render("Your fleet is ready.")
+			help = "enter start · u take back · x place again at random · esc back"
+		}
+	default:
+		over := bm.Status == "over"
+		var cursor *battleship.Point
+		if !over {
+			cursor = &bm.Cursor
+		}
+		body = lipgloss.JoinHorizontal(lipgloss.Top,
+			bsOcean("Your fleet", bm.Mine, true, nil, nil), "     ",
+			bsOcean("Enemy waters", bm.Theirs, over, cursor, nil))
+		lines := []string{}
+		for _, s := range bm.Shots {
+			if s != "" {
+				lines = append(lines, turnStatusStyle.Render(s))
+			}
+		}
+		switch {
+		case over && bm.Theirs.AllSunk():
+			won := fmt.Sprintf("You sank the enemy fleet in %d shots!", bm.Theirs.Shots)
+			if bm.Best {
+				won += " Your best yet."
+			}
+			lines = append(lines, turnStatusStyle.Bold(true).Render(won))
+		case over:
+			lines = append(lines, turnErrorStyle.Bold(true).Render("The CPU sank your fleet. Its ships are shown."))
+		case bm.Message != "":
+			lines = append(lines, turnErrorStyle.Render(bm.Message))
+		}
+		status = strings.Join(lines, "\n")
+		help = "arrows aim · enter fire · esc back · ctrl+c quit"
+		if over {
+			help = "n new game · esc back · q quit"
+		}
+	}
+
+	title := fmt.Sprintf("%s · %s fleet · CPU %s", battleshipTitle, bm.Fleet.Name, bm.Level)
+	content := lipgloss.JoinVertical(lipgloss.Left, body, "", status, "", turnMutedStyle.Render(help))
+	margin := max((m.terminal.Width-lipgloss.Width(content))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", horizontalCenterBox(turnTitleStyle, title, m.terminal),
+		lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// ghost is the next ship to place, at the cursor.
+func (bm BattleshipModel) ghost() *bsGhost {
+	if bm.Mine.Placed() {
+		return nil
+	}
+	p := battleship.Placement{Point: bm.Cursor, Vertical: bm.Vertical}
+	return &bsGhost{Cells: p.Cells(bm.Mine.Next().Len), Fits: bm.Mine.Check(p) == nil}
+}
+
+// bsGhost is a ship being placed, drawn over the board.
+type bsGhost struct {
+	Cells []battleship.Point
+	Fits  bool
+}
+
+// placeList lists the ships of the fleet, the placed ones ticked and the
+// next one marked.
+func (bm BattleshipModel) placeList() string {
+	lines := []string{turnStatusStyle.Bold(true).Render("Ships"), ""}
+	for i, ship := range bm.Fleet.Ships {
+		text := fmt.Sprintf("%-10s %s", ship.Name, strings.Repeat("■", ship.Len))
+		switch {
+		case i < len(bm.Mine.Ships):
+			text = turnMutedStyle.Render("✓ " + text)
+		case i == len(bm.Mine.Ships):
+			text = turnStatusStyle.Bold(true).Render("› " + text)
+		default:
+			text = "  " + text
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bsOcean draws a board under its title, with the ships shown if
+// reveal, the cursor and a ship being placed, then the fleet.
+func bsOcean(title string, b battleship.Board, reveal bool, cursor *battleship.Point, ghost *bsGhost) string {
+	size := b.Fleet.Size
+	var s strings.Builder
+	s.WriteString(turnStatusStyle.Bold(true).Render(title) + "\n\n")
+	s.WriteString(strings.Repeat(" ", bsLabelWidth))
+	for c := 0; c < size; c++ {
+		fmt.Fprintf(&s, " %c ", 'A'+c)
+	}
+	for r := 0; r < size; r++ {
+		fmt.Fprintf(&s, "\n%2d ", r+1)
+		for c := 0; c < size; c++ {
+			p := battleship.Point{Row: r, Col: c}
+			style, glyph := bsWaterStyle, "·"
+			switch b.Marks[r][c] {
+			case battleship.Miss:
+				style, glyph = bsMissStyle, "•"
+			case battleship.Hit:
+				style, glyph = bsHitStyle, "✕"
+			case battleship.Sunk:
+				style, glyph = bsSunkStyle, "✕"
+			default:
+				if reveal && b.ShipAt(p) >= 0 {
+					style, glyph = bsShipStyle, "■"
+				}
+			}
+			if ghost != nil && slices.Contains(ghost.Cells, p) {
+				style, glyph = bsBadStyle, "■"
+				if ghost.Fits {
+					style = bsGoodStyle
+				}
+			}
+			if cursor != nil && *cursor == p {
+				style = style.Inherit(turnCursorStyle)
+			}
+			s.WriteString(style.Render(" " + glyph + " "))
+		}
+	}
+
+	// The fleet, with the ships sunk crossed out.
+	ships := make([]string, len(b.Fleet.Ships))
+	for i, ship := range b.Fleet.Ships {
+		ships[i] = bsShipStyle.UnsetBackground().Render(strings.Repeat("■", ship.Len))
+		if i < len(b.Ships) && b.Sunk(i) {
+			ships[i] = bsSunkStyle.Render(strings.Repeat("✕", ship.Len))
+		}
+	}
+	s.WriteString("\n\n" + strings.Repeat(" ", bsLabelWidth) + strings.Join(ships, " "))
+	return s.String()
+}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/checkers"
+	"gamics/games/turn"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CheckersDepth is how many plies the hard Checkers CPU looks ahead. It is
+// set from "checkers-depth" in .gamics/config.yaml.
+var CheckersDepth = checkers.DefaultDepth
+
+// Squares are drawn ckCellWidth wide, without labels: empty dark squares
+// show their number instead. Click relies on the same numbers.
+const ckCellWidth = 3
+
+var (
+	ckPieceStyles = map[string]lipgloss.Style{
+		"black": lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#000000")),
+		"white": lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")),
+	}
+	ckLightBg  = lipgloss.Color("#E8D1A7")
+	ckDarkBg   = lipgloss.Color("#8B5A2B")
+	ckLastBg   = lipgloss.Color("#A8783E")
+	ckPickBg   = lipgloss.Color("#2E7D32")
+	ckTargetBg = lipgloss.Color("#5E9C3A")
+	ckNumStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#C9A57A"))
+)
+
+// ckGame plays draughts under one rule set on the turn screens. A move is
+// made by picking a piece, then every square it lands on; the squares it
+// may go to next are lit. Matches are saved as PDN.
+type ckGame struct {
+	rules checkers.Rules
+}
+
+func (g ckGame) Title() string { return "Checkers · " + g.rules.Name }
+func (ckGame) Help() string {
+	return "arrows move · enter or click a piece, then where it lands"
+}
+func (g ckGame) New() turn.Game { return checkers.New(g.rules) }
+func (ckGame) NewAI(level string, seed uint64) turn.AI {
+	return checkers.NewAI(level, CheckersDepth, seed)
+}
+func (g ckGame) Seat(seat int) string { return g.rules.Seats[seat] }
+
+func (ckGame) Variants() []turnGame {
+	var games []turnGame
+	for _, r := range checkers.Variants {
+		games = append(games, ckGame{rules: r})
+	}
+	return games
+}
+
+func (ckGame) Ext() string { return checkers.PDNExt }
+
+func (g ckGame) Export(match turn.Match) string {
+	rec := checkers.Record{
+		Rules:  g.rules,
+		Event:  "Gamics",
+		Date:   time.Now().Format("2006.01.02"),
+		Result: checkers.ResultOf(match.Outcome()),
+	}
+	for i, p := range match.Players {
+		rec.Players[i] = p.Name
+	}
+	for _, r := range match.History {
+		rec.Moves = append(rec.Moves, r.Move)
+	}
+	return rec.PDN()
+}
+
+func (ckGame) Import(text string) (turnGame, []turn.Move, error) {
+	rec, err := checkers.ParsePDN(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ckGame{rules: rec.Rules}, rec.Moves, nil
+}
+
+// ckPicked is the path picked so far, as squares.
+func ckPicked(rules checkers.Rules, c turnCursor) []int {
+	path := make([]int, len(c.Picks))
+	for i, p := range c.Picks {
+		path[i] = rules.Square(p.Row, p.Col)
+	}
+	return path
+}
+
+// ckNext lists the legal steps that go on from the picked path, and the
+// squares they land on next.
+func ckNext(g checkers.Game, picked []int) (steps []checkers.Step, next map[int]bool) {
+	next = map[int]bool{}
+	for _, s := range g.Steps() {
+		if len(s.Path) > len(picked) && slices.Equal(s.Path[:len(picked)], picked) {
+			steps = append(steps, s)
+			next[s.Path[len(picked)]] = true
+		}
+	}
+	return steps, next
+}
+
+func (g ckGame) Board(tg turn.Game, cursor *turnCursor) string {
+	game := tg.(checkers.Game)
+	rules := game.Rules
+	var picked []int
+	next := map[int]bool{}
+	if cursor != nil && !game.Outcome().Over {
+		picked = ckPicked(rules, *cursor)
+		_, next = ckNext(game, picked)
+	}
+
+	var b strings.Builder
+	for r := 0; r < rules.Size; r++ {
+		if r > 0 {
+			b.WriteString("\n")
+		}
+		for c := 0; c < rules.Size; c++ {
+			sq := rules.Square(r, c)
+			style, cell := lipgloss.NewStyle().Background(ckLightBg), "   "
+			if sq >= 0 {
+				switch p := game.Board[sq]; {
+				case p == checkers.Empty:
+					style, cell = ckNumStyle, fmt.Sprintf("%2d ", sq+1)
+				case p.IsKing():
+					style, cell = ckPieceStyles[rules.Seats[p.Seat()]], " ◉ "
+				default:
+					style, cell = ckPieceStyles[rules.Seats[p.Seat()]], " ● "
+				}
+				switch {
+				case slices.Contains(picked, sq):
+					style = style.Background(ckPickBg)
+				case len(picked) > 0 && next[sq]:
+					style = style.Background(ckTargetBg)
+				case slices.Contains(game.Last, sq):
+					style = style.Background(ckLastBg)
+				default:
+					style = style.Background(ckDarkBg)
+				}
+			}
+			if cursor != nil && cursor.Row == r && cursor.Col == c {
+				style = style.Inherit(turnCursorStyle)
+			}
+			b.WriteString(style.Render(cell))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n\n%s %d  %s %d", rules.Seats[0], game.Count(0), rules.Seats[1], game.Count(1))
+	return b.String()
+}
+
+func (g ckGame) Key(tg turn.Game, c turnCursor, key string) (turnCursor, turn.Move) {
+	size := g.rules.Size
+	switch key {
+	case "up", "k":
+		c.Row = (c.Row + size - 1) % size
+	case "down", "j":
+		c.Row = (c.Row + 1) % size
+	case "left", "h":
+		c.Col = (c.Col + size - 1) % size
+	case "right", "l":
+		c.Col = (c.Col + 1) % size
+	case "enter", " ":
+		return ckPick(tg.(checkers.Game), c)
+	}
+	return c, ""
+}
+
+func (g ckGame) Click(tg turn.Game, c turnCursor, x, y int) (turnCursor, turn.Move) {
+	row, col := y, x/ckCellWidth
+	if x < 0 || y < 0 || row >= g.rules.Size || col >= g.rules.Size {
+		return c, ""
+	}
+	c.Row, c.Col = row, col
+	return ckPick(tg.(checkers.Game), c)
+}
+
+// ckPick picks the square under the cursor: a piece that can move starts
+// a path, a square it can land on extends it, and the path of a whole move
+// makes that move. Picking the piece again, or a square that goes nowhere,
+// starts over.
+func ckPick(g checkers.Game, c turnCursor) (turnCursor, turn.Move) {
+	sq := g.Rules.Square(c.Row, c.Col)
+	picked := ckPicked(g.Rules, c)
+	if sq < 0 {
+		return c, ""
+	}
+	if _, next := ckNext(g, picked); len(picked) > 0 && next[sq] {
+		picked = append(picked, sq)
+		c.Picks = append(slices.Clip(c.Picks), turnPos{Row: c.Row, Col: c.Col})
+		steps, _ := ckNext(g, picked)
+		for _, s := range g.Steps() {
+			if slices.Equal(s.Path, picked) {
+				return c, s.Move()
+			}
+		}
+		if len(steps) == 0 {
+			c.Picks = nil
+		}
+		return c, ""
+	}
+
+	again := len(picked) == 1 && picked[0] == sq
+	c.Picks = nil
+	if steps, _ := ckNext(g, []int{sq}); len(steps) > 0 && !again {
+		c.Picks = []turnPos{{Row: c.Row, Col: c.Col}}
+	}
+	return c, ""
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"gamics/games/connectfour"
+	"gamics/games/turn"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConnectFourDepth is how many plies the hard Connect Four CPU looks ahead.
+// It is set from "connect-four-depth" in .gamics/config.yaml.
+var ConnectFourDepth = connectfour.DefaultDepth
+
+// Columns are drawn cfCellWidth wide between single-character walls, under
+// a line of column numbers and a line where the next disc hangs; Click
+// relies on the same numbers.
+const (
+	cfCellWidth = 3
+	cfHeader    = 2
+)
+
+var (
+	cfDiscStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#B00020", Dark: "#FF3B3B"}),
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#B8860B", Dark: "#FFD700"}),
+	}
+	cfWallStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#0B2E6B", Dark: "#5C9DFF"})
+	cfWinStyle  = lipgloss.NewStyle().Background(lipgloss.AdaptiveColor{Light: "#2FC67D", Dark: "#135334"})
+)
+
+// cfGame plays Connect Four on the turn screens. Discs fall one row per
+// frame.
+type cfGame struct{}
+
+func (cfGame) Title() string  { return "Connect Four" }
+func (cfGame) Help() string   { return "←/→ column · enter or 1-7 drop" }
+func (cfGame) New() turn.Game { return connectfour.New() }
+func (cfGame) NewAI(level string, seed uint64) turn.AI {
+	return connectfour.NewAI(level, ConnectFourDepth, seed)
+}
+func (cfGame) Seat(seat int) string {
+	return []string{"red", "yellow"}[seat]
+}
+
+func (cfGame) Board(tg turn.Game, cursor *turnCursor) string {
+	g := tg.(connectfour.Game)
+	hanging := -1
+	if cursor != nil {
+		hanging = cursor.Col
+	}
+	return drawConnectFour(g, hanging, g.Turn)
+}
+
+func (cfGame) AnimFrames(tg turn.Game, mv turn.Move) int {
+	return tg.(connectfour.Game).Last.Row + 1
+}
+
+func (cfGame) BoardFrame(tg turn.Game, mv turn.Move, frame int) string {
+	g := tg.(connectfour.Game)
+	land := g.Last
+	seat := g.Cells[land.Row][land.Col] - 1
+	g.Cells[land.Row][land.Col] = 0
+
+	falling := connectfour.Point{Row: land.Row - frame, Col: land.Col}
+	if falling.Row < 0 {
+		return drawConnectFour(g, land.Col, seat)
+	}
+	g.Cells[falling.Row][falling.Col] = seat + 1
+	return drawConnectFour(g, -1, seat)
+}
+
+// drawConnectFour draws the board with a disc of seat hanging over column
+// hanging (none when -1) and the winning four highlighted.
+func drawConnectFour(g connectfour.Game, hanging, seat int) string {
+	win := map[connectfour.Point]bool{}
+	if line, ok := g.WinLine(); ok {
+		for _, p := range line {
+			win[p] = true
+		}
+	}
+	center := func(s string) string { return " " + s + " " }
+
+	lines := make([]string, 0, connectfour.Rows+cfHeader+1)
+	var nums, hang strings.Builder
+	for c := 0; c < connectfour.Cols; c++ {
+		nums.WriteString(" " + center(string(rune('1'+c))))
+		if c == hanging {
+			hang.WriteString(" " + cfDiscStyles[seat].Render(center("●")))
+		} else {
+			hang.WriteString(" " + center(" "))
+		}
+	}
+	lines = append(lines, turnMutedStyle.Render(nums.String()), hang.String())
+
+	wall := cfWallStyle.Render("│")
+	for r := 0; r < connectfour.Rows; r++ {
+		var row strings.Builder
+		row.WriteString(wall)
+		for c := 0; c < connectfour.Cols; c++ {
+			cell := center(" ")
+			style := lipgloss.NewStyle()
+			if v := g.Cells[r][c]; v != 0 {
+				cell = center("●")
+				style = cfDiscStyles[v-1]
+			}
+			if win[connectfour.Point{Row: r, Col: c}] {
+				style = style.Inherit(cfWinStyle)
+			}
+			row.WriteString(style.Render(cell) + wall)
+		}
+		lines = append(lines, row.String())
+	}
+	bottom := "╰" + strings.Repeat(strings.Repeat("─", cfCellWidth)+"┴", connectfour.Cols-1) + strings.Repeat("─", cfCellWidth) + "╯"
+	lines = append(lines, cfWallStyle.Render(bottom))
+	return strings.Join(lines, "\n")
+}
+
+func (cfGame) Key(tg turn.Game, c turnCursor, key string) (turnCursor, turn.Move) {
+	switch key {
+	case "left", "h":
+		c.Col = (c.Col + connectfour.Cols - 1) % connectfour.Cols
+	case "right", "l":
+		c.Col = (c.Col + 1) % connectfour.Cols
+	case "enter", " ", "down", "j":
+		return c, connectfour.ColumnMove(c.Col)
+	default:
+		if len(key) == 1 && key[0] >= '1' && key[0] < '1'+connectfour.Cols {
+			c.Col = int(key[0] - '1')
+			return c, connectfour.ColumnMove(c.Col)
+		}
+	}
+	return c, ""
+}
+
+func (cfGame) Click(tg turn.Game, c turnCursor, x, y int) (turnCursor, turn.Move) {
+	x -= 1 // left wall
+	if x < 0 || y < 0 || y > connectfour.Rows+cfHeader || x%(cfCellWidth+1) == cfCellWidth {
+		return c, ""
+	}
+	col := x / (cfCellWidth + 1)
+	if col >= connectfour.Cols {
+		return c, ""
+	}
+	c.Col = col
+	return c, connectfour.ColumnMove(col)
+}
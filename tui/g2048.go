@@ -0,0 +1,381 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"gamics/games/game2048"
+	"gamics/internal"
+	"math/bits"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	g2048Session    = "2048" // saved in 2048.yaml
+	g2048UndoDepth  = 100
+	g2048BestTile   = "2048-best-tile"
+	g2048BestScore  = "2048-best-score"
+	g2048TileWidth  = 7
+	g2048LargeTiles = 3 // tile height when the terminal is tall enough
+)
+
+var (
+	// g2048Colors holds the background of each tile from 2 to 2048: warm
+	// beige to orange for the small ones, then yellow up to the goal.
+	g2048Colors = append(
+		internal.InterpolateHexColors("#EEE4DA", "#F65E3B", 6),
+		internal.InterpolateHexColors("#EDCF72", "#EDC22E", 5)...,
+	)
+
+	g2048BoardStyle = lipgloss.NewStyle().Padding(0, 1).Background(lipgloss.Color("#BBADA0"))
+	g2048EmptyStyle = lipgloss.NewStyle().Background(lipgloss.Color("#CDC1B4"))
+	g2048Arrows     = map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→"}
+)
+
+func init() {
+	initTableGames[GAME_2048_UI] = func() tea.Cmd { return func() tea.Msg { return g2048StartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type g2048StartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// Game2048Model is a game of 2048. The board is saved to 2048.yaml after
+// every move; the undo history only lives as long as the screen.
+type Game2048Model struct {
+	State   game2048.State
+	History []game2048.State // states before each move, newest last
+	Status  string           // "start", "playing", "over"
+	Options Options
+	Preview bool   // arrows show a move before playing it
+	Pending string // direction being previewed
+	Best    int    // best tile on record
+	High    int    // best score on record
+	User    string
+	Message string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewGame2048Model offers to continue the saved game, if there is one, or
+// asks for a board size.
+func NewGame2048Model(user string) (Game2048Model, error) {
+	gm := Game2048Model{Status: "start", User: user}
+	p, err := loadProfile(user)
+	if err != nil {
+		return gm, err
+	}
+	gm.Best, gm.High = p.GetInt(g2048BestTile), p.GetInt(g2048BestScore)
+	if !sessionExists(user, g2048Session) {
+		gm.Options = g2048SizeOptions()
+		return gm, nil
+	}
+	gm.Options = Options{Prompt: "You have a 2048 game in progress. What do you want to do?", Items: []Option{
+		{Text: "Continue", Action: func(m model) model {
+			st, err := load2048(m.g2048.User)
+			if err != nil {
+				m.err = err
+				return m
+			}
+			m.g2048.State = st
+			m.g2048.Status = "playing"
+			return m
+		}},
+		{Text: "Start Over", Action: func(m model) model {
+			m.g2048.Options = g2048SizeOptions()
+			return m
+		}},
+	}}
+	return gm, nil
+}
+
+func g2048SizeOptions() Options {
+	var items []Option
+	for size := game2048.MinSize; size <= game2048.MaxSize; size++ {
+		items = append(items, Option{Text: fmt.Sprintf("%d×%d", size, size), Action: func(m model) model {
+			st, err := game2048.New(size, uint64(time.Now().UnixNano()))
+			if err != nil {
+				m.err = err
+				return m
+			}
+			gm := &m.g2048
+			gm.State, gm.History, gm.Pending, gm.Message = st, nil, "", ""
+			gm.Status = "playing"
+			m.err = save2048(gm.User, st)
+			return m
+		}})
+	}
+	return Options{Prompt: "New game of 2048. Pick a board size.", Items: items, Cursor: game2048.DefaultSize - game2048.MinSize}
+}
+
+// ----------------------------------------------------------------------------------
+// Session helpers
+// ----------------------------------------------------------------------------------
+func save2048(user string, st game2048.State) error {
+	cfg, err := sessionCfg(user, g2048Session)
+	if err != nil {
+		return err
+	}
+	cfg.Set("state", st)
+	if err := cfg.WriteConfig(); err != nil {
+		return fmt.Errorf("could not write 2048 session file: %w", err)
+	}
+	return nil
+}
+
+func load2048(user string) (game2048.State, error) {
+	var st game2048.State
+	cfg, err := sessionCfg(user, g2048Session)
+	if err != nil {
+		return st, err
+	}
+	if err := cfg.ReadInConfig(); err != nil {
+		return st, fmt.Errorf("could not read 2048 session file: %w", err)
+	}
+	if err := cfg.UnmarshalKey("state", &st); err != nil {
+		return st, fmt.Errorf("could not unmarshal 2048 state: %w", err)
+	}
+	if st.Size < game2048.MinSize || st.Size > game2048.MaxSize || len(st.Cells) != st.Size*st.Size {
+		return st, errors.New("2048 session file has no board to continue")
+	}
+	return st, nil
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) Game2048Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	gm := &m.g2048
+
+	switch msg := msg.(type) {
+	case g2048StartMsg:
+		m.g2048, m.err = NewGame2048Model(m.user())
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			if gm.Pending != "" {
+				gm.Pending = ""
+				return m, nil
+			}
+			m.currentUI = LIST_GAMES_UI
+			return m, nil
+		}
+
+		switch gm.Status {
+		case "start":
+			updateOptions(&gm.Options, key, &m)
+		case "playing":
+			m.err = gm.updatePlaying(key)
+		case "over":
+			switch key {
+			case "u":
+				m.err = gm.undo()
+			case "r", "n", "enter":
+				gm.Status = "start"
+				gm.Options = g2048SizeOptions()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (gm *Game2048Model) updatePlaying(key string) error {
+	dirs := map[string]string{
+		"up": game2048.Up, "k": game2048.Up, "w": game2048.Up,
+		"down": game2048.Down, "j": game2048.Down, "s": game2048.Down,
+		"left": game2048.Left, "h": game2048.Left, "a": game2048.Left,
+		"right": game2048.Right, "l": game2048.Right, "d": game2048.Right,
+	}
+	if dir, ok := dirs[key]; ok {
+		if gm.Preview && gm.Pending != dir {
+			gm.Pending = dir
+			return nil
+		}
+		return gm.move(dir)
+	}
+
+	switch key {
+	case "enter", " ":
+		if gm.Pending != "" {
+			return gm.move(gm.Pending)
+		}
+	case "u":
+		return gm.undo()
+	case "p":
+		gm.Preview = !gm.Preview
+		gm.Pending = ""
+	case "n":
+		gm.Status = "start"
+		gm.Options = g2048SizeOptions()
+	}
+	return nil
+}
+
+// move plays dir, saves the board and keeps the records.
+func (gm *Game2048Model) move(dir string) error {
+	gm.Pending = ""
+	next, slide := gm.State.Move(dir)
+	if !slide.Moved {
+		gm.Message = "Nothing moves that way."
+		return nil
+	}
+	gm.History = append(gm.History, gm.State)
+	if len(gm.History) > g2048UndoDepth {
+		gm.History = gm.History[1:]
+	}
+	gm.Message = ""
+	if gm.State.BestTile() < game2048.Goal && next.BestTile() >= game2048.Goal {
+		gm.Message = fmt.Sprintf("You made %d! Keep going for a bigger tile.", game2048.Goal)
+	}
+	gm.State = next
+	err := gm.keepRecords()
+
+	if !next.CanMove() {
+		gm.Status = "over"
+		return errors.Join(err, endSession(gm.User, g2048Session))
+	}
+	return errors.Join(err, save2048(gm.User, next))
+}
+
+func (gm *Game2048Model) undo() error {
+	if len(gm.History) == 0 {
+		gm.Message = "Nothing to undo."
+		return nil
+	}
+	gm.State = gm.History[len(gm.History)-1]
+	gm.History = gm.History[:len(gm.History)-1]
+	gm.Status = "playing"
+	gm.Pending, gm.Message = "", ""
+	return save2048(gm.User, gm.State)
+}
+
+// keepRecords writes the best tile and score to profile.yaml when the game
+// beats them.
+func (gm *Game2048Model) keepRecords() error {
+	tile, score := gm.State.BestTile(), gm.State.Score
+	if tile <= gm.Best && score <= gm.High {
+		return nil
+	}
+	gm.Best, gm.High = max(gm.Best, tile), max(gm.High, score)
+	return updateProfile(gm.User, func(p *viper.Viper) {
+		p.Set(g2048BestTile, gm.Best)
+		p.Set(g2048BestScore, gm.High)
+	})
+}
+
+func (m model) Game2048View() string {
+	gm := m.g2048
+	if gm.Status == "" {
+		return ""
+	}
+	if gm.Status == "start" {
+		return viewOptions(gm.Options, m.terminal)
+	}
+
+	st, merged := gm.State, []bool(nil)
+	var lines []string
+	switch {
+	case gm.Pending != "":
+		var slide game2048.Slide
+		st, slide = gm.State.Slide(gm.Pending)
+		merged = slide.Merged
+		line := fmt.Sprintf("Preview %s: ", g2048Arrows[gm.Pending])
+		if slide.Moved {
+			line += fmt.Sprintf("+%d. Press %s again or enter to play it, esc to cancel.", slide.Gained, g2048Arrows[gm.Pending])
+		} else {
+			line += "nothing moves that way."
+		}
+		lines = append(lines, turnStatusStyle.Render(line))
+	case gm.Status == "over":
+		lines = append(lines, turnErrorStyle.Bold(true).Render(fmt.Sprintf("No moves left! Final score %d, best tile %d.", st.Score, st.BestTile())))
+	case gm.Message != "":
+		lines = append(lines, turnStatusStyle.Render(gm.Message))
+	default:
+		lines = append(lines, "")
+	}
+
+	help := "arrows slide · u undo · p preview " + onOff(gm.Preview) + " · n new game · esc menu · q quit"
+	if gm.Status == "over" {
+		help = "u undo · n new game · esc menu · q quit"
+	}
+	lines = append(lines, turnMutedStyle.Render(help))
+
+	header := turnStatusStyle.Render(fmt.Sprintf("Score %d   Moves %d   Undo %d", st.Score, st.Moves, len(gm.History))) +
+		turnMutedStyle.Render(fmt.Sprintf("   Best tile %d · best score %d", gm.Best, gm.High))
+	board := draw2048(st, merged, g2048TileHeight(st.Size, m.terminal))
+	title := horizontalCenterBox(turnTitleStyle, "2048", m.terminal)
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", board, "", strings.Join(lines, "\n"))
+	margin := max((m.terminal.Width-lipgloss.Width(board))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// g2048TileHeight uses tall tiles when the whole board fits the terminal.
+func g2048TileHeight(size int, t Terminal) int {
+	if size*(g2048LargeTiles+1)+10 <= t.Height {
+		return g2048LargeTiles
+	}
+	return 1
+}
+
+// draw2048 draws the board, underlining the tiles in merged.
+func draw2048(st game2048.State, merged []bool, tileHeight int) string {
+	gap := g2048BoardStyle.Render(" ")
+	rows := make([]string, 0, 2*st.Size+1)
+	blank := g2048BoardStyle.Render(strings.Repeat(" ", st.Size*(g2048TileWidth+1)-1))
+	rows = append(rows, blank)
+	for y := 0; y < st.Size; y++ {
+		tiles := make([]string, 0, 2*st.Size)
+		for x := 0; x < st.Size; x++ {
+			if x > 0 {
+				tiles = append(tiles, strings.Repeat(gap+"\n", tileHeight-1)+gap)
+			}
+			i := y*st.Size + x
+			tiles = append(tiles, g2048Tile(st.Cells[i], merged != nil && merged[i], tileHeight))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles...), blank)
+	}
+	return g2048BoardStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+func g2048Tile(v int, merged bool, height int) string {
+	style := g2048EmptyStyle
+	label := ""
+	if v > 0 {
+		k := bits.Len(uint(v)) - 1 // v is 2^k
+		bg := "#3C3A32"
+		if k-1 < len(g2048Colors) {
+			bg = g2048Colors[k-1]
+		}
+		fg := "#F9F6F2"
+		if k <= 2 {
+			fg = "#776E65"
+		}
+		style = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(fg)).Background(lipgloss.Color(bg))
+		label = fmt.Sprint(v)
+	}
+	if merged {
+		style = style.Underline(true)
+	}
+	return style.Width(g2048TileWidth).Height(height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(label)
+}
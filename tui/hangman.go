@@ -0,0 +1,346 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/draw"
+	"gamics/games/hangman"
+	"math/rand/v2"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	hangmanTitle      = "Hangman"
+	hangmanWins       = "hangman-wins"
+	hangmanPlayed     = "hangman-played"
+	hangmanSecretMax  = 40
+	hangmanTwoPlayers = -1 // Pack of a round on a secret word typed in
+)
+
+var (
+	// hangmanDir is where players drop their own word packs.
+	hangmanDir = path.Join(gamicsRoot, "hangman")
+
+	hangmanWordStyle  = lipgloss.NewStyle().Bold(true)
+	hangmanFoundStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#5FAF00"))
+	hangmanMissStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F5F"))
+)
+
+func init() {
+	initTableGames[HANGMAN_UI] = func() tea.Cmd { return func() tea.Msg { return hangmanStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type hangmanStartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// HangmanModel deals words from the built-in packs and those dropped in
+// .gamics/hangman, or plays a word one player types for the other.
+type HangmanModel struct {
+	Packs   []hangman.Pack
+	Pack    int // pack being played, or hangmanTwoPlayers
+	Game    hangman.Game
+	Status  string // "packs", "secret", "playing", "over"
+	Menu    Options
+	Input   textinput.Model
+	Secret  string // word typed by the first player, while the category is asked
+	User    string
+	Message string
+	Warning string // packs that failed to load
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewHangmanModel loads the packs and lists them.
+func NewHangmanModel(user string) (HangmanModel, error) {
+	in := textinput.New()
+	in.CharLimit = hangmanSecretMax
+	in.Width = hangmanSecretMax
+	hm := HangmanModel{Status: "packs", User: user, Packs: hangman.Builtin(), Input: in}
+	own, err := hangman.LoadDir(hangmanDir)
+	if err != nil {
+		hm.Warning = err.Error()
+	}
+	hm.Packs = append(hm.Packs, own...)
+	hm.Menu, err = hm.packMenu()
+	return hm, err
+}
+
+// packMenu lists the packs with their language, difficulty and size, then
+// the two-player round.
+func (hm *HangmanModel) packMenu() (Options, error) {
+	var items []Option
+	for i, pack := range hm.Packs {
+		info := fmt.Sprintf("%s · %-6s · %d words", pack.Language, pack.Difficulty, len(pack.Words))
+		items = append(items, Option{Text: fmt.Sprintf("%-20s %s", pack.Name, turnMutedStyle.Render(info)), Action: func(m model) model {
+			m.hangman.Pack = i
+			m.hangman.deal()
+			return m
+		}})
+	}
+	items = append(items, Option{Text: "Two players: type a secret word", Action: func(m model) model {
+		m.hangman.askSecret()
+		return m
+	}})
+
+	p, err := loadProfile(hm.User)
+	if err != nil {
+		return Options{}, err
+	}
+	prompt := "Hangman. Pick a category.\n" + turnMutedStyle.Render("Drop word packs (.txt) into "+hangmanDir+" to play them here.")
+	if played := p.GetInt(hangmanPlayed); played > 0 {
+		prompt += "\n" + turnMutedStyle.Render(fmt.Sprintf("Won %d of %d words.", p.GetInt(hangmanWins), played))
+	}
+	if hm.Warning != "" {
+		prompt += "\n" + turnErrorStyle.Render(hm.Warning)
+	}
+	cursor := hm.Pack
+	if cursor == hangmanTwoPlayers {
+		cursor = len(items) - 1
+	}
+	return Options{Prompt: prompt, Items: items, Cursor: min(max(cursor, 0), len(items)-1)}, nil
+}
+
+// deal starts a round on a word of the current pack, another than the one
+// just played when there is a choice.
+func (hm *HangmanModel) deal() {
+	pack := hm.Packs[hm.Pack]
+	word := pack.Words[rand.IntN(len(pack.Words))]
+	for len(pack.Words) > 1 && word == hm.Game.Word {
+		word = pack.Words[rand.IntN(len(pack.Words))]
+	}
+	hm.play(hangman.New(word, pack.Name, pack.Difficulty.Lives()))
+}
+
+// askSecret hides the input for the first player to type the word.
+func (hm *HangmanModel) askSecret() {
+	hm.Pack = hangmanTwoPlayers
+	hm.Status, hm.Secret, hm.Message = "secret", "", ""
+	hm.Input.EchoMode = textinput.EchoPassword
+	hm.Input.Placeholder = "the secret word"
+	hm.Input.SetValue("")
+	hm.Input.Focus()
+}
+
+func (hm *HangmanModel) play(g hangman.Game) {
+	hm.Game = g
+	hm.Status, hm.Message = "playing", ""
+}
+
+// finish counts the round in the profile. Two-player rounds are not the
+// player's own, so they are left out.
+func (hm *HangmanModel) finish() error {
+	hm.Status = "over"
+	if hm.Pack == hangmanTwoPlayers {
+		return nil
+	}
+	return updateProfile(hm.User, func(p *viper.Viper) {
+		p.Set(hangmanPlayed, p.GetInt(hangmanPlayed)+1)
+		if hm.Game.Won() {
+			p.Set(hangmanWins, p.GetInt(hangmanWins)+1)
+		}
+	})
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) HangmanUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	hm := &m.hangman
+
+	switch msg := msg.(type) {
+	case hangmanStartMsg:
+		m.hangman, m.err = NewHangmanModel(m.user())
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		if hm.Status == "secret" {
+			var cmd tea.Cmd
+			cmd, m.err = hm.updateSecret(msg)
+			return m, cmd
+		}
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if hm.Status == "packs" {
+				m.currentUI = LIST_GAMES_UI
+				return m, nil
+			}
+			hm.Status = "packs"
+			hm.Menu, m.err = hm.packMenu()
+			return m, nil
+		}
+
+		switch hm.Status {
+		case "packs":
+			if key == "q" {
+				return m, tea.Quit
+			}
+			updateOptions(&hm.Menu, key, &m)
+			if m.hangman.Status == "secret" {
+				return m, textinput.Blink
+			}
+		case "playing":
+			m.err = hm.updatePlaying(msg)
+		case "over":
+			switch key {
+			case "q":
+				return m, tea.Quit
+			case "n", "enter":
+				if hm.Pack == hangmanTwoPlayers {
+					hm.askSecret()
+					return m, textinput.Blink
+				}
+				hm.deal()
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateSecret reads the first player's word, then an optional category
+// to give the guesser a clue.
+func (hm *HangmanModel) updateSecret(msg tea.KeyMsg) (tea.Cmd, error) {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit, nil
+	case "esc":
+		hm.Input.Blur()
+		hm.Status = "packs"
+		var err error
+		hm.Menu, err = hm.packMenu()
+		return nil, err
+	case "enter":
+		value := strings.Join(strings.Fields(hm.Input.Value()), " ")
+		if hm.Secret == "" {
+			if err := hangman.CheckWord(value); err != nil {
+				hm.Message = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "."
+				return nil, nil
+			}
+			hm.Secret, hm.Message = value, ""
+			hm.Input.EchoMode = textinput.EchoNormal
+			hm.Input.Placeholder = "a clue, such as Movies (optional)"
+			hm.Input.SetValue("")
+			return nil, nil
+		}
+		if value == "" {
+			value = "Secret word"
+		}
+		hm.Input.Blur()
+		hm.play(hangman.New(hm.Secret, value, hangman.Medium.Lives()))
+		hm.Secret = ""
+		return nil, nil
+	}
+	var cmd tea.Cmd
+	hm.Input, cmd = hm.Input.Update(msg)
+	return cmd, nil
+}
+
+// updatePlaying guesses the letter typed.
+func (hm *HangmanModel) updatePlaying(msg tea.KeyMsg) error {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || !unicode.IsLetter(msg.Runes[0]) {
+		return nil
+	}
+	r := msg.Runes[0]
+	next, err := hm.Game.Guess(r)
+	if err != nil {
+		hm.Message = turnErrorStyle.Render(err.Error() + ".")
+		return nil
+	}
+	hm.Game, hm.Message = next, ""
+	if next.Over() {
+		return hm.finish()
+	}
+	if next.Found(r) {
+		hm.Message = hangmanFoundStyle.Render(fmt.Sprintf("%c is in the word!", unicode.ToUpper(hangman.Fold(r))))
+	}
+	return nil
+}
+
+func (m model) HangmanView() string {
+	hm := m.hangman
+	switch hm.Status {
+	case "":
+		return ""
+	case "packs":
+		return viewOptions(hm.Menu, m.terminal)
+	case "secret":
+		return m.hangmanSecretView()
+	}
+
+	g := hm.Game
+	word := g.Masked()
+	if g.Lost() {
+		word = g.Word
+	}
+	word = hangmanWordStyle.Render(strings.Join(strings.Split(strings.ToUpper(word), ""), " "))
+
+	missed := make([]string, 0, g.Lives)
+	for _, r := range g.Missed() {
+		missed = append(missed, string(unicode.ToUpper(r)))
+	}
+	info := []string{
+		turnMutedStyle.Render(g.Category),
+		"",
+		word,
+		"",
+		fmt.Sprintf("Lives  %d/%d", g.Lives-g.Misses(), g.Lives),
+		"Missed " + hangmanMissStyle.Render(strings.Join(missed, " ")),
+	}
+
+	var status string
+	switch {
+	case g.Won():
+		status = turnStatusStyle.Bold(true).Render("You found the word!")
+	case g.Lost():
+		status = turnErrorStyle.Bold(true).Render("Hanged! The word was " + strings.ToUpper(g.Word) + ".")
+	default:
+		status = hm.Message
+	}
+	help := "type a letter to guess · esc categories · ctrl+c quit"
+	if g.Over() {
+		help = "n next word · esc categories · q quit"
+		if hm.Pack == hangmanTwoPlayers {
+			help = "n new secret word · esc categories · q quit"
+		}
+	}
+
+	stage := draw.HANGMAN[g.Misses()+hangman.MaxLives-g.Lives]
+	board := lipgloss.JoinHorizontal(lipgloss.Top, stage, "    ", lipgloss.JoinVertical(lipgloss.Left, info...))
+	content := lipgloss.JoinVertical(lipgloss.Left, board, "", status, "", turnMutedStyle.Render(help))
+	margin := max((m.terminal.Width-lipgloss.Width(board))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", horizontalCenterBox(turnTitleStyle, hangmanTitle, m.terminal),
+		lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+func (m model) hangmanSecretView() string {
+	hm := m.hangman
+	label := "Player 1, type the secret word while player 2 looks away:"
+	if hm.Secret != "" {
+		label = "Now a category for player 2 to go on:"
+	}
+	lines := []string{label, "", hm.Input.View(), ""}
+	if hm.Message != "" {
+		lines = append(lines, turnErrorStyle.Render(hm.Message))
+	}
+	lines = append(lines, turnMutedStyle.Render("enter confirm · esc back"))
+	return fullCenterBox(lipgloss.NewStyle(), strings.Join(lines, "\n"), m.terminal)
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ----------------------------------------------------------------------------------
+// Game loop
+// ----------------------------------------------------------------------------------
+
+// loopTimer names one periodic effect of a game session.
+type loopTimer string
+
+const (
+	timerMove  loopTimer = "move"  // movement step; hunger and food TTL ride on it
+	timerBlink loopTimer = "blink" // food blink animation
+	timerCPU   loopTimer = "cpu"   // a CPU player's move in a turn game
+	timerAnim  loopTimer = "anim"  // one frame of a turn game move landing
+	timerClock loopTimer = "clock" // redraw of a running game clock
+	timerGen   loopTimer = "gen"   // a board generated in the background
+)
+
+// loopMsg is one tick of a gameLoop timer.
+type loopMsg struct {
+	Loop  string
+	Gen   int
+	Timer loopTimer
+}
+
+// gameLoop owns every timer of a game session. Each timer is a chain of
+// one-shot ticks that the session re-arms when it handles them. Start and
+// Stop begin a new generation and Owns rejects ticks from older ones, so a
+// pause, restart or menu exit never leaves a second chain running.
+type gameLoop struct {
+	Name string
+	Gen  int
+}
+
+// Start begins a new generation. Chains of the previous one die out.
+func (l *gameLoop) Start() { l.Gen++ }
+
+// Stop drops every pending tick without starting new chains.
+func (l *gameLoop) Stop() { l.Gen++ }
+
+// After schedules the next tick of timer in the current generation.
+func (l gameLoop) After(timer loopTimer, d time.Duration) tea.Cmd {
+	name, gen := l.Name, l.Gen
+	return tea.Tick(d, func(time.Time) tea.Msg { return loopMsg{Loop: name, Gen: gen, Timer: timer} })
+}
+
+// Owns reports whether msg belongs to the current generation of this loop.
+func (l gameLoop) Owns(msg loopMsg) bool {
+	return msg.Loop == l.Name && msg.Gen == l.Gen
+}
@@ -0,0 +1,522 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/minesweeper"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	minesTitle     = "Minesweeper"
+	minesCellWidth = 2 // a glyph and a space; Click relies on it
+	minesNoGuess   = "minesweeper-no-guess"
+)
+
+var (
+	minesNumberColors = []lipgloss.AdaptiveColor{
+		{},
+		{Light: "#0000FF", Dark: "#5C9DFF"},
+		{Light: "#007B00", Dark: "#2FC67D"},
+		{Light: "#D00000", Dark: "#FF5C5C"},
+		{Light: "#00007B", Dark: "#A78BFA"},
+		{Light: "#7B0000", Dark: "#FF8A3D"},
+		{Light: "#007B7B", Dark: "#2DD4BF"},
+		{Light: "#000000", Dark: "#E6E6E6"},
+		{Light: "#7B7B7B", Dark: "#9CA3AF"},
+	}
+
+	minesBoardStyle = lipgloss.NewStyle().
+			Padding(0, 1).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.AdaptiveColor{Light: "#135334", Dark: "#2FC67D"})
+
+	minesClosedStyle   = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#888", Dark: "#666"})
+	minesFlagStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#D00000", Dark: "#FF5C5C"})
+	minesMineStyle     = lipgloss.NewStyle().Bold(true)
+	minesExplodedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFF")).Background(lipgloss.Color("#B00020"))
+)
+
+func init() {
+	initTableGames[MINESWEEPER_UI] = startMinesCmd
+	initTableGames[MINESWEEPER_TINY_UI] = startMinesCmd
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type minesStartMsg struct{}
+
+// minesBoardMsg carries a board whose mines were laid in the background for
+// the first dig at X, Y.
+type minesBoardMsg struct {
+	loopMsg
+	Board minesweeper.Board
+	X, Y  int
+}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// MinesModel is a game of Minesweeper. Both catalog entries play on it:
+// the full one offers every board size, Minesweeper Tiny goes straight to
+// the 5×5 board.
+type MinesModel struct {
+	Board   minesweeper.Board
+	Status  string // "setup", "custom", "generating", "playing", "over"
+	Setup   Options
+	NoGuess bool
+	Custom  minesweeper.Preset
+	Field   int // field of the custom board being edited
+	X, Y    int // cursor
+	Started time.Time
+	Elapsed time.Duration // time of the finished game
+	Best    bool          // the win beat the best time
+	Loop    gameLoop
+	User    string
+	Message string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewMinesModel opens the setup screen, or for Minesweeper Tiny the tiny
+// board.
+func NewMinesModel(id, user string) (MinesModel, error) {
+	mm := MinesModel{
+		Loop:   gameLoop{Name: id},
+		User:   user,
+		Custom: minesweeper.Preset{Name: "custom", Width: 20, Height: 12, Mines: 40},
+	}
+	profile, err := loadProfile(user)
+	if err != nil {
+		return mm, err
+	}
+	mm.NoGuess = profile.GetBool(minesNoGuess)
+	if id == MINESWEEPER_TINY_UI {
+		mm.newBoard(minesweeper.Tiny)
+		return mm, nil
+	}
+	mm.Status = "setup"
+	mm.Setup = minesSetupOptions(mm, profile)
+	return mm, nil
+}
+
+func minesSetupOptions(mm MinesModel, profile *viper.Viper) Options {
+	var items []Option
+	for _, p := range minesweeper.Presets() {
+		items = append(items, Option{
+			Text: fmt.Sprintf("%-13s %2d×%-2d %3d mines   %s", strings.ToUpper(p.Name[:1])+p.Name[1:], p.Width, p.Height, p.Mines, minesBestLine(profile, p)),
+			Action: func(m model) model {
+				m.mines.newBoard(p)
+				return m
+			},
+		})
+	}
+	items = append(items,
+		Option{Text: "Custom…", Action: func(m model) model {
+			m.mines.Status = "custom"
+			return m
+		}},
+		Option{Text: "No-guess boards: " + onOff(mm.NoGuess), Action: func(m model) model {
+			mm := &m.mines
+			mm.NoGuess = !mm.NoGuess
+			if m.err = updateProfile(mm.User, func(p *viper.Viper) { p.Set(minesNoGuess, mm.NoGuess) }); m.err != nil {
+				return m
+			}
+			profile, err := loadProfile(mm.User)
+			if err != nil {
+				m.err = err
+				return m
+			}
+			cursor := mm.Setup.Cursor
+			mm.Setup = minesSetupOptions(*mm, profile)
+			mm.Setup.Cursor = cursor
+			return m
+		}},
+	)
+	return Options{Prompt: "Minesweeper. Pick a board.", Items: items}
+}
+
+// newBoard deals a fresh board of p, mines unplaced until the first dig.
+func (mm *MinesModel) newBoard(p minesweeper.Preset) {
+	mm.Loop.Start()
+	mm.Board = minesweeper.New(p, mm.NoGuess, uint64(time.Now().UnixNano()))
+	mm.Status = "playing"
+	mm.X, mm.Y = p.Width/2, p.Height/2
+	mm.Started, mm.Elapsed, mm.Best = time.Time{}, 0, false
+	mm.Message = ""
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) MinesUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	mm := &m.mines
+
+	switch msg := msg.(type) {
+	case minesStartMsg:
+		m.mines, m.err = NewMinesModel(m.currentUI, m.user())
+		return m, nil
+
+	case minesBoardMsg:
+		if !mm.Loop.Owns(msg.loopMsg) || mm.Status != "generating" {
+			return m, nil
+		}
+		mm.Board = msg.Board
+		mm.Status = "playing"
+		mm.Board.Open(msg.X, msg.Y)
+		cmd := mm.afterMove()
+		return m, cmd
+
+	case loopMsg:
+		if !mm.Loop.Owns(msg) || msg.Timer != timerClock || mm.Status != "playing" {
+			return m, nil
+		}
+		return m, mm.Loop.After(timerClock, time.Second)
+
+	case tea.MouseMsg:
+		if mm.Status != "playing" || msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		ox, oy := minesBoardOrigin(m)
+		if msg.X < ox || msg.Y < oy {
+			return m, nil
+		}
+		x, y := (msg.X-ox)/minesCellWidth, msg.Y-oy
+		if x >= mm.Board.Width || y >= mm.Board.Height {
+			return m, nil
+		}
+		mm.X, mm.Y = x, y
+		var cmd tea.Cmd
+		switch msg.Button {
+		case tea.MouseButtonLeft:
+			cmd = mm.dig()
+		case tea.MouseButtonRight:
+			mm.Board.ToggleFlag(x, y)
+		case tea.MouseButtonMiddle:
+			mm.Board.Chord(x, y)
+			cmd = mm.afterMove()
+		}
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if mm.Status == "custom" {
+				mm.Status = "setup"
+				mm.Message = ""
+				return m, nil
+			}
+			return exitMinesGame(m)
+		}
+		var cmd tea.Cmd
+		switch mm.Status {
+		case "setup":
+			if msg.String() == "q" {
+				return m, tea.Quit
+			}
+			updateOptions(&mm.Setup, msg.String(), &m)
+		case "custom":
+			mm.updateCustom(msg.String())
+		case "playing":
+			cmd = mm.updatePlaying(msg.String())
+		case "over":
+			switch msg.String() {
+			case "q":
+				return m, tea.Quit
+			case "r", "enter", " ":
+				mm.newBoard(mm.Board.Preset)
+			}
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateCustom edits the width, height and mines of a custom board.
+func (mm *MinesModel) updateCustom(key string) {
+	fields := []*int{&mm.Custom.Width, &mm.Custom.Height, &mm.Custom.Mines}
+	switch key {
+	case "up", "k":
+		mm.Field = (mm.Field + len(fields) - 1) % len(fields)
+	case "down", "j", "tab":
+		mm.Field = (mm.Field + 1) % len(fields)
+	case "left", "h":
+		*fields[mm.Field]--
+	case "right", "l":
+		*fields[mm.Field]++
+	case "pgdown", "H":
+		*fields[mm.Field] -= 10
+	case "pgup", "L":
+		*fields[mm.Field] += 10
+	case "enter":
+		p, err := minesweeper.Custom(mm.Custom.Width, mm.Custom.Height, mm.Custom.Mines)
+		if err != nil {
+			mm.Message = err.Error()
+			return
+		}
+		mm.newBoard(p)
+		return
+	}
+	*fields[mm.Field] = max(*fields[mm.Field], 0)
+	mm.Message = ""
+}
+
+func (mm *MinesModel) updatePlaying(key string) tea.Cmd {
+	b := &mm.Board
+	switch key {
+	case "q":
+		return tea.Quit
+	case "up", "k":
+		mm.Y = (mm.Y + b.Height - 1) % b.Height
+	case "down", "j":
+		mm.Y = (mm.Y + 1) % b.Height
+	case "left", "h":
+		mm.X = (mm.X + b.Width - 1) % b.Width
+	case "right", "l":
+		mm.X = (mm.X + 1) % b.Width
+	case "enter", " ", "d":
+		return mm.dig()
+	case "f":
+		b.ToggleFlag(mm.X, mm.Y)
+	case "c":
+		b.Chord(mm.X, mm.Y)
+		return mm.afterMove()
+	case "r":
+		mm.newBoard(b.Preset)
+	}
+	return nil
+}
+
+// dig opens the cell under the cursor, or chords it if it is an open
+// number. The first dig of a no-guess board draws the board in the
+// background.
+func (mm *MinesModel) dig() tea.Cmd {
+	x, y := mm.X, mm.Y
+	if mm.Board.Cell(x, y).Open {
+		mm.Board.Chord(x, y)
+		return mm.afterMove()
+	}
+	if mm.Board.Started() || !mm.NoGuess || mm.Board.Cell(x, y).Flag {
+		mm.Board.Open(x, y)
+		return mm.afterMove()
+	}
+
+	mm.Status = "generating"
+	b, name, gen := mm.Board, mm.Loop.Name, mm.Loop.Gen
+	return func() tea.Msg {
+		return minesBoardMsg{loopMsg: loopMsg{Loop: name, Gen: gen, Timer: timerGen}, Board: b.Prepared(x, y), X: x, Y: y}
+	}
+}
+
+// afterMove starts the clock on the first dig and settles a finished game.
+func (mm *MinesModel) afterMove() tea.Cmd {
+	var cmd tea.Cmd
+	if mm.Started.IsZero() && mm.Board.Started() {
+		mm.Started = time.Now()
+		cmd = mm.Loop.After(timerClock, time.Second)
+	}
+	if mm.Board.State() == minesweeper.Playing {
+		return cmd
+	}
+	mm.Loop.Stop()
+	mm.Status = "over"
+	mm.Elapsed = time.Since(mm.Started)
+	return failCmd(mm.record())
+}
+
+// exitMinesGame stops the clock and goes back to the game list.
+func exitMinesGame(m model) (model, tea.Cmd) {
+	m.mines.Loop.Stop()
+	m.currentUI = LIST_GAMES_UI
+	return m, tea.DisableMouse
+}
+
+// ----------------------------------------------------------------------------------
+// Record
+// ----------------------------------------------------------------------------------
+
+// record counts the finished game in profile.yaml as
+// minesweeper-<board>-wins or -losses, and keeps the best time of each
+// preset board in minesweeper-<board>-best, in milliseconds.
+func (mm *MinesModel) record() error {
+	p := mm.Board.Preset
+	prefix := "minesweeper-" + p.Name + "-"
+	won := mm.Board.State() == minesweeper.Won
+	return updateProfile(mm.User, func(v *viper.Viper) {
+		if !won {
+			v.Set(prefix+"losses", v.GetInt(prefix+"losses")+1)
+			return
+		}
+		v.Set(prefix+"wins", v.GetInt(prefix+"wins")+1)
+		if p.Name == "custom" {
+			return
+		}
+		ms := mm.Elapsed.Milliseconds()
+		if best := v.GetInt64(prefix + "best"); best == 0 || ms < best {
+			v.Set(prefix+"best", ms)
+			mm.Best = true
+		}
+	})
+}
+
+func minesBestLine(profile *viper.Viper, p minesweeper.Preset) string {
+	best := profile.GetInt64("minesweeper-" + p.Name + "-best")
+	if best == 0 {
+		return ""
+	}
+	return "best " + minesClock(time.Duration(best)*time.Millisecond)
+}
+
+func minesClock(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// ----------------------------------------------------------------------------------
+// View
+// ----------------------------------------------------------------------------------
+func (m model) MinesView() string {
+	mm := m.mines
+	switch mm.Status {
+	case "":
+		return ""
+	case "setup":
+		return viewOptions(mm.Setup, m.terminal)
+	case "custom":
+		return viewMinesCustom(m)
+	}
+
+	title := horizontalCenterBox(turnTitleStyle, minesTitle, m.terminal)
+	content, margin := minesLayout(m)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// minesLayout stacks the counters, the board and the status lines, and
+// says how far right they are pushed.
+func minesLayout(m model) (content string, margin int) {
+	mm := m.mines
+	b := mm.Board
+
+	elapsed := mm.Elapsed
+	if mm.Status != "over" && !mm.Started.IsZero() {
+		elapsed = time.Since(mm.Started)
+	}
+	mode := b.Name
+	if b.NoGuess {
+		mode += " · no-guess"
+	}
+	header := turnStatusStyle.Render(fmt.Sprintf("Mines %d   Time %ds   ", b.MinesLeft(), int(elapsed.Seconds()))) + turnMutedStyle.Render(mode)
+	board := minesBoardStyle.Render(drawMinesBoard(mm))
+
+	var lines []string
+	switch mm.Status {
+	case "generating":
+		lines = append(lines, turnStatusStyle.Render("Drawing a board you can clear without guessing..."))
+	case "over":
+		if b.State() == minesweeper.Won {
+			line := "Cleared in " + minesClock(mm.Elapsed) + "!"
+			if mm.Best {
+				line += " New best time."
+			}
+			lines = append(lines, turnStatusStyle.Bold(true).Render(line))
+		} else {
+			lines = append(lines, turnErrorStyle.Bold(true).Render("Boom! You dug up a mine."))
+		}
+		lines = append(lines, turnMutedStyle.Render("r new board · esc menu · q quit"))
+	default:
+		lines = append(lines, turnMutedStyle.Render("arrows move · enter dig · f flag · c chord · mouse: left dig, right flag"),
+			turnMutedStyle.Render("r new board · esc menu · q quit"))
+	}
+
+	// Centered on the board alone, so it stays put as the lines below change.
+	content = lipgloss.JoinVertical(lipgloss.Left, header, board, "", strings.Join(lines, "\n"))
+	return content, max((m.terminal.Width-lipgloss.Width(board))/2, 0)
+}
+
+// minesBoardOrigin is where the first cell is drawn on screen: below the
+// title, a blank line and the counters, inside the board box.
+func minesBoardOrigin(m model) (x, y int) {
+	_, margin := minesLayout(m)
+	return margin + minesBoardStyle.GetBorderLeftSize() + minesBoardStyle.GetPaddingLeft(),
+		3 + minesBoardStyle.GetBorderTopSize() + minesBoardStyle.GetPaddingTop()
+}
+
+func drawMinesBoard(mm MinesModel) string {
+	b := mm.Board
+	over := mm.Status == "over"
+	ex, ey, exploded := b.Exploded()
+
+	var sb strings.Builder
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			c := b.Cell(x, y)
+			glyph, style := "■", minesClosedStyle
+			switch {
+			case exploded && x == ex && y == ey:
+				glyph, style = "✱", minesExplodedStyle
+			case over && c.Flag && !c.Mine:
+				glyph, style = "✗", minesFlagStyle
+			case c.Flag:
+				glyph, style = "⚑", minesFlagStyle
+			case over && c.Mine:
+				glyph, style = "✱", minesMineStyle
+			case c.Open && c.Adjacent == 0:
+				glyph, style = " ", lipgloss.NewStyle()
+			case c.Open:
+				glyph = fmt.Sprint(c.Adjacent)
+				style = lipgloss.NewStyle().Bold(true).Foreground(minesNumberColors[c.Adjacent])
+			}
+			if !over && x == mm.X && y == mm.Y {
+				style = style.Inherit(turnCursorStyle)
+			}
+			sb.WriteString(style.Render(glyph + " "))
+		}
+		if y < b.Height-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+func viewMinesCustom(m model) string {
+	mm := m.mines
+	fields := []struct {
+		name  string
+		value int
+	}{{"Width", mm.Custom.Width}, {"Height", mm.Custom.Height}, {"Mines", mm.Custom.Mines}}
+
+	var tw strings.Builder
+	for i, f := range fields {
+		txt := snakeBoxOption
+		if i == mm.Field {
+			txt = txt.Foreground(lipgloss.AdaptiveColor{Light: "#0F0", Dark: "#060"}).Bold(true)
+		}
+		tw.WriteString(txt.Render(fmt.Sprintf("%-7s ◀ %3d ▶", f.name, f.value)) + "\n")
+	}
+	message := fmt.Sprintf("Custom board.\n\n%s\n←/→ change · H/L by ten · enter play · esc back\n%s", tw.String(), mm.Message)
+	return fullCenterBox(snakeBoxWarn, strings.TrimRight(message, "\n"), m.terminal)
+}
+
+// startMinesCmd opens the game and turns the mouse on.
+func startMinesCmd() tea.Cmd {
+	return tea.Batch(func() tea.Msg { return minesStartMsg{} }, tea.EnableMouseCellMotion)
+}
@@ -0,0 +1,553 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"gamics/games/snake"
+	"gamics/games/turn"
+	"gamics/netplay"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	netPlayTitle    = "Gamics LAN"
+	netChatLines    = 6
+	netInputLimit   = 120
+	netLobbyDefault = "%s's room"
+)
+
+var (
+	netReadyStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#060", Dark: "#0B0"}).Bold(true)
+	netErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F33"))
+	netMutedStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#666", Dark: "#999"})
+)
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type netMsg netplay.Message
+type netClosedMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// NetPlayModel is a player connected to a gamics server: the lobby list,
+// the lobby they are in and, while a round runs, their copy of the game.
+type NetPlayModel struct {
+	Client  *netplay.Client
+	Screen  string // "lobbies", "games", "lobby", "playing", "closed"
+	Lobbies []netplay.LobbyInfo
+	Cursor  int
+	Pick    int             // cursor on the games a new lobby may play
+	NewGame string          // game of the lobby being created
+	Lobby   netplay.Message // last "lobby" update
+	Start   netplay.Message // "start" of the current or last round
+	Round   snake.State
+	Result  string
+	Chat    []string
+	Error   string
+	Typing  string // "", "chat" or "create"
+	Input   textinput.Model
+
+	// A turn game from the catalog; Game is nil during snake rounds.
+	Game       turnGame
+	Match      turn.Match
+	Seat       int // ours, in Match
+	TurnCursor turnCursor
+	Waiting    bool // our move is on its way to the server
+	Claimed    bool // we sent how the game ended
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewNetPlayModel opens the TUI on the lobby list of the server c is
+// connected to.
+func NewNetPlayModel(c *netplay.Client) model {
+	m := NewModel(NET_PLAY_UI)
+	in := textinput.New()
+	in.CharLimit = netInputLimit
+	m.netPlay = NetPlayModel{Client: c, Screen: "lobbies", Input: in}
+	return m
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) NetPlayUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	np := &m.netPlay
+	switch msg := msg.(type) {
+	case netClosedMsg:
+		np.Screen = "closed"
+		return m, nil
+
+	case netMsg:
+		np.apply(netplay.Message(msg))
+		return m, waitNetCmd(np.Client)
+
+	case tea.KeyMsg:
+		if np.Typing != "" {
+			return m, np.updateTyping(msg)
+		}
+		return m, np.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m model) NetPlayView() string {
+	np := m.netPlay
+	title := horizontalCenterBox(snakeAppTitleStyle, netPlayTitle+" — "+np.Client.Name, m.terminal)
+
+	var body string
+	switch np.Screen {
+	case "closed":
+		return fullCenterBox(snakeBoxWarn, "The server closed the connection.\n\nPress 'q' to quit.", m.terminal)
+	case "lobbies":
+		body = np.viewLobbies()
+	case "games":
+		body = np.viewGames()
+	case "lobby":
+		body = np.viewLobby(m.renderer)
+	case "playing":
+		if np.Game != nil {
+			body = np.viewTurnGame()
+		} else {
+			body = np.viewRound(m.renderer, m.terminal)
+		}
+	}
+
+	footer := np.viewChat()
+	if np.Error != "" {
+		footer = netErrorStyle.Render(np.Error) + "\n" + footer
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n%s", title, body, snakeAppStatsStyle.Render(footer))
+}
+
+// apply folds one server message into the model.
+func (np *NetPlayModel) apply(msg netplay.Message) {
+	switch msg.Type {
+	case netplay.TypeLobbies:
+		np.Lobbies = msg.Lobbies
+		np.Cursor = min(np.Cursor, max(len(np.Lobbies)-1, 0))
+		// The server only sends the list to players outside a lobby.
+		if np.Screen != "games" {
+			np.Screen = "lobbies"
+		}
+	case netplay.TypeLobby:
+		np.Lobby = msg
+		if np.Screen != "playing" {
+			np.Screen = "lobby"
+		}
+	case netplay.TypeStart:
+		np.Start = msg
+		np.Result = ""
+		np.Game = nil
+		if msg.Game == netplay.GameSnake {
+			np.Round = netplay.NewSnakeRound(msg)
+			np.Screen = "playing"
+		} else if err := np.startTurnGame(msg); err != nil {
+			np.send(netplay.Message{Type: netplay.TypeLeave})
+			np.Error = err.Error()
+			return
+		}
+	case netplay.TypeTick:
+		if np.Screen == "playing" {
+			np.Round, _ = snake.Step(np.Round, snake.Input{Turns: msg.Turns, Elapsed: time.Duration(msg.ElapsedMs) * time.Millisecond})
+		}
+	case netplay.TypeMove:
+		if np.Screen == "playing" && np.Game != nil {
+			np.applyMove(msg)
+		}
+	case netplay.TypeEnd:
+		np.Result = "Draw"
+		if msg.Winner != "" {
+			np.Result = msg.Winner + " wins"
+		}
+		if msg.Reason != "round" && msg.Reason != "" {
+			np.Result += " (" + msg.Reason + ")"
+		}
+		np.Result += "."
+		np.Screen = "lobby"
+	case netplay.TypeChat:
+		np.Chat = append(np.Chat, fmt.Sprintf("%s: %s", msg.From, msg.Text))
+		if len(np.Chat) > netChatLines {
+			np.Chat = np.Chat[len(np.Chat)-netChatLines:]
+		}
+	case netplay.TypeError:
+		np.Error = msg.Text
+		np.Waiting = false // a move the server refused
+		return
+	}
+	if msg.Type != netplay.TypeChat {
+		np.Error = ""
+	}
+}
+
+func (np *NetPlayModel) updateKeys(msg tea.KeyMsg) tea.Cmd {
+	k := msg.String()
+	if k == "ctrl+c" {
+		np.Client.Close()
+		return tea.Quit
+	}
+
+	switch np.Screen {
+	case "closed":
+		if k == "q" {
+			return tea.Quit
+		}
+
+	case "lobbies":
+		switch k {
+		case "q":
+			np.Client.Close()
+			return tea.Quit
+		case "up":
+			np.Cursor = max(np.Cursor-1, 0)
+		case "down":
+			np.Cursor = min(np.Cursor+1, max(len(np.Lobbies)-1, 0))
+		case "enter":
+			if np.Cursor < len(np.Lobbies) {
+				np.send(netplay.Message{Type: netplay.TypeJoin, Lobby: np.Lobbies[np.Cursor].Name})
+			}
+		case "c":
+			np.Screen, np.Pick = "games", 0
+		case "t":
+			np.startTyping("chat", "")
+			return textinput.Blink
+		case "r":
+			np.send(netplay.Message{Type: netplay.TypeList})
+		}
+
+	case "games":
+		games := netGames()
+		switch k {
+		case "up":
+			np.Pick = max(np.Pick-1, 0)
+		case "down":
+			np.Pick = min(np.Pick+1, len(games)-1)
+		case "enter":
+			np.NewGame, np.Screen = games[np.Pick], "lobbies"
+			np.startTyping("create", fmt.Sprintf(netLobbyDefault, np.Client.Name))
+			return textinput.Blink
+		case "esc":
+			np.Screen = "lobbies"
+		}
+
+	case "lobby":
+		switch k {
+		case "q":
+			np.Client.Close()
+			return tea.Quit
+		case "r":
+			np.send(netplay.Message{Type: netplay.TypeReady})
+		case "l", "esc":
+			np.send(netplay.Message{Type: netplay.TypeLeave})
+		case "t":
+			np.startTyping("chat", "")
+			return textinput.Blink
+		}
+
+	case "playing":
+		if k == "esc" {
+			np.send(netplay.Message{Type: netplay.TypeLeave})
+		} else if k == "t" {
+			np.startTyping("chat", "")
+			return textinput.Blink
+		} else if np.Game != nil {
+			np.playKey(k)
+		} else if dir, ok := arrowKeys[k]; ok {
+			np.send(netplay.Message{Type: netplay.TypeTurn, Direction: dir})
+		} else if dir, ok := wasdKeys[k]; ok {
+			np.send(netplay.Message{Type: netplay.TypeTurn, Direction: dir})
+		}
+	}
+	return nil
+}
+
+// startTurnGame seats the players of a turn game in the order of "start".
+func (np *NetPlayModel) startTurnGame(start netplay.Message) error {
+	g, ok := turnGames[start.Game]
+	if !ok {
+		return fmt.Errorf("this version of gamics can't play %s", start.Game)
+	}
+	players := make([]turn.Player, len(start.Players))
+	for i, name := range start.Players {
+		players[i] = turn.Player{Name: name}
+	}
+	match, err := turn.NewMatch(g.New(), players)
+	if err != nil {
+		return err
+	}
+	np.Game, np.Match, np.Seat = g, match, slices.Index(start.Players, np.Client.Name)
+	np.TurnCursor, np.Waiting, np.Claimed = turnCursor{}, false, false
+	np.Screen = "playing"
+	return nil
+}
+
+// myTurn reports whether we are to move in the turn game.
+func (np NetPlayModel) myTurn() bool {
+	return !np.Waiting && !np.Claimed && !np.Match.Outcome().Over && np.Match.Game().ToMove() == np.Seat
+}
+
+// playKey moves our cursor on the board and sends the move it completes.
+// The move is played once the server relays it back, so both players see
+// the moves in the same order.
+func (np *NetPlayModel) playKey(k string) {
+	if !np.myTurn() {
+		return
+	}
+	var mv turn.Move
+	np.TurnCursor, mv = np.Game.Key(np.Match.Game(), np.TurnCursor, k)
+	if mv == "" {
+		return
+	}
+	next, err := np.Match.Play(mv)
+	if err != nil {
+		np.Error = err.Error()
+		return
+	}
+	payload, _ := json.Marshal(mv)
+	np.Waiting = true
+	np.send(netplay.Message{Type: netplay.TypeMove, Move: payload, Turn: next.Game().ToMove()})
+}
+
+// applyMove plays a move relayed by the server and, when it ends the game,
+// tells the server the result. A move our copy of the game refuses, or one
+// after which the mover expects the wrong player to move, is called as a
+// loss for the mover; the server ends games the players disagree on
+// without a winner.
+func (np *NetPlayModel) applyMove(msg netplay.Message) {
+	np.Waiting = false
+	var mv turn.Move
+	err := json.Unmarshal(msg.Move, &mv)
+	next := np.Match
+	if err == nil {
+		next, err = np.Match.Play(mv)
+	}
+	if err == nil && !next.Outcome().Over && next.Game().ToMove() != msg.Turn {
+		err = turn.ErrIllegal
+	}
+	if err != nil {
+		winner := ""
+		for _, name := range np.Start.Players {
+			if name != msg.From {
+				winner = name
+			}
+		}
+		np.claim(winner, msg.From+" made an illegal move")
+		return
+	}
+
+	np.Match = next
+	np.TurnCursor.Picks = nil
+	if o := next.Outcome(); o.Over {
+		winner := ""
+		if o.Winner != turn.Draw {
+			winner = next.Players[o.Winner].Name
+		}
+		np.claim(winner, o.Reason)
+	}
+}
+
+// claim tells the server how the turn game ended, once.
+func (np *NetPlayModel) claim(winner, reason string) {
+	if np.Claimed {
+		return
+	}
+	np.Claimed = true
+	np.send(netplay.Message{Type: netplay.TypeEnd, Winner: winner, Reason: reason})
+}
+
+// netGames lists what a lobby can play: snake, then the turn games of the
+// catalog.
+func netGames() []string {
+	return append([]string{netplay.GameSnake}, slices.Sorted(maps.Keys(turnGames))...)
+}
+
+// netGameTitle names the game with ID id.
+func netGameTitle(id string) string {
+	if g, ok := turnGames[id]; ok {
+		return g.Title()
+	}
+	if id == netplay.GameSnake {
+		return "Snake"
+	}
+	return id
+}
+
+func (np *NetPlayModel) startTyping(what, value string) {
+	np.Typing = what
+	np.Input.SetValue(value)
+	np.Input.CursorEnd()
+	np.Input.Focus()
+}
+
+func (np *NetPlayModel) updateTyping(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		np.Typing = ""
+		np.Input.Blur()
+		return nil
+	case "enter":
+		text := strings.TrimSpace(np.Input.Value())
+		switch np.Typing {
+		case "chat":
+			np.send(netplay.Message{Type: netplay.TypeChat, Text: text})
+		case "create":
+			np.send(netplay.Message{Type: netplay.TypeCreate, Lobby: text, Game: np.NewGame})
+		}
+		np.Typing = ""
+		np.Input.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	np.Input, cmd = np.Input.Update(msg)
+	return cmd
+}
+
+func (np *NetPlayModel) send(msg netplay.Message) {
+	if err := np.Client.Send(msg); err != nil {
+		np.Error = err.Error()
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Rendering helpers
+// ----------------------------------------------------------------------------------
+func (np NetPlayModel) viewLobbies() string {
+	var sb strings.Builder
+	if len(np.Lobbies) == 0 {
+		sb.WriteString(netMutedStyle.Render("No lobbies yet. Press 'c' to create one.") + "\n")
+	}
+	for i, l := range np.Lobbies {
+		seats := netplay.MaxPlayers
+		if l.Game != netplay.GameSnake {
+			seats = netplay.TurnPlayers
+		}
+		line := fmt.Sprintf("%-24s %-22s %d/%d  %s", l.Name, netGameTitle(l.Game), len(l.Players), seats, strings.Join(l.Players, ", "))
+		if l.Running {
+			line += "  (playing)"
+		}
+		style := snakeBoxOption
+		if i == np.Cursor {
+			style = style.Foreground(lipgloss.AdaptiveColor{Light: "#0F0", Dark: "#060"}).Bold(true)
+		}
+		sb.WriteString(style.Render(line) + "\n")
+	}
+	sb.WriteString("\n" + netMutedStyle.Render("enter join · c create · r refresh · t chat · q quit"))
+	return sb.String()
+}
+
+func (np NetPlayModel) viewGames() string {
+	var sb strings.Builder
+	sb.WriteString("What will the new lobby play?\n\n")
+	for i, id := range netGames() {
+		style := snakeBoxOption
+		if i == np.Pick {
+			style = style.Foreground(lipgloss.AdaptiveColor{Light: "#0F0", Dark: "#060"}).Bold(true)
+		}
+		sb.WriteString(style.Render(netGameTitle(id)) + "\n")
+	}
+	sb.WriteString("\n" + netMutedStyle.Render("enter pick · esc back"))
+	return sb.String()
+}
+
+func (np NetPlayModel) viewLobby(r *lipgloss.Renderer) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Lobby %q — %s\n\n", np.Lobby.Lobby, netGameTitle(np.Lobby.Game))
+	for i, name := range np.Lobby.Players {
+		ready := netMutedStyle.Render("waiting")
+		if i < len(np.Lobby.Ready) && np.Lobby.Ready[i] {
+			ready = netReadyStyle.Render("ready")
+		}
+		wins := 0
+		if i < len(np.Lobby.Wins) {
+			wins = np.Lobby.Wins[i]
+		}
+		name = r.NewStyle().Bold(true).Foreground(lipgloss.Color(versusPalettes[i%len(versusPalettes)].head(r))).Render(name)
+		fmt.Fprintf(&sb, "  %s  %s  wins: %d\n", name, ready, wins)
+	}
+	if np.Result != "" {
+		sb.WriteString("\n" + np.Result + "\n")
+	}
+	sb.WriteString("\n" + netMutedStyle.Render("r ready · l leave · t chat · q quit — the round starts when everybody is ready"))
+	return sb.String()
+}
+
+func (np NetPlayModel) viewRound(r *lipgloss.Renderer, t Terminal) string {
+	if w, h := fieldSize(t); w < np.Round.Width || h < np.Round.Height {
+		return fmt.Sprintf("The field is %dx%d; enlarge the terminal to at least %dx%d.",
+			np.Round.Width, np.Round.Height, np.Round.Width+2, np.Round.Height+10)
+	}
+
+	field := snakeAppStyle.Width(np.Round.Width).Height(np.Round.Height).
+		Render(drawField(r, np.Round.Food, true, np.Round.Snakes, versusPalettes, np.Round.Width, np.Round.Height))
+
+	cols := make([]string, 0, len(np.Start.Players))
+	for i, name := range np.Start.Players {
+		sn := np.Round.Snakes[i]
+		if !sn.Alive() {
+			name += " ✗"
+		}
+		style := r.NewStyle().Bold(true).Foreground(lipgloss.Color(versusPalettes[i%len(versusPalettes)].head(r)))
+		cols = append(cols, fmt.Sprintf("%s  %d  %s", style.Render(name), sn.Score, strings.Repeat("♥", snake.Seconds(sn.Hunger))))
+	}
+	return field + "\n" + strings.Join(cols, "    ") + "\n" + netMutedStyle.Render("arrows or WASD turn · t chat · esc leave")
+}
+
+func (np NetPlayModel) viewTurnGame() string {
+	var cursor *turnCursor
+	if np.myTurn() {
+		cursor = &np.TurnCursor
+	}
+	board := turnBoardStyle.Render(np.Game.Board(np.Match.Game(), cursor))
+
+	seats := TurnModel{Game: np.Game, Match: np.Match}
+	status := fmt.Sprintf("%s to move", seats.seatName(np.Match.Game().ToMove()))
+	switch {
+	case np.Match.Outcome().Over:
+		status = "Game over, waiting for the result..."
+	case np.myTurn():
+		status = "Your move, " + seats.seatName(np.Seat)
+	}
+	return board + "\n" + turnStatusStyle.Render(status) + "\n" + netMutedStyle.Render(np.Game.Help()+" · t chat · esc leave")
+}
+
+func (np NetPlayModel) viewChat() string {
+	lines := append([]string(nil), np.Chat...)
+	if np.Typing != "" {
+		label := "say: "
+		if np.Typing == "create" {
+			label = "lobby name: "
+		}
+		lines = append(lines, label+np.Input.View())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ----------------------------------------------------------------------------------
+// Networking
+// ----------------------------------------------------------------------------------
+
+// waitNetCmd delivers the next server message; it is re-armed after each.
+func waitNetCmd(c *netplay.Client) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-c.Messages()
+		if !ok {
+			return netClosedMsg{}
+		}
+		return netMsg(msg)
+	}
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/nim"
+	"gamics/games/turn"
+	"strings"
+)
+
+// nimGame draws Nim as rows of matches. The cursor picks a heap with up and
+// down and how many matches to take with left and right; the matches about
+// to be taken are highlighted.
+type nimGame struct{}
+
+func (nimGame) Title() string { return "Nim" }
+func (nimGame) Help() string {
+	return "↑/↓ heap · ←/→ how many · enter take"
+}
+func (nimGame) New() turn.Game                          { return nim.New(nim.DefaultHeaps) }
+func (nimGame) NewAI(level string, seed uint64) turn.AI { return nim.NewAI(level, seed) }
+func (nimGame) Seat(int) string                         { return "" }
+
+func (nimGame) Board(tg turn.Game, cursor *turnCursor) string {
+	g := tg.(nim.Game)
+	lines := make([]string, 0, len(g.Heaps)+2)
+	for h, n := range g.Heaps {
+		take := 0
+		if cursor != nil && cursor.Row == h {
+			take = min(max(cursor.Col, 1), n)
+		}
+		row := strings.Repeat("| ", n-take) + turnCursorStyle.Render(strings.TrimRight(strings.Repeat("| ", take), " "))
+		mark := "  "
+		if cursor != nil && cursor.Row == h {
+			mark = "> "
+		}
+		lines = append(lines, fmt.Sprintf("%sHeap %d (%2d)  %s", mark, h+1, n, row))
+	}
+	lines = append(lines, "", fmt.Sprintf("%d matches left. Whoever takes the last one wins.", g.Matches()))
+	return strings.Join(lines, "\n")
+}
+
+func (nimGame) Key(tg turn.Game, c turnCursor, key string) (turnCursor, turn.Move) {
+	g := tg.(nim.Game)
+	switch key {
+	case "up", "k":
+		c.Row = (c.Row + len(g.Heaps) - 1) % len(g.Heaps)
+	case "down", "j":
+		c.Row = (c.Row + 1) % len(g.Heaps)
+	case "left", "h":
+		c.Col = max(c.Col-1, 1)
+	case "right", "l":
+		c.Col = c.Col + 1
+	case "enter", " ":
+		n := g.Heaps[c.Row]
+		if n == 0 {
+			return c, nim.MoveFor(c.Row, 1) // refused with a reason by the engine
+		}
+		return c, nim.MoveFor(c.Row, min(max(c.Col, 1), n))
+	}
+	if n := g.Heaps[c.Row]; c.Col > n {
+		c.Col = max(n, 1)
+	}
+	return c, ""
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/reversi"
+	"gamics/games/turn"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ReversiThinkTime is how long the Reversi CPU searches a move. It is set
+// from "reversi-think-ms" in .gamics/config.yaml.
+var ReversiThinkTime = reversi.DefaultThinkTime
+
+// The board is drawn with squares rvCellWidth wide, a row label of
+// rvLabelWidth on the left and a header line on top; Click relies on the
+// same numbers.
+const (
+	rvLabelWidth = 2
+	rvCellWidth  = 3
+)
+
+var (
+	rvDiscStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#000000")),
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")),
+	}
+	rvSeatNames = []string{"Black", "White"}
+	rvSquareBgs = []lipgloss.Color{"#1E6B3A", "#237A43"} // checkered felt
+	rvLastBg    = lipgloss.Color("#3C8C5A")
+
+	// rvFlipFrames is how a flipped disc turns over, from the first frame
+	// to the last, before it shows its new colour.
+	rvFlipFrames = []struct {
+		glyph string
+		old   bool // drawn in the colour of the seat it was taken from
+	}{{"●", true}, {"◑", true}, {"│", false}, {"◐", false}}
+)
+
+// rvGame plays Reversi on the turn screens, by keyboard or mouse. The legal
+// squares of the human to move are dotted and flipped discs turn over.
+type rvGame struct{}
+
+func (rvGame) Title() string  { return "Reversi" }
+func (rvGame) Help() string   { return "arrows move · enter or click to place · dots are legal" }
+func (rvGame) New() turn.Game { return reversi.New() }
+func (rvGame) NewAI(level string, seed uint64) turn.AI {
+	return reversi.NewAI(level, ReversiThinkTime, seed)
+}
+func (rvGame) Seat(seat int) string {
+	return strings.ToLower(rvSeatNames[seat])
+}
+
+func (rvGame) Board(tg turn.Game, cursor *turnCursor) string {
+	return drawReversi(tg.(reversi.Game), cursor, 0)
+}
+
+func (rvGame) AnimFrames(tg turn.Game, mv turn.Move) int {
+	if tg.(reversi.Game).Flips == 0 {
+		return 0
+	}
+	return len(rvFlipFrames)
+}
+
+func (rvGame) BoardFrame(tg turn.Game, mv turn.Move, frame int) string {
+	return drawReversi(tg.(reversi.Game), nil, frame)
+}
+
+// drawReversi draws the board, with the legal squares and the cursor of a
+// human to move, and the last move's flips frame frames away from done.
+func drawReversi(g reversi.Game, cursor *turnCursor, frame int) string {
+	legal := map[reversi.Point]bool{}
+	if cursor != nil {
+		for _, mv := range g.Legal() {
+			p, _ := reversi.ParseMove(mv)
+			legal[p] = true
+		}
+	}
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", rvLabelWidth))
+	for c := 0; c < reversi.Size; c++ {
+		fmt.Fprintf(&b, " %c ", 'a'+c)
+	}
+	for r := 0; r < reversi.Size; r++ {
+		fmt.Fprintf(&b, "\n%-*d", rvLabelWidth, r+1)
+		for c := 0; c < reversi.Size; c++ {
+			sq := r*reversi.Size + c
+			style := lipgloss.NewStyle().Background(rvSquareBgs[(r+c)%2])
+			if sq == g.Last {
+				style = style.Background(rvLastBg)
+			}
+			glyph := " "
+			switch v := g.Cell(r, c); {
+			case v != 0 && frame > 0 && g.Flips&(1<<sq) != 0:
+				f := rvFlipFrames[len(rvFlipFrames)-frame]
+				seat := v - 1
+				if f.old {
+					seat = 1 - seat
+				}
+				glyph, style = f.glyph, style.Inherit(rvDiscStyles[seat])
+			case v != 0:
+				glyph, style = "●", style.Inherit(rvDiscStyles[v-1])
+			case legal[reversi.Point{Row: r, Col: c}]:
+				glyph, style = "·", style.Inherit(rvDiscStyles[g.Turn])
+			}
+			if cursor != nil && cursor.Row == r && cursor.Col == c {
+				style = style.Inherit(turnCursorStyle)
+			}
+			b.WriteString(style.Render(" " + glyph + " "))
+		}
+	}
+
+	score := fmt.Sprintf("\n\n%s black %-2d  %s white %d",
+		rvDiscStyles[0].Background(rvSquareBgs[0]).Render(" ● "), g.Count(0),
+		rvDiscStyles[1].Background(rvSquareBgs[0]).Render(" ● "), g.Count(1))
+	b.WriteString(score)
+	if g.Passed >= 0 && frame == 0 {
+		b.WriteString("\n" + turnStatusStyle.Render(fmt.Sprintf("%s has no move and passes.", rvSeatNames[g.Passed])))
+	}
+	return b.String()
+}
+
+func (rvGame) Key(tg turn.Game, c turnCursor, key string) (turnCursor, turn.Move) {
+	switch key {
+	case "up", "k":
+		c.Row = (c.Row + reversi.Size - 1) % reversi.Size
+	case "down", "j":
+		c.Row = (c.Row + 1) % reversi.Size
+	case "left", "h":
+		c.Col = (c.Col + reversi.Size - 1) % reversi.Size
+	case "right", "l":
+		c.Col = (c.Col + 1) % reversi.Size
+	case "enter", " ":
+		return c, reversi.SquareMove(c.Row, c.Col)
+	}
+	return c, ""
+}
+
+func (rvGame) Click(tg turn.Game, c turnCursor, x, y int) (turnCursor, turn.Move) {
+	x -= rvLabelWidth
+	y -= 1 // header
+	if x < 0 || y < 0 {
+		return c, ""
+	}
+	row, col := y, x/rvCellWidth
+	if row >= reversi.Size || col >= reversi.Size {
+		return c, ""
+	}
+	c.Row, c.Col = row, col
+	return c, reversi.SquareMove(row, col)
+}
@@ -46,41 +46,42 @@ type GameMeta struct {
 func catalog() []GameMeta {
 	return []GameMeta{
 		{Title: "Snake", Description: "Guide the snake, eat food, grow and survive.", ID: SNAKE_GAME_UI},
-		{Title: "Tic‑Tac‑Toe", Description: "3×3 noughts and crosses.", ID: ""},
-		{Title: "Connect Four", Description: "Drop discs and make a line of four.", ID: ""},
-		{Title: "Hangman", Description: "Guess the word, one letter at a time.", ID: ""},
-		{Title: "2048", Description: "Slide tiles to reach 2048.", ID: ""},
-		{Title: "Minesweeper", Description: "Uncover cells without hitting mines.", ID: ""},
+		{Title: "Snake Versus", Description: "Two snakes, one keyboard: arrows vs WASD.", ID: SNAKE_VS_UI},
+		{Title: "Tic‑Tac‑Toe", Description: "3×3 noughts and crosses.", ID: TIC_TAC_TOE_UI},
+		{Title: "Connect Four", Description: "Drop discs and make a line of four.", ID: CONNECT_FOUR_UI},
+		{Title: "Hangman", Description: "Guess the word, one letter at a time.", ID: HANGMAN_UI},
+		{Title: "2048", Description: "Slide tiles to reach 2048.", ID: GAME_2048_UI},
+		{Title: "Minesweeper", Description: "Uncover cells without hitting mines.", ID: MINESWEEPER_UI},
 		{Title: "Lights Out", Description: "Toggle lights to turn all off.", ID: ""},
 		{Title: "15‑Puzzle", Description: "Slide tiles into order.", ID: ""},
 		{Title: "8‑Puzzle", Description: "Smaller sliding puzzle variant.", ID: ""},
-		{Title: "Sokoban", Description: "Push crates onto goals.", ID: ""},
+		{Title: "Sokoban", Description: "Push crates onto goals.", ID: SOKOBAN_UI},
 		{Title: "Dots and Boxes", Description: "Draw lines, complete boxes.", ID: ""},
-		{Title: "Nim", Description: "Take turns removing matches.", ID: ""},
+		{Title: "Nim", Description: "Take turns removing matches.", ID: NIM_UI},
 		{Title: "21 Sticks", Description: "Variant of Nim to avoid the last stick.", ID: ""},
 		{Title: "Rock‑Paper‑Scissors", Description: "Best of luck vs CPU.", ID: ""},
 		{Title: "Higher or Lower", Description: "Guess if next number is higher.", ID: ""},
 		{Title: "Guess the Number", Description: "Binary search your way to victory.", ID: ""},
 		{Title: "Mastermind", Description: "Crack the color/code pattern.", ID: ""},
 		{Title: "Bulls and Cows", Description: "Number‑guessing with feedback.", ID: ""},
-		{Title: "Reversi (Othello)", Description: "Flip discs to dominate the board.", ID: ""},
-		{Title: "Checkers", Description: "Draughts on an 8×8 board.", ID: ""},
+		{Title: "Reversi (Othello)", Description: "Flip discs to dominate the board.", ID: REVERSI_UI},
+		{Title: "Checkers", Description: "Draughts on an 8×8 board.", ID: CHECKERS_UI},
 		{Title: "Gomoku", Description: "Five‑in‑a‑row on a grid.", ID: ""},
 		{Title: "Hex", Description: "Connect opposite sides.", ID: ""},
-		{Title: "Battleship", Description: "Sink the enemy fleet.", ID: ""},
+		{Title: "Battleship", Description: "Sink the enemy fleet.", ID: BATTLESHIP_UI},
 		{Title: "Peg Solitaire", Description: "Jump pegs to leave one.", ID: ""},
 		{Title: "Memory (Concentration)", Description: "Match pairs from hidden cards.", ID: ""},
 		{Title: "Simon", Description: "Repeat the sequence.", ID: ""},
 		{Title: "Tower of Hanoi", Description: "Move disks with rules.", ID: ""},
 		{Title: "Hitori", Description: "Logic puzzle on a grid.", ID: ""},
 		{Title: "Nonogram (Picross)", Description: "Fill cells by clues to draw.", ID: ""},
-		{Title: "Sudoku", Description: "Place digits 1‑9 without repeats.", ID: ""},
-		{Title: "Wordle", Description: "Guess a 5‑letter word in 6 tries.", ID: ""},
+		{Title: "Sudoku", Description: "Place digits 1‑9 without repeats.", ID: SUDOKU_UI},
+		{Title: "Wordle", Description: "Guess a 5‑letter word in 6 tries.", ID: WORDLE_UI},
 		{Title: "Boggle", Description: "Find words in letter dice.", ID: ""},
 		{Title: "Word Search", Description: "Locate hidden words in a grid.", ID: ""},
 		{Title: "Anagrams", Description: "Rearrange letters to form words.", ID: ""},
 		{Title: "Kakuro", Description: "Crossword‑like number sums.", ID: ""},
-		{Title: "Minesweeper Tiny", Description: "5×5 quick variant.", ID: ""},
+		{Title: "Minesweeper Tiny", Description: "5×5 quick variant.", ID: MINESWEEPER_TINY_UI},
 		{Title: "Treasure Hunt", Description: "Hot/Cold grid‑based search.", ID: ""},
 		{Title: "Chomp", Description: "Take bites from a chocolate grid.", ID: ""},
 		{Title: "Fox and Geese", Description: "Classic asymmetrical chase.", ID: ""},
@@ -95,7 +96,7 @@ func catalog() []GameMeta {
 		{Title: "Flappy Bird (ASCII)", Description: "One‑key obstacle dodging.", ID: ""},
 		{Title: "Pong", Description: "Two paddles, one ball.", ID: ""},
 		{Title: "Breakout", Description: "Break bricks with a paddle.", ID: ""},
-		{Title: "Tetris", Description: "Fit falling tetrominoes.", ID: ""},
+		{Title: "Tetris", Description: "Fit falling tetrominoes.", ID: TETRIS_UI},
 	}
 }
 
@@ -1,12 +1,11 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"gamics/draw"
+	"gamics/games/snake"
 	"gamics/internal"
-	"log"
-	"math"
-	"math/rand"
 	"os"
 	"path"
 	"sort"
@@ -25,15 +24,25 @@ const (
 	SNAKE_GAME_LIGHT_BG = "#BCF0D7"
 	SNAKE_GAME_DARK_BG  = "#04110A"
 
-	gameTitle          = "Snake Game"
-	foodTTL            = 10 * time.Second
-	hungerResetSeconds = 30
-	runTickEvery       = 100 * time.Millisecond
+	gameTitle         = "Snake Game"
+	snakeSession      = "snake" // saved in snake.yaml
+	rivalRespawnTicks = 25
 )
 
 var (
-	snakeCfg = viper.New()
-	renderer = lipgloss.NewRenderer(os.Stdout)
+	// InputBufferSize is how many turns a snake player can type ahead of
+	// the snake. It is set from "snake-input-buffer" in .gamics/config.yaml.
+	InputBufferSize = snake.DefaultQueueSize
+
+	// foodColors maps a food kind to its light and dark terminal colors.
+	foodColors = map[string][2]string{
+		snake.FoodNormal: {"#2FC67D", "#F0D700"},
+		snake.FoodGolden: {"#B8860B", "#FFB300"},
+		snake.FoodShrink: {"#1E6FD9", "#4FA3FF"},
+		snake.FoodSlow:   {"#7B3FBF", "#B98CFF"},
+		snake.FoodGhost:  {"#8A8A8A", "#E6E6E6"},
+		snake.FoodPoison: {"#B00020", "#FF3B3B"},
+	}
 
 	snakeBoxWarn = lipgloss.
 			NewStyle().
@@ -81,47 +90,16 @@ var (
 		Background(lipgloss.AdaptiveColor{Light: SNAKE_GAME_LIGHT_BG, Dark: SNAKE_GAME_DARK_BG})
 )
 
-// ----------------------------------------------------------------------------------
-// Messages (Bubble Tea)
-// ----------------------------------------------------------------------------------
-type tickRunSnakeGameMsg struct{}
-type tickMsg struct{ gen int }
-type tickBlinkFoodMsg struct{}
-type tickHungerFoodMsg struct{ dieIn int }
-
 // ----------------------------------------------------------------------------------
 // Data types
 // ----------------------------------------------------------------------------------
-type Coordinates struct {
-	X int `yaml:"x" mapstructure:"x"`
-	Y int `yaml:"y" mapstructure:"y"`
-}
-
-type SnakePos struct {
-	Position Coordinates `yaml:"position" mapstructure:"position"`
-	Order    int         `yaml:"order"    mapstructure:"order"`
-}
-
-type Snake struct {
-	Position          []SnakePos `yaml:"position"          mapstructure:"position"`
-	Direction         string     `yaml:"direction"         mapstructure:"direction"`
-	Speed             float64    `yaml:"speed"             mapstructure:"speed"`
-	RenderedDirection string     `yaml:"renderedDirection" mapstructure:"renderedDirection"`
-	DieByHungerIn     int        `yaml:"dieByHungerIn"     mapstructure:"dieByHungerIn"`
-}
-
-type Food struct {
-	Position Coordinates `yaml:"position" mapstructure:"position"`
-	Color    bool        `yaml:"color"    mapstructure:"color"`
-	Expire   time.Time   `yaml:"expire"   mapstructure:"expire"`
-}
-
 type Option struct {
 	Text   string              `yaml:"text"  mapstructure:"text"`
 	Action func(m model) model `yaml:"-"` // função não serializa
 }
 
 type Options struct {
+	Prompt string   `yaml:"prompt" mapstructure:"prompt"`
 	Items  []Option `yaml:"items"  mapstructure:"items"`
 	Cursor int      `yaml:"cursor" mapstructure:"cursor"`
 }
@@ -132,163 +110,263 @@ type Game struct {
 	Options Options `yaml:"options" mapstructure:"options"`
 }
 
+// SnakeModel wraps the engine state of a solo game. The player is
+// State.Snakes[0]; any further snakes are CPU rivals.
 type SnakeModel struct {
-	Snake   Snake `yaml:"snake"    mapstructure:"snake"`
-	Food    Food  `yaml:"food"     mapstructure:"food"`
-	Game    Game  `yaml:"game"     mapstructure:"game"`
-	TickGen int   `yaml:"tickGen"  mapstructure:"tickGen"`
+	State snake.State     `yaml:"state"    mapstructure:"state"`
+	Turns snake.TurnQueue `yaml:"-"`                             // turns typed since the last tick
+	Blink bool            `yaml:"blink"    mapstructure:"blink"` // food currently lit
+	Game  Game            `yaml:"game"     mapstructure:"game"`
+	Loop  gameLoop        `yaml:"-"`
+
+	Daily       string       `yaml:"-"` // challenge day, empty outside the daily challenge
+	DailyResult *DailyResult `yaml:"-"`
+}
+
+func (sm SnakeModel) player() snake.Snake { return sm.State.Snakes[0] }
+
+// rivalSetup is one opponent choice offered when a new solo game starts.
+type rivalSetup struct {
+	Text   string
+	Levels []string
+}
+
+func rivalSetups() []rivalSetup {
+	return []rivalSetup{
+		{Text: "Play alone", Levels: nil},
+		{Text: "Versus 1 CPU (easy)", Levels: []string{snake.AIEasy}},
+		{Text: "Versus 1 CPU (medium)", Levels: []string{snake.AIMedium}},
+		{Text: "Versus 1 CPU (hard)", Levels: []string{snake.AIHard}},
+		{Text: "Versus 3 CPUs (mixed)", Levels: []string{snake.AIEasy, snake.AIMedium, snake.AIHard}},
+	}
 }
 
 // ----------------------------------------------------------------------------------
 // Constructors
 // ----------------------------------------------------------------------------------
 func InitNewSnakeModel() SnakeModel {
-	return SnakeModel{Game: Game{Status: "start"}}
+	return SnakeModel{Game: Game{Status: "start"}, Loop: gameLoop{Name: SNAKE_GAME_UI}}
 }
 
-func NewSnakeModel() SnakeModel {
-	snakeBody := []SnakePos{
-		{Position: Coordinates{X: 5, Y: 5}, Order: 0},
-		{Position: Coordinates{X: 4, Y: 5}, Order: 1},
-		{Position: Coordinates{X: 3, Y: 5}, Order: 2},
-	}
-	return SnakeModel{
-		Game:  Game{Status: "running"},
-		Food:  Food{Color: true, Position: Coordinates{X: -1, Y: -1}, Expire: time.Now().Add(foodTTL)},
-		Snake: Snake{Position: snakeBody, Direction: "right", RenderedDirection: "right", Speed: 1, DieByHungerIn: hungerResetSeconds},
+// NewSnakeModel starts a solo game sized to the terminal, with one CPU rival
+// per entry in levels.
+func NewSnakeModel(t Terminal, levels []string) SnakeModel {
+	w, h := fieldSize(t)
+	snakes := []snake.SnakeConfig{{
+		Body:    []snake.Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}},
+		Heading: snake.Right,
+	}}
+	for _, l := range levels {
+		snakes = append(snakes, snake.SnakeConfig{AI: l, Respawn: rivalRespawnTicks})
 	}
+
+	st := snake.New(snake.Config{Width: w, Height: h, Seed: uint64(time.Now().UnixNano()), Snakes: snakes})
+	return SnakeModel{State: st, Turns: snake.NewTurnQueue(InputBufferSize), Blink: true, Game: Game{Status: "running"}}
 }
 
 func RestartSnakeModel(m model) SnakeModel {
-	snakeBody := []SnakePos{
-		{Position: Coordinates{X: 5, Y: 5}, Order: 0},
-		{Position: Coordinates{X: 4, Y: 5}, Order: 1},
-		{Position: Coordinates{X: 3, Y: 5}, Order: 2},
+	levels := make([]string, 0, len(m.snakeGame.State.Snakes))
+	for _, s := range m.snakeGame.State.Snakes[1:] {
+		levels = append(levels, s.AI)
 	}
 
-	m.snakeGame.TickGen = 0
-	m.snakeGame.Game = Game{Status: "running", Score: 0}
-	m.snakeGame.Snake = Snake{Position: snakeBody, Direction: "right", Speed: 1, DieByHungerIn: hungerResetSeconds}
-	m.snakeGame.Food = generateFood(m.snakeGame.Snake, m.snakeGame.Food, m.terminal)
-	return m.snakeGame
+	sm := NewSnakeModel(m.terminal, levels)
+	sm.Loop = m.snakeGame.Loop
+	return sm
+}
+
+// newGameOptions asks which CPU opponents, if any, join a fresh session.
+func newGameOptions() Options {
+	items := make([]Option, 0)
+	for _, setup := range rivalSetups() {
+		levels := setup.Levels
+		items = append(items, Option{Text: setup.Text, Action: func(m model) model {
+			if err := createSessionGame(m.user()); err != nil {
+				m.err = err
+				return m
+			}
+			m.snakeGame = NewSnakeModel(m.terminal, levels)
+			return m
+		}})
+	}
+	items = append(items, dailyOption())
+	return Options{Prompt: "New game. Who do you want to play against?", Items: items}
 }
 
 // ----------------------------------------------------------------------------------
 // Config helpers (Viper)
 // ----------------------------------------------------------------------------------
-func userDirOrDie() string {
-	user, err := internal.GetUser()
+
+// errNotRegistered is returned for a player without a directory under
+// .gamics.
+var errNotRegistered = errors.New("user directory does not exist, please register first")
+
+// userDir is the directory a registered player's files live in.
+func userDir(user string) (string, error) {
+	dir := path.Join(".", ".gamics", user)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return "", errNotRegistered
+	}
+	return dir, nil
+}
+
+// sessionPath is the file a player's saved session of game lives in, such
+// as snake.yaml.
+func sessionPath(userDir, game string) string {
+	return path.Join(userDir, game+".yaml")
+}
+
+// sessionCfg opens the saved session of game of a player. Every call gets
+// its own Viper so that players served side by side never share a session.
+func sessionCfg(user, game string) (*viper.Viper, error) {
+	dir, err := userDir(user)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	cfg := viper.New()
+	cfg.SetConfigFile(sessionPath(dir, game))
+	return cfg, nil
+}
 
-	userDir := path.Join(".", ".gamics", user)
-	if _, err := os.Stat(userDir); os.IsNotExist(err) {
-		log.Fatalf("user directory does not exist, please register in first")
+// sessionExists reports whether user has a session of game to continue.
+func sessionExists(user, game string) bool {
+	dir, err := userDir(user)
+	if err != nil {
+		return false
 	}
-	return userDir
+	_, err = os.Stat(sessionPath(dir, game))
+	return err == nil
 }
 
-func bindSnakeViper(userDir string) {
-	snakeCfg.SetConfigName("snake")
-	snakeCfg.SetConfigType("yaml")
-	snakeCfg.AddConfigPath(userDir)
+// endSession removes the saved session of game, if there is one.
+func endSession(user, game string) error {
+	dir, err := userDir(user)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(sessionPath(dir, game)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove %s session file: %w", game, err)
+	}
+	return nil
 }
 
-func createSessionGame() string {
-	userDir := userDirOrDie()
-	bindSnakeViper(userDir)
+func createSessionGame(user string) error {
+	snakeCfg, err := sessionCfg(user, snakeSession)
+	if err != nil {
+		return err
+	}
 
-	snakeCfg.SetDefault("snake", Snake{
-		Position: []SnakePos{
-			{Position: Coordinates{X: 5, Y: 5}, Order: 0},
-			{Position: Coordinates{X: 4, Y: 5}, Order: 1},
-			{Position: Coordinates{X: 3, Y: 5}, Order: 2},
-		},
-		Direction:         "right",
-		RenderedDirection: "right",
-		Speed:             1,
-		DieByHungerIn:     hungerResetSeconds,
-	})
 	snakeCfg.SetDefault("score", 0)
-	snakeCfg.SetDefault("food", Food{Color: true, Position: Coordinates{X: -1, Y: -1}, Expire: time.Now().Add(foodTTL)})
 
 	if err := snakeCfg.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			log.Fatalf("could not read config file: %v", err)
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("could not read config file: %w", err)
 		}
-		cfgPath := path.Join(userDir, "snake.yaml")
+		cfgPath := snakeCfg.ConfigFileUsed()
 		if err := snakeCfg.SafeWriteConfigAs(cfgPath); err != nil {
-			log.Fatalf("could not create config file at %s: %v", cfgPath, err)
+			return fmt.Errorf("could not create config file at %s: %w", cfgPath, err)
 		}
 	}
-	return userDir
+	return nil
 }
 
-func updateConfig(m SnakeModel) {
-	snakeCfg.Set("snake", m.Snake)
+func updateConfig(user string, m SnakeModel) error {
+	snakeCfg, err := sessionCfg(user, snakeSession)
+	if err != nil {
+		return err
+	}
+	snakeCfg.Set("state", m.State)
 	snakeCfg.Set("score", m.Game.Score)
-	snakeCfg.Set("food", m.Food)
 	if err := snakeCfg.WriteConfig(); err != nil {
-		log.Fatalf("could not write config file: %v", err)
+		return fmt.Errorf("could not write config file: %w", err)
 	}
+	return nil
 }
 
-func endSessionGame() {
-	userDir := userDirOrDie()
+func endSessionGame(user string) error {
+	if !sessionExists(user, snakeSession) {
+		return nil
+	}
 
-	cfgPath := path.Join(userDir, "snake.yaml")
-	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
-		return
+	snakeCfg, err := sessionCfg(user, snakeSession)
+	if err != nil {
+		return err
+	}
+	if err := snakeCfg.ReadInConfig(); err != nil {
+		return fmt.Errorf("could not read snake config file: %w", err)
 	}
 
 	// Persist high score in a separate file with explicit path
-	profile := viper.New()
-	profile.Set("snake-highscore", snakeCfg.GetInt("score"))
-	if err := profile.WriteConfigAs(path.Join(userDir, "profile.yaml")); err != nil {
-		log.Fatalf("could not write profile file: %v", err)
+	err = updateProfile(user, func(p *viper.Viper) {
+		p.Set("snake-highscore", snakeCfg.GetInt("score"))
+	})
+	if err != nil {
+		return err
 	}
 
-	if err := os.Remove(cfgPath); err != nil {
-		log.Fatalf("could not remove snake.yaml file: %v", err)
-	}
+	return endSession(user, snakeSession)
 }
 
-func checkIfSnakeYamlFileExists() bool {
-	userDir := userDirOrDie()
-	_, err := os.Stat(path.Join(userDir, "snake.yaml"))
-	return err == nil
+// canContinueSnake reports whether user has a snake session to continue.
+// Sessions saved before the whole game went under the "state" key (as
+// "snake" and "food") can't be picked up again and count as none: the
+// player is offered a new game, which replaces the old file.
+func canContinueSnake(user string) bool {
+	if !sessionExists(user, snakeSession) {
+		return false
+	}
+	snakeCfg, err := sessionCfg(user, snakeSession)
+	if err != nil || snakeCfg.ReadInConfig() != nil {
+		return true // Continue reports what is wrong with it
+	}
+	return snakeCfg.IsSet("state")
 }
 
-func ContinueSnakeModel() SnakeModel {
-	userDir := userDirOrDie()
-	bindSnakeViper(userDir)
+func ContinueSnakeModel(user string) (SnakeModel, error) {
+	var m SnakeModel
+	snakeCfg, err := sessionCfg(user, snakeSession)
+	if err != nil {
+		return m, err
+	}
 
 	if err := snakeCfg.ReadInConfig(); err != nil {
-		log.Fatalf("could not read snake config file: %v", err)
+		return m, fmt.Errorf("could not read snake config file: %w", err)
 	}
 
-	var m SnakeModel
 	m.Game.Score = snakeCfg.GetInt("score")
-	if err := snakeCfg.UnmarshalKey("snake", &m.Snake); err != nil {
-		log.Fatalf("could not unmarshal snake: %v", err)
+	m.Turns = snake.NewTurnQueue(InputBufferSize)
+	if err := snakeCfg.UnmarshalKey("state", &m.State); err != nil {
+		return m, fmt.Errorf("could not unmarshal snake state: %w", err)
 	}
-	if err := snakeCfg.UnmarshalKey("food", &m.Food); err != nil {
-		log.Fatalf("could not unmarshal food: %v", err)
+	if len(m.State.Snakes) == 0 || !m.State.Snakes[0].Alive() {
+		return m, errors.New("snake session file has no snake to continue")
 	}
+	m.Blink = true
 	m.Game.Status = "running"
-	return m
+	return m, nil
 }
 
 // ----------------------------------------------------------------------------------
 // Update / View (Bubble Tea)
 // ----------------------------------------------------------------------------------
 func (m model) SnakeGameUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := updateSnakeGame(m, msg)
+	if nm, ok := next.(model); ok {
+		next = broadcastSnake(nm)
+	}
+	return next, cmd
+}
+
+func updateSnakeGame(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.snakeGame.Game.Status {
 	case "running":
 		return updateInRunningState(m, msg)
 	case "lost":
-		endSessionGame()
+		if m.snakeGame.Daily == "" {
+			if err := endSessionGame(m.user()); err != nil {
+				m.err = err
+			}
+		}
 		return updateInLostState(m, msg)
 	case "paused":
 		return updateInPausedState(m, msg)
@@ -315,19 +393,23 @@ func (m model) SnakeGameView() string {
 func updateInStartState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tickStartSnakeGame:
-		if !checkIfSnakeYamlFileExists() {
-			createSessionGame()
-			m.snakeGame = NewSnakeModel()
-			m.snakeGame.Food = generateFood(m.snakeGame.Snake, m.snakeGame.Food, m.terminal)
-			return m, tickRunSnakeGameCmd()
+		if !canContinueSnake(m.user()) {
+			m.snakeGame.Game.Options = newGameOptions()
+			return m, nil
 		}
 
-		m.snakeGame.Game.Options = Options{Items: []Option{
-			{Text: "Continue", Action: func(m model) model { m.snakeGame = ContinueSnakeModel(); return m }},
+		m.snakeGame.Game.Options = Options{Prompt: "You are already in a game session. What do you want to do?", Items: []Option{
+			{Text: "Continue", Action: func(m model) model {
+				sm, err := ContinueSnakeModel(m.user())
+				if err != nil {
+					m.err = err
+					return m
+				}
+				m.snakeGame = sm
+				return m
+			}},
 			{Text: "Start Over", Action: func(m model) model {
-				createSessionGame()
-				m.snakeGame = NewSnakeModel()
-				m.snakeGame.Food = generateFood(m.snakeGame.Snake, m.snakeGame.Food, m.terminal)
+				m.snakeGame.Game.Options = newGameOptions()
 				return m
 			}},
 		}, Cursor: 0}
@@ -351,9 +433,11 @@ func updateInStartState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			cur := m.snakeGame.Game.Options.Cursor
 			if cur >= 0 && cur < len(m.snakeGame.Game.Options.Items) {
+				loop := m.snakeGame.Loop
 				m = m.snakeGame.Game.Options.Items[cur].Action(m)
+				m.snakeGame.Loop = loop
 				if m.snakeGame.Game.Status == "running" {
-					return m, tickRunSnakeGameCmd()
+					return startSnakeLoop(m)
 				}
 			}
 			return m, nil
@@ -364,62 +448,47 @@ func updateInStartState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func updateInRunningState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tickHungerFoodMsg:
-		if msg.dieIn <= 0 {
-			m.snakeGame.Game.Status = "lost"
+	case loopMsg:
+		if !m.snakeGame.Loop.Owns(msg) {
 			return m, nil
 		}
-		m.snakeGame.Snake.DieByHungerIn--
-		return m, foodHungerTickCmd(1*time.Second, m.snakeGame.Snake.DieByHungerIn)
-
-	case tickBlinkFoodMsg:
-		rem := time.Until(m.snakeGame.Food.Expire)
-		switch {
-		case rem <= 0:
-			m.snakeGame.Food = generateFood(m.snakeGame.Snake, m.snakeGame.Food, m.terminal)
-			return m, foodBlinkTickCmd(500 * time.Millisecond)
-		case rem <= 3*time.Second:
-			m.snakeGame.Food.Color = !m.snakeGame.Food.Color
-			return m, foodBlinkTickCmd(60 * time.Millisecond)
-		case rem <= 5*time.Second:
-			m.snakeGame.Food.Color = !m.snakeGame.Food.Color
-			return m, foodBlinkTickCmd(120 * time.Millisecond)
-		default:
-			return m, foodBlinkTickCmd(500 * time.Millisecond)
-		}
-
-	case tickMsg:
-		if msg.gen != m.snakeGame.TickGen {
-			return m, nil
-		}
-		m.snakeGame.Snake.RenderedDirection = m.snakeGame.Snake.Direction
-		m.snakeGame = updateSnakeSituation(m)
-		if checkIfUserLose(m.snakeGame.Snake, m.terminal) {
-			m.snakeGame.Game.Status = "lost"
-			return m, nil
-		}
-		updateConfig(m.snakeGame)
-		return m, tickCmd(m.snakeGame.TickGen, m.terminal, m.snakeGame.Snake)
-
-	case tickRunSnakeGameMsg:
-		m.snakeGame.TickGen++
-		cmds := []tea.Cmd{
-			foodBlinkTickCmd(500 * time.Millisecond),
-			tickCmd(m.snakeGame.TickGen, m.terminal, m.snakeGame.Snake),
-			foodHungerTickCmd(1*time.Second, m.snakeGame.Snake.DieByHungerIn),
+		switch msg.Timer {
+		case timerBlink:
+			var next time.Duration
+			m.snakeGame.Blink, next = blinkFood(m.snakeGame.State.Food, m.snakeGame.Blink)
+			return m, m.snakeGame.Loop.After(timerBlink, next)
+
+		case timerMove:
+			var events []snake.Event
+			st := m.snakeGame.State
+			if m.snakeGame.Daily == "" {
+				st.Width, st.Height = fieldSize(m.terminal)
+			}
+			m.snakeGame.State, events = snake.Step(st, snake.Input{Turns: []string{m.snakeGame.Turns.Pop()}, Elapsed: st.TickInterval(0)})
+			m.snakeGame.Game.Score = m.snakeGame.player().Score
+			if playerDied(events) {
+				m.snakeGame.Game.Status = "lost"
+				m.snakeGame.Loop.Stop()
+				m.snakeGame, m.err = finishDaily(m.user(), m.snakeGame)
+				return m, nil
+			}
+			if m.snakeGame.Daily == "" {
+				if err := updateConfig(m.user(), m.snakeGame); err != nil {
+					m.err = err
+				}
+			}
+			return m, m.snakeGame.Loop.After(timerMove, m.snakeGame.State.TickInterval(0))
 		}
-		return m, tea.Batch(cmds...)
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "p", "q", "ctrl+c":
 			m.snakeGame.Game.Status = "paused"
+			m.snakeGame.Turns.Clear()
+			m.snakeGame.Loop.Stop()
 			return m, nil
 		case "up", "down", "left", "right":
-			opp := map[string]string{"up": "down", "down": "up", "left": "right", "right": "left"}
-			if m.snakeGame.Snake.RenderedDirection != opp[msg.String()] {
-				m.snakeGame.Snake.Direction = msg.String()
-			}
+			m.snakeGame.Turns.Push(msg.String(), m.snakeGame.player().Heading)
 			return m, nil
 		}
 	}
@@ -436,15 +505,14 @@ func updateInLostState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
+		case "esc":
+			return exitSnakeGame(m)
 		case "r":
-			m.snakeGame.TickGen++
-			m.snakeGame = RestartSnakeModel(m)
-			cmds := []tea.Cmd{
-				foodBlinkTickCmd(500 * time.Millisecond),
-				foodHungerTickCmd(1*time.Second, hungerResetSeconds),
-				tickCmd(m.snakeGame.TickGen, m.terminal, m.snakeGame.Snake),
+			if m.snakeGame.Daily != "" {
+				return m, nil // one attempt per day
 			}
-			return m, tea.Batch(cmds...)
+			m.snakeGame = RestartSnakeModel(m)
+			return startSnakeLoop(m)
 		}
 	}
 	return m, nil
@@ -459,23 +527,42 @@ func updateInPausedState(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c":
+			m.snakeGame, m.err = finishDaily(m.user(), m.snakeGame)
 			return m, tea.Quit
+		case "esc":
+			m.snakeGame, m.err = finishDaily(m.user(), m.snakeGame)
+			return exitSnakeGame(m)
 		case "r":
 			m.snakeGame.Game.Status = "running"
-			m.snakeGame.TickGen++
-			cmds := []tea.Cmd{
-				foodBlinkTickCmd(10 * time.Millisecond),
-				foodHungerTickCmd(1*time.Second, m.snakeGame.Snake.DieByHungerIn),
-				tickCmd(m.snakeGame.TickGen, m.terminal, m.snakeGame.Snake),
-			}
-			return m, tea.Batch(cmds...)
+			return startSnakeLoop(m)
 		}
 	}
 	return m, nil
 }
 
+// startSnakeLoop begins a new loop generation with the movement and blink
+// timers. Ticks still in flight from before are discarded.
+func startSnakeLoop(m model) (model, tea.Cmd) {
+	m.snakeGame.Loop.Start()
+	return m, tea.Batch(
+		m.snakeGame.Loop.After(timerMove, m.snakeGame.State.TickInterval(0)),
+		m.snakeGame.Loop.After(timerBlink, 10*time.Millisecond),
+	)
+}
+
+// exitSnakeGame stops the loop and goes back to the game list. A paused
+// session stays on disk and can be continued from the start menu.
+func exitSnakeGame(m model) (model, tea.Cmd) {
+	loop := m.snakeGame.Loop
+	loop.Stop()
+	m.snakeGame = InitNewSnakeModel()
+	m.snakeGame.Loop = loop
+	m.currentUI = LIST_GAMES_UI
+	return m, nil
+}
+
 func viewInStartState(m model) string {
-	if !checkIfSnakeYamlFileExists() {
+	if len(m.snakeGame.Game.Options.Items) == 0 {
 		l := lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#060", Dark: "#0B0"}).Bold(true)
 		return fullCenterBox(l, fmt.Sprintf("%s\n\n%s", draw.SNAKE_LOSE, "CARREGANDO..."), m.terminal)
 	}
@@ -489,219 +576,200 @@ func viewInStartState(m model) string {
 		tw.WriteString(txt.Render(m.snakeGame.Game.Options.Items[i].Text) + "\n")
 	}
 
-	message := fmt.Sprintf("You are already in a game session. What do you want to do?\n\n%s", tw.String())
+	message := fmt.Sprintf("%s\n\n%s", m.snakeGame.Game.Options.Prompt, tw.String())
 	return fullCenterBox(snakeBoxWarn, message, m.terminal)
 }
 
 func viewInRunningState(m model) string {
-	w, h := fieldSize(m.terminal)
-	foodBar := strings.Repeat("♥", max(m.snakeGame.Snake.DieByHungerIn, 0))
+	w, h := m.snakeGame.State.Width, m.snakeGame.State.Height
+	player := m.snakeGame.player()
+	hunger := snake.Seconds(player.Hunger)
+	foodBar := strings.Repeat("♥", hunger)
 
 	title := horizontalCenterBox(snakeAppTitleStyle, gameTitle, m.terminal)
-	stats := snakeAppStatsStyle.Render(fmt.Sprintf("Hunger: %ds: %s\nScore: %d", m.snakeGame.Snake.DieByHungerIn, foodBar, m.snakeGame.Game.Score))
-	snakeBox := snakeAppStyle.Width(w).Height(h).Render(drawApp(m.snakeGame.Food, m.snakeGame.Snake, m.terminal))
+	stats := snakeAppStatsStyle.Render(fmt.Sprintf("Hunger: %ds: %s\nScore: %d%s", hunger, foodBar, m.snakeGame.Game.Score, effectsLine(m.snakeGame)))
+	snakeBox := snakeAppStyle.Width(w).Height(h).Render(drawSnakeModel(m.renderer, m.snakeGame))
 	return fmt.Sprintf("%s\n\n%s\n\n%s", title, snakeBox, stats)
 }
 
 func viewInLostState(m model) string {
-	w, h := fieldSize(m.terminal)
+	w, h := m.snakeGame.State.Width, m.snakeGame.State.Height
 	title := horizontalCenterBox(snakeAppTitleStyle, gameTitle, m.terminal)
-	stats := snakeAppStatsStyle.Render("You lost! Press 'q' to quit or 'r' to restart.")
+	stats := snakeAppStatsStyle.Render("You lost! Press 'q' to quit, 'r' to restart or 'esc' for the menu.")
+	if m.snakeGame.Daily != "" {
+		stats = snakeAppStatsStyle.Render(fmt.Sprintf("Daily challenge over with %d points. Press 'q' to quit or 'esc' for the menu.\n%s",
+			m.snakeGame.Game.Score, dailySummary(m.user(), m.snakeGame)))
+	}
 	snakeBox := snakeAppStyle.Width(w).
 		Foreground(lipgloss.Color("#F00")).
 		Background(lipgloss.Color("#600")).
 		BorderForeground(lipgloss.Color("#F00")).
 		Height(h).
-		Render(drawApp(m.snakeGame.Food, m.snakeGame.Snake, m.terminal))
+		Render(drawSnakeModel(m.renderer, m.snakeGame))
 	return fmt.Sprintf("%s\n\n%s\n\n%s", title, snakeBox, stats)
 }
 
 func viewInPausedState(m model) string {
-	w, h := fieldSize(m.terminal)
+	w, h := m.snakeGame.State.Width, m.snakeGame.State.Height
 	title := horizontalCenterBox(snakeAppTitleStyle, gameTitle, m.terminal)
-	stats := snakeAppStatsStyle.Render("Game paused. Press 'q' to quit or 'r' to resume.")
-	snakeBox := snakeAppStyle.Width(w).Height(h).Render(drawApp(m.snakeGame.Food, m.snakeGame.Snake, m.terminal))
+	stats := snakeAppStatsStyle.Render("Game paused. Press 'q' to quit, 'r' to resume or 'esc' for the menu.")
+	snakeBox := snakeAppStyle.Width(w).Height(h).Render(drawSnakeModel(m.renderer, m.snakeGame))
 	return fmt.Sprintf("%s\n\n%s\n\n%s", title, snakeBox, stats)
 }
 
-// ----------------------------------------------------------------------------------
-// Ticking / Timing
-// ----------------------------------------------------------------------------------
-func tickCmd(gen int, t Terminal, s Snake) tea.Cmd {
-	d := tickInterval(t, s)
-	return tea.Tick(d, func(time.Time) tea.Msg { return tickMsg{gen: gen} })
-}
-
-func tickRunSnakeGameCmd() tea.Cmd {
-	return tea.Tick(runTickEvery, func(time.Time) tea.Msg { return tickRunSnakeGameMsg{} })
-}
-func foodHungerTickCmd(d time.Duration, dieIn int) tea.Cmd {
-	return tea.Tick(d, func(time.Time) tea.Msg { return tickHungerFoodMsg{dieIn: dieIn} })
-}
-func foodBlinkTickCmd(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(time.Time) tea.Msg { return tickBlinkFoodMsg{} })
-}
-
-func tickInterval(t Terminal, s Snake) time.Duration {
-	if s.Speed <= 0 {
-		s.Speed = 0.05
+// effectsLine lists the food on the field, the power-ups still active and
+// the score of every rival.
+func effectsLine(sm SnakeModel) string {
+	player := sm.player()
+	parts := []string{"Food: " + snake.LookupFoodKind(sm.State.Food.Kind).Name}
+	if player.Slow() {
+		parts = append(parts, fmt.Sprintf("Slow %ds", snake.Seconds(player.SlowLeft)))
 	}
-	const (
-		baseMs  = 200.0
-		minMs   = 16.0
-		maxMs   = 1000.0
-		refDiag = 80.0
-	)
-	size := math.Hypot(float64(t.Width), float64(t.Height))
-	ms := s.Speed * baseMs / (1.0 + size/refDiag)
-	if ms < minMs {
-		ms = minMs
-	} else if ms > maxMs {
-		ms = maxMs
+	if player.Ghost() {
+		parts = append(parts, fmt.Sprintf("Ghost %ds", snake.Seconds(player.GhostLeft)))
+	}
+	for _, r := range sm.State.Snakes[1:] {
+		state := fmt.Sprintf("%d", r.Score)
+		if !r.Alive() {
+			state += " (respawning)"
+		}
+		parts = append(parts, fmt.Sprintf("CPU %s: %s", r.AI, state))
 	}
-	return time.Duration(ms) * time.Millisecond
+	return "\n" + strings.Join(parts, " | ")
 }
 
 // ----------------------------------------------------------------------------------
-// Game logic & rendering
+// Game glue & rendering
 // ----------------------------------------------------------------------------------
-func checkIfUserLose(s Snake, t Terminal) bool {
-	w, h := fieldSize(t)
-	head := s.Position[0].Position
-	if head.X < 0 || head.X >= w || head.Y < 0 || head.Y >= h {
-		return true
-	}
-	for i := 1; i < len(s.Position); i++ {
-		if head.X == s.Position[i].Position.X && head.Y == s.Position[i].Position.Y {
+
+// playerDied reports whether the player's snake (index 0) died in events.
+func playerDied(events []snake.Event) bool {
+	for _, e := range events {
+		if e.Kind == snake.EventDied && e.Snake == 0 {
 			return true
 		}
 	}
 	return false
 }
 
-func updateSnakeSituation(m model) SnakeModel {
-	if len(m.snakeGame.Snake.Position) == 0 {
-		return m.snakeGame
-	}
-
-	head := m.snakeGame.Snake.Position[0].Position
-	newHead := head
-	switch m.snakeGame.Snake.RenderedDirection {
-	case "up":
-		newHead.Y--
-	case "down":
-		newHead.Y++
-	case "left":
-		newHead.X--
-	case "right":
-		newHead.X++
+// blinkFood advances the blink cycle of the food: it flickers faster as its
+// remaining game time runs out. It returns the delay until the next blink.
+func blinkFood(f snake.Food, lit bool) (bool, time.Duration) {
+	switch {
+	case f.TTL <= 3*time.Second:
+		return !lit, 60 * time.Millisecond
+	case f.TTL <= 5*time.Second:
+		return !lit, 120 * time.Millisecond
 	default:
-		return m.snakeGame
+		return true, 500 * time.Millisecond
 	}
+}
 
-	// If no food eaten, move tail
-	if m.snakeGame.Food.Position.X != newHead.X || m.snakeGame.Food.Position.Y != newHead.Y {
-		for i := len(m.snakeGame.Snake.Position) - 1; i >= 1; i-- {
-			m.snakeGame.Snake.Position[i].Position = m.snakeGame.Snake.Position[i-1].Position
-			m.snakeGame.Snake.Position[i].Order = i
-		}
-		m.snakeGame.Snake.Position[0].Position = newHead
-		m.snakeGame.Snake.Position[0].Order = 0
-		return m.snakeGame
+// snakePalette holds the head/tail gradient ends of a snake for light and
+// dark terminals.
+type snakePalette struct {
+	LightHead, LightTail string
+	DarkHead, DarkTail   string
+}
+
+var defaultSnakePalette = snakePalette{LightHead: "#0B321F", LightTail: "#9BE8C3", DarkHead: "#49D491", DarkTail: "#0C321D"}
+
+func (p snakePalette) colors(r *lipgloss.Renderer, n int) []string {
+	if r.HasDarkBackground() {
+		return internal.InterpolateHexColors(p.DarkHead, p.DarkTail, max(n, 1))
 	}
+	return internal.InterpolateHexColors(p.LightHead, p.LightTail, max(n, 1))
+}
 
-	// Ate food
-	m.snakeGame.Snake.DieByHungerIn = hungerResetSeconds
-	m.snakeGame.Game.Score++
-	m.snakeGame.Snake.Speed -= 0.05
-	m.snakeGame.Snake.Position = append([]SnakePos{{Position: newHead, Order: 0}}, m.snakeGame.Snake.Position...)
-	m.snakeGame.Food = generateFood(m.snakeGame.Snake, m.snakeGame.Food, m.terminal)
-	for i := len(m.snakeGame.Snake.Position) - 1; i >= 1; i-- {
-		m.snakeGame.Snake.Position[i].Order = i
+func (p snakePalette) head(r *lipgloss.Renderer) string { return p.colors(r, 1)[0] }
+
+// drawSnakeModel renders the solo field, rivals included.
+func drawSnakeModel(r *lipgloss.Renderer, sm SnakeModel) string {
+	palettes := []snakePalette{defaultSnakePalette}
+	if len(sm.State.Snakes) > 1 {
+		palettes = versusPalettes
 	}
-	return m.snakeGame
+	return drawField(r, sm.State.Food, sm.Blink, sm.State.Snakes, palettes, sm.State.Width, sm.State.Height)
 }
 
-func drawApp(f Food, s Snake, t Terminal) string {
+// drawField renders the food and every snake with r, each snake with its
+// own gradient on a w×h field. palettes[i] colors snakes[i].
+func drawField(r *lipgloss.Renderer, f snake.Food, lit bool, snakes []snake.Snake, palettes []snakePalette, fw, fh int) string {
 	var sb strings.Builder
 
-	colorHead, colorTail := "#0B321F", "#9BE8C3"
-	colorFood := "#2FC67D"
-	if renderer.HasDarkBackground() {
-		colorHead, colorTail = "#49D491", "#0C321D"
-		colorFood = "#F0D700"
+	type cell struct {
+		Position snake.Point
+		Color    string
+		Glyph    string
+		Solid    bool
 	}
 
-	colors := internal.InterpolateHexColors(colorHead, colorTail, len(s.Position))
+	colorFood := foodColors[snake.LookupFoodKind(f.Kind).Name][0]
+	if r.HasDarkBackground() {
+		colorFood = foodColors[snake.LookupFoodKind(f.Kind).Name][1]
+	}
 
-	// Build positions WITHOUT mutating s.Position (avoid hidden side-effects)
-	positions := make([]SnakePos, 0, len(s.Position)+1)
-	positions = append(positions, s.Position...)
-	positions = append(positions, SnakePos{Position: Coordinates{X: f.Position.X, Y: f.Position.Y}, Order: -1})
+	// Snakes drawn first win a shared cell; the food goes last.
+	seen := make(map[snake.Point]bool)
+	cells := make([]cell, 0, len(snakes)*8+1)
+	for i, s := range snakes {
+		colors := palettes[i%len(palettes)].colors(r, len(s.Body))
+		glyph, solid := "█", true
+		if s.Ghost() {
+			glyph, solid = "▒", false
+		}
+		for order, p := range s.Body {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			cells = append(cells, cell{Position: p, Color: colors[min(order, len(colors)-1)], Glyph: glyph, Solid: solid})
+		}
+	}
+	if !seen[f.Position] {
+		if lit {
+			cells = append(cells, cell{Position: f.Position, Color: colorFood, Glyph: " ", Solid: true})
+		} else {
+			cells = append(cells, cell{Position: f.Position, Glyph: " "})
+		}
+	}
 
-	sort.SliceStable(positions, func(i, j int) bool {
-		if positions[i].Position.Y == positions[j].Position.Y {
-			return positions[i].Position.X < positions[j].Position.X
+	sort.SliceStable(cells, func(i, j int) bool {
+		if cells[i].Position.Y == cells[j].Position.Y {
+			return cells[i].Position.X < cells[j].Position.X
 		}
-		return positions[i].Position.Y < positions[j].Position.Y
+		return cells[i].Position.Y < cells[j].Position.Y
 	})
 
-	fw, fh := fieldSize(t)
 	curY, curX := 0, 0
-	for _, pos := range positions {
-		if pos.Position.X < 0 || pos.Position.X >= fw || pos.Position.Y < 0 || pos.Position.Y >= fh {
+	for _, c := range cells {
+		if c.Position.X < 0 || c.Position.X >= fw || c.Position.Y < 0 || c.Position.Y >= fh {
 			return ""
 		}
 
-		for curY < pos.Position.Y {
+		for curY < c.Position.Y {
 			sb.WriteString("\n")
 			curX = 0
 			curY++
 		}
-		for curX < pos.Position.X-1 {
+		for curX < c.Position.X-1 {
 			sb.WriteString(" ")
 			curX++
 		}
 
-		part := lipgloss.NewStyle()
-		if pos.Order == -1 {
-			if f.Color {
-				part = part.Background(lipgloss.Color(colorFood)).Foreground(lipgloss.Color(colorFood))
+		part := r.NewStyle()
+		if c.Color != "" {
+			part = part.Foreground(lipgloss.Color(c.Color))
+			if c.Solid {
+				part = part.Background(lipgloss.Color(c.Color))
 			}
-			sb.WriteString(part.Render(" "))
-			curX++
-			continue
-		}
-
-		idx := max(pos.Order, 0)
-		if idx >= len(colors) {
-			idx = len(colors) - 1
 		}
-		sb.WriteString(part.Foreground(lipgloss.Color(colors[idx])).Background(lipgloss.Color(colors[idx])).Render("█"))
+		sb.WriteString(part.Render(c.Glyph))
 		curX++
 	}
 	return sb.String()
 }
 
-func generateFood(s Snake, f Food, t Terminal) Food {
-	w, h := fieldSize(t)
-	occupied := make(map[[2]int]bool, len(s.Position))
-	for _, p := range s.Position {
-		occupied[[2]int{p.Position.X, p.Position.Y}] = true
-	}
-
-	for tries := 0; tries < 1_000; tries++ { // safety cap
-		x := rand.Intn(max(w, 1))
-		y := rand.Intn(max(h, 1))
-		if !occupied[[2]int{x, y}] {
-			return Food{Color: true, Position: Coordinates{X: x, Y: y}, Expire: time.Now().Add(foodTTL)}
-		}
-	}
-	// Fallback: keep previous food, extend TTL
-	f.Expire = time.Now().Add(foodTTL)
-	return f
-}
-
 // ----------------------------------------------------------------------------------
 // Layout helpers
 // ----------------------------------------------------------------------------------
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"gamics/daily"
+	"gamics/games/snake"
+	"gamics/internal"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	dailySnakeGame   = "snake"
+	dailySnakeWidth  = 60
+	dailySnakeHeight = 20
+	dailyBoardShown  = 5
+)
+
+var gamicsRoot = path.Join(".", ".gamics")
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// DailyResult is what the lost screen shows once a daily attempt is over.
+type DailyResult struct {
+	Board  daily.Board
+	Streak int
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewDailySnakeModel starts the day's challenge: a fixed field and the
+// shared seed, so every player gets the same food sequence.
+func NewDailySnakeModel(day time.Time) SnakeModel {
+	st := snake.New(snake.Config{
+		Width:  dailySnakeWidth,
+		Height: dailySnakeHeight,
+		Seed:   daily.Seed(dailySnakeGame, day),
+		Snakes: []snake.SnakeConfig{{
+			Body:    []snake.Point{{X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 5}},
+			Heading: snake.Right,
+		}},
+	})
+	return SnakeModel{State: st, Turns: snake.NewTurnQueue(InputBufferSize), Blink: true, Daily: daily.Key(day), Game: Game{Status: "running"}}
+}
+
+// dailyOption is the start-menu entry of today's challenge.
+func dailyOption() Option {
+	day := daily.Today()
+	return Option{Text: fmt.Sprintf("Daily challenge (%s)", daily.Key(day)), Action: func(m model) model {
+		back := []Option{{Text: "Back", Action: func(m model) model {
+			m.snakeGame.Game.Options = newGameOptions()
+			return m
+		}}}
+
+		if w, h := fieldSize(m.terminal); w < dailySnakeWidth || h < dailySnakeHeight {
+			m.snakeGame.Game.Options = Options{Prompt: fmt.Sprintf(
+				"The daily challenge is played on a %dx%d field.\nEnlarge the terminal to at least %dx%d and try again.",
+				dailySnakeWidth, dailySnakeHeight, dailySnakeWidth+2, dailySnakeHeight+10), Items: back}
+			return m
+		}
+
+		user := m.user()
+		err := daily.Begin(gamicsRoot, dailySnakeGame, user, day)
+		if errors.Is(err, daily.ErrAlreadyPlayed) {
+			board, err := daily.Load(gamicsRoot, dailySnakeGame, day)
+			if err != nil {
+				m.err = err
+				return m
+			}
+			e, _ := board.Find(user)
+			m.snakeGame.Game.Options = Options{Prompt: fmt.Sprintf(
+				"You already played today's challenge: score %d, rank %d of %d.\nCome back tomorrow!\n\n%s",
+				e.Score, board.Rank(user), len(board.Entries), dailyBoardLines(board, user)), Items: back}
+			return m
+		}
+		if err != nil {
+			m.err = err
+			return m
+		}
+
+		m.snakeGame = NewDailySnakeModel(day)
+		return m
+	}}
+}
+
+// ----------------------------------------------------------------------------------
+// Results
+// ----------------------------------------------------------------------------------
+
+// finishDaily stores the result of a daily attempt, moves the streak in the
+// profile and loads the board for the lost screen. It is a no-op outside
+// the daily challenge or once the result is stored.
+func finishDaily(user string, sm SnakeModel) (SnakeModel, error) {
+	if sm.Daily == "" || sm.DailyResult != nil {
+		return sm, nil
+	}
+	day, err := daily.Parse(sm.Daily)
+	if err != nil {
+		return sm, err
+	}
+
+	if err := daily.Finish(gamicsRoot, dailySnakeGame, user, day, sm.Game.Score, sm.State.Tick); err != nil {
+		return sm, err
+	}
+
+	var streak int
+	err = updateProfile(user, func(p *viper.Viper) {
+		streak = daily.NextStreak(p.GetString("daily-last"), p.GetInt("daily-streak"), day)
+		p.Set("daily-streak", streak)
+		p.Set("daily-last", daily.Key(day))
+		if streak > p.GetInt("daily-best-streak") {
+			p.Set("daily-best-streak", streak)
+		}
+	})
+	if err != nil {
+		return sm, err
+	}
+
+	board, err := daily.Load(gamicsRoot, dailySnakeGame, day)
+	if err != nil {
+		return sm, err
+	}
+	sm.DailyResult = &DailyResult{Board: board, Streak: streak}
+	return sm, nil
+}
+
+func dailySummary(user string, sm SnakeModel) string {
+	r := sm.DailyResult
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf("Daily %s: rank %d of %d, streak %d day(s).\n%s",
+		sm.Daily, r.Board.Rank(user), len(r.Board.Entries), r.Streak, dailyBoardLines(r.Board, user))
+}
+
+// dailyBoardLines renders the top of a board, marking the player's entry.
+func dailyBoardLines(b daily.Board, player string) string {
+	lines := make([]string, 0, dailyBoardShown)
+	for i, e := range b.Entries {
+		if i == dailyBoardShown {
+			break
+		}
+		mark := "  "
+		if e.Player == player {
+			mark = "> "
+		}
+		status := ""
+		if !e.Finished {
+			status = " (playing)"
+		}
+		lines = append(lines, fmt.Sprintf("%s%d. %-12s %4d%s", mark, i+1, e.Player, e.Score, status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ----------------------------------------------------------------------------------
+// Profile helpers
+// ----------------------------------------------------------------------------------
+func currentUserOrDie() string {
+	user, err := internal.GetUser()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return user
+}
+
+// loadProfile reads the profile.yaml of user; a player without one gets an
+// empty profile.
+func loadProfile(user string) (*viper.Viper, error) {
+	dir, err := userDir(user)
+	if err != nil {
+		return nil, err
+	}
+	profile := viper.New()
+	profile.SetConfigFile(path.Join(dir, "profile.yaml"))
+	if err := profile.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("could not read profile file: %w", err)
+		}
+	}
+	return profile, nil
+}
+
+// updateProfile loads profile.yaml, lets set change it and writes it back,
+// keeping every key set leaves alone.
+func updateProfile(user string, set func(p *viper.Viper)) error {
+	profile, err := loadProfile(user)
+	if err != nil {
+		return err
+	}
+	set(profile)
+	if err := profile.WriteConfig(); err != nil {
+		return fmt.Errorf("could not write profile file: %w", err)
+	}
+	return nil
+}
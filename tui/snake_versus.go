@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/snake"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	versusTitle       = "Snake Versus"
+	versusRoundsToWin = 3
+)
+
+var (
+	versusPalettes = []snakePalette{
+		defaultSnakePalette,
+		{LightHead: "#7A2E00", LightTail: "#FFC58F", DarkHead: "#FF8A3D", DarkTail: "#3A1600"},
+		{LightHead: "#0B2E6B", LightTail: "#A8C8FF", DarkHead: "#5C9DFF", DarkTail: "#0A1A3A"},
+		{LightHead: "#5A0B5E", LightTail: "#F1B5F4", DarkHead: "#E070E6", DarkTail: "#2E0830"},
+	}
+
+	arrowKeys = map[string]string{"up": "up", "down": "down", "left": "left", "right": "right"}
+	wasdKeys  = map[string]string{"w": "up", "s": "down", "a": "left", "d": "right"}
+)
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+type VersusPlayer struct {
+	Name    string
+	Keys    map[string]string // key pressed -> direction
+	Palette snakePalette
+	Wins    int
+	Turns   snake.TurnQueue // turns typed since the last tick
+}
+
+// VersusModel wraps the engine state of a versus round; Players[i] drives
+// State.Snakes[i].
+type VersusModel struct {
+	State   snake.State
+	Players []VersusPlayer
+	Blink   bool
+	Round   int
+	Status  string // "start", "running", "paused", "roundOver", "matchOver"
+	Winner  int    // index into Players, -1 for a draw
+	Loop    gameLoop
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+func InitNewSnakeVersusModel() VersusModel {
+	return VersusModel{
+		Status: "start",
+		Winner: -1,
+		Loop:   gameLoop{Name: SNAKE_VS_UI},
+		Players: []VersusPlayer{
+			{Name: "Player 1", Keys: arrowKeys, Palette: versusPalettes[0]},
+			{Name: "Player 2", Keys: wasdKeys, Palette: versusPalettes[1]},
+		},
+	}
+}
+
+// startVersusRound lines every player up on its spawn lane and resets the
+// per-round state. Wins carry over between rounds.
+func startVersusRound(vm VersusModel, t Terminal) VersusModel {
+	w, h := fieldSize(t)
+	snakes := make([]snake.SnakeConfig, len(vm.Players))
+	for i := range vm.Players {
+		vm.Players[i].Turns = snake.NewTurnQueue(InputBufferSize)
+	}
+	vm.State = snake.New(snake.Config{Width: w, Height: h, Seed: uint64(time.Now().UnixNano()), Snakes: snakes})
+	vm.Blink = true
+	vm.Round++
+	vm.Winner = -1
+	vm.Status = "running"
+	return vm
+}
+
+func (vm VersusModel) palettes() []snakePalette {
+	out := make([]snakePalette, 0, len(vm.Players))
+	for _, p := range vm.Players {
+		out = append(out, p.Palette)
+	}
+	return out
+}
+
+// turns pops the next queued turn of every player.
+func (vm *VersusModel) turns() []string {
+	out := make([]string, 0, len(vm.Players))
+	for i := range vm.Players {
+		out = append(out, vm.Players[i].Turns.Pop())
+	}
+	return out
+}
+
+// pace is the shared movement tick: the fastest player sets the speed and
+// any slow-motion pickup slows the whole field.
+func (vm VersusModel) pace() time.Duration {
+	pace := snake.Snake{Speed: 1}
+	for _, sn := range vm.State.Snakes {
+		if !sn.Alive() {
+			continue
+		}
+		pace.Speed = min(pace.Speed, sn.Speed)
+		if sn.SlowLeft > pace.SlowLeft {
+			pace.SlowLeft = sn.SlowLeft
+		}
+	}
+	field := snake.State{Width: vm.State.Width, Height: vm.State.Height, Snakes: []snake.Snake{pace}}
+	return field.TickInterval(0)
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) SnakeVersusUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch m.snakeVersus.Status {
+	case "running":
+		return updateVersusRunning(m, msg)
+	case "paused":
+		return updateVersusPaused(m, msg)
+	default:
+		return updateVersusIdle(m, msg)
+	}
+}
+
+func (m model) SnakeVersusView() string {
+	vm := m.snakeVersus
+	w, h := vm.State.Width, vm.State.Height
+	title := horizontalCenterBox(snakeAppTitleStyle, fmt.Sprintf("%s — Round %d", versusTitle, max(vm.Round, 1)), m.terminal)
+
+	var footer string
+	switch vm.Status {
+	case "start":
+		lines := []string{fmt.Sprintf("First to %d rounds wins the match.", versusRoundsToWin)}
+		for _, p := range vm.Players {
+			lines = append(lines, versusNameStyle(m.renderer, p).Render(p.Name)+": "+versusControls(p))
+		}
+		lines = append(lines, "", "Press 'enter' to start, 'esc' for the menu or 'q' to quit.")
+		return fullCenterBox(snakeBoxWarn, strings.Join(lines, "\n"), m.terminal)
+	case "paused":
+		footer = "Game paused. Press 'q' to quit, 'r' to resume or 'esc' for the menu."
+	case "roundOver":
+		footer = versusResultLine(vm) + " Press 'enter' for the next round."
+	case "matchOver":
+		footer = versusResultLine(vm) + " Press 'r' for a rematch, 'esc' for the menu or 'q' to quit."
+	}
+
+	box := snakeAppStyle.Width(w).Height(h)
+	if vm.Status == "matchOver" {
+		box = box.BorderForeground(lipgloss.Color("#F00"))
+	}
+	field := box.Render(drawField(m.renderer, vm.State.Food, vm.Blink, vm.State.Snakes, vm.palettes(), vm.State.Width, vm.State.Height))
+	return fmt.Sprintf("%s\n\n%s\n\n%s", title, field, versusStats(m.renderer, vm, footer))
+}
+
+func updateVersusIdle(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return exitSnakeVersus(m)
+		case "enter":
+			if m.snakeVersus.Status == "matchOver" {
+				return m, nil
+			}
+			return startVersus(m)
+		case "r":
+			if m.snakeVersus.Status != "matchOver" {
+				return m, nil
+			}
+			for i := range m.snakeVersus.Players {
+				m.snakeVersus.Players[i].Wins = 0
+			}
+			m.snakeVersus.Round = 0
+			return startVersus(m)
+		}
+	}
+	return m, nil
+}
+
+func updateVersusPaused(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			return exitSnakeVersus(m)
+		case "r":
+			m.snakeVersus.Status = "running"
+			return startVersusLoop(m)
+		}
+	}
+	return m, nil
+}
+
+func updateVersusRunning(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	vm := &m.snakeVersus
+	switch msg := msg.(type) {
+	case loopMsg:
+		if !vm.Loop.Owns(msg) {
+			return m, nil
+		}
+		switch msg.Timer {
+		case timerMove:
+			vm.State, _ = snake.Step(vm.State, snake.Input{Turns: vm.turns(), Elapsed: vm.pace()})
+			m.snakeVersus = settleVersusRound(m.snakeVersus)
+			if m.snakeVersus.Status != "running" {
+				return m, nil
+			}
+			return m, m.snakeVersus.Loop.After(timerMove, m.snakeVersus.pace())
+
+		case timerBlink:
+			var next time.Duration
+			vm.Blink, next = blinkFood(vm.State.Food, vm.Blink)
+			return m, vm.Loop.After(timerBlink, next)
+		}
+
+	case tea.KeyMsg:
+		k := msg.String()
+		switch k {
+		case "p", "q", "ctrl+c":
+			vm.Status = "paused"
+			vm.Loop.Stop()
+			for i := range vm.Players {
+				vm.Players[i].Turns.Clear()
+			}
+			return m, nil
+		}
+		for i := range vm.Players {
+			p := &vm.Players[i]
+			dir, ok := p.Keys[k]
+			if !ok || !vm.State.Snakes[i].Alive() {
+				continue
+			}
+			p.Turns.Push(dir, vm.State.Snakes[i].Heading)
+		}
+	}
+	return m, nil
+}
+
+func startVersus(m model) (tea.Model, tea.Cmd) {
+	m.snakeVersus = startVersusRound(m.snakeVersus, m.terminal)
+	return startVersusLoop(m)
+}
+
+// startVersusLoop begins a new loop generation with the shared movement
+// and blink timers.
+func startVersusLoop(m model) (model, tea.Cmd) {
+	vm := &m.snakeVersus
+	vm.Loop.Start()
+	return m, tea.Batch(
+		vm.Loop.After(timerMove, vm.pace()),
+		vm.Loop.After(timerBlink, 10*time.Millisecond),
+	)
+}
+
+// exitSnakeVersus stops the loop and goes back to the game list. The match
+// is dropped; wins do not carry over to the next visit.
+func exitSnakeVersus(m model) (model, tea.Cmd) {
+	loop := m.snakeVersus.Loop
+	loop.Stop()
+	m.snakeVersus = InitNewSnakeVersusModel()
+	m.snakeVersus.Loop = loop
+	m.currentUI = LIST_GAMES_UI
+	return m, nil
+}
+
+// ----------------------------------------------------------------------------------
+// Game logic
+// ----------------------------------------------------------------------------------
+
+// settleVersusRound ends the round once at most one snake is left and ends
+// the match once someone reached versusRoundsToWin.
+func settleVersusRound(vm VersusModel) VersusModel {
+	alive := make([]int, 0, len(vm.Players))
+	for i, sn := range vm.State.Snakes {
+		if sn.Alive() {
+			alive = append(alive, i)
+		}
+	}
+	if len(alive) > 1 {
+		return vm
+	}
+
+	vm.Loop.Stop()
+	vm.Winner = -1
+	vm.Status = "roundOver"
+	if len(alive) == 1 {
+		vm.Winner = alive[0]
+		vm.Players[vm.Winner].Wins++
+		if vm.Players[vm.Winner].Wins >= versusRoundsToWin {
+			vm.Status = "matchOver"
+		}
+	}
+	return vm
+}
+
+// ----------------------------------------------------------------------------------
+// Rendering helpers
+// ----------------------------------------------------------------------------------
+func versusNameStyle(r *lipgloss.Renderer, p VersusPlayer) lipgloss.Style {
+	return r.NewStyle().Bold(true).Foreground(lipgloss.Color(p.Palette.head(r)))
+}
+
+func versusControls(p VersusPlayer) string {
+	if _, ok := p.Keys["w"]; ok {
+		return "W A S D"
+	}
+	return "arrow keys"
+}
+
+func versusResultLine(vm VersusModel) string {
+	if vm.Winner < 0 {
+		return "Draw — nobody scores this round."
+	}
+	p := vm.Players[vm.Winner]
+	if vm.Status == "matchOver" {
+		return fmt.Sprintf("%s wins the match!", p.Name)
+	}
+	return fmt.Sprintf("%s wins round %d.", p.Name, vm.Round)
+}
+
+func versusStats(r *lipgloss.Renderer, vm VersusModel, footer string) string {
+	cols := make([]string, 0, 2*len(vm.Players))
+	for i, p := range vm.Players {
+		if i > 0 {
+			cols = append(cols, "    ")
+		}
+		sn := vm.State.Snakes[i]
+		name := p.Name
+		if !sn.Alive() && vm.Status == "running" {
+			name += " ✗"
+		}
+		hunger := strings.Repeat("♥", snake.Seconds(sn.Hunger))
+		cols = append(cols, fmt.Sprintf("%s  Wins: %d  Score: %d\nHunger: %s",
+			versusNameStyle(r, p).Render(name), p.Wins, sn.Score, hunger))
+	}
+	stats := snakeAppStatsStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, cols...))
+	if footer != "" {
+		return stats + "\n" + snakeAppStatsStyle.Render(footer)
+	}
+	return stats
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"gamics/games/snake"
+	"gamics/spectate"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type watchFrameMsg json.RawMessage
+type watchEndedMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// snakeFrame is what a solo snake game broadcasts to its spectators:
+// everything the game screens draw, and nothing that belongs to the player
+// only (menus, typed-ahead turns, timers).
+type snakeFrame struct {
+	State       snake.State  `json:"state"`
+	Blink       bool         `json:"blink"`
+	Score       int          `json:"score"`
+	Status      string       `json:"status"`
+	Daily       string       `json:"daily,omitempty"`
+	DailyResult *DailyResult `json:"daily_result,omitempty"`
+}
+
+// WatchModel follows another player's game read-only.
+type WatchModel struct {
+	Watcher *spectate.Watcher
+	Frames  int  // frames received so far
+	Ended   bool // the player stopped playing
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewWatchModel opens the TUI on the game of player, received through w.
+func NewWatchModel(player string, w *spectate.Watcher) model {
+	m := NewPlayerModel(WATCH_UI, player, lipgloss.DefaultRenderer())
+	m.watch = WatchModel{Watcher: w}
+	return m
+}
+
+// ----------------------------------------------------------------------------------
+// Broadcasting
+// ----------------------------------------------------------------------------------
+
+// broadcastSnake publishes the solo game to spectators while it is on
+// screen, and ends the broadcast once the player is back in the menus.
+// Spectating is a nicety: when the socket cannot be opened the game goes on
+// without it.
+func broadcastSnake(m model) model {
+	sm := m.snakeGame
+	onScreen := m.currentUI == SNAKE_GAME_UI && sm.Game.Status != "start" && len(sm.State.Snakes) > 0
+	if !onScreen {
+		if m.spectators != nil {
+			m.spectators.Close()
+			m.spectators = nil
+		}
+		return m
+	}
+
+	if m.spectators == nil {
+		if m.spectateOff {
+			return m
+		}
+		b, err := spectate.Listen(spectate.SocketPath(gamicsRoot, m.user()))
+		if err != nil {
+			m.spectateOff = true
+			return m
+		}
+		m.spectators = b
+	}
+
+	m.spectators.Publish(snakeFrame{
+		State:       sm.State,
+		Blink:       sm.Blink,
+		Score:       sm.Game.Score,
+		Status:      sm.Game.Status,
+		Daily:       sm.Daily,
+		DailyResult: sm.DailyResult,
+	})
+	return m
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) WatchUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case watchEndedMsg:
+		m.watch.Ended = true
+		return m, nil
+
+	case watchFrameMsg:
+		var f snakeFrame
+		if err := json.Unmarshal(msg, &f); err == nil && len(f.State.Snakes) > 0 {
+			m.snakeGame.State = f.State
+			m.snakeGame.Blink = f.Blink
+			m.snakeGame.Game.Score = f.Score
+			m.snakeGame.Game.Status = f.Status
+			m.snakeGame.Daily = f.Daily
+			m.snakeGame.DailyResult = f.DailyResult
+			m.watch.Frames++
+		}
+		return m, waitFrameCmd(m.watch.Watcher)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m model) WatchView() string {
+	player := m.user()
+	if m.watch.Frames == 0 {
+		text := fmt.Sprintf("Waiting for %s's game...\n\nPress 'q' to stop watching.", player)
+		if m.watch.Ended {
+			text = fmt.Sprintf("%s stopped playing.\n\nPress 'q' to quit.", player)
+		}
+		return fullCenterBox(snakeBoxWarn, text, m.terminal)
+	}
+
+	status := fmt.Sprintf("Watching %s (read-only). Press 'q' to stop watching.", player)
+	if m.watch.Ended {
+		status = fmt.Sprintf("%s stopped playing. Press 'q' to quit.", player)
+	}
+	return fmt.Sprintf("%s\n%s", m.SnakeGameView(), snakeAppStatsStyle.Render(status))
+}
+
+// waitFrameCmd delivers the next frame; it is re-armed after each.
+func waitFrameCmd(w *spectate.Watcher) tea.Cmd {
+	return func() tea.Msg {
+		frame, ok := <-w.Frames()
+		if !ok {
+			return watchEndedMsg{}
+		}
+		return watchFrameMsg(frame)
+	}
+}
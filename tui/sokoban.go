@@ -0,0 +1,398 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/sokoban"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const sokobanTitle = "Sokoban"
+
+var (
+	// sokobanDir is where players drop their own .xsb packs.
+	sokobanDir = path.Join(gamicsRoot, "sokoban")
+
+	sokobanWallStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#585858"))
+	sokobanGoalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#D7AF00"))
+	sokobanBoxStyle    = lipgloss.NewStyle().Background(lipgloss.Color("#AF8700"))
+	sokobanHomeStyle   = lipgloss.NewStyle().Background(lipgloss.Color("#5FAF00")) // a box on a goal
+	sokobanPlayerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#000000")).Background(lipgloss.Color("#00AFD7"))
+
+	sokobanDirs = map[string]byte{
+		"up": sokoban.Up, "k": sokoban.Up, "w": sokoban.Up,
+		"down": sokoban.Down, "j": sokoban.Down, "s": sokoban.Down,
+		"left": sokoban.Left, "h": sokoban.Left, "a": sokoban.Left,
+		"right": sokoban.Right, "l": sokoban.Right, "d": sokoban.Right,
+	}
+)
+
+func init() {
+	initTableGames[SOKOBAN_UI] = func() tea.Cmd { return func() tea.Msg { return sokobanStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type sokobanStartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// SokobanModel plays the built-in packs and those dropped in
+// .gamics/sokoban. Undo and redo have no limit while a level is open.
+type SokobanModel struct {
+	Packs   []sokoban.Pack
+	Pack    int    // pack being played
+	Level   int    // level of the pack being played
+	Status  string // "packs", "levels", "playing", "solved"
+	Menu    Options
+	State   sokoban.State
+	History []sokoban.State // states before each move, newest last
+	Redo    []sokoban.State // states undone, the next to redo last
+	Record  sokobanRecord   // best of the level before this game
+	User    string
+	Message string
+	Warning string // packs that failed to load
+}
+
+// sokobanRecord is a player's best on a level, kept in profile.yaml.
+type sokobanRecord struct {
+	Moves  int // fewest moves, 0 while unsolved
+	Pushes int // fewest pushes
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewSokobanModel loads the packs and lists them.
+func NewSokobanModel(user string) (SokobanModel, error) {
+	sm := SokobanModel{Status: "packs", User: user, Packs: sokoban.Builtin()}
+	own, err := sokoban.LoadDir(sokobanDir)
+	if err != nil {
+		sm.Warning = err.Error()
+	}
+	sm.Packs = append(sm.Packs, own...)
+	sm.Menu, err = sm.packMenu()
+	return sm, err
+}
+
+// packMenu lists the packs with how far the player got in each.
+func (sm *SokobanModel) packMenu() (Options, error) {
+	p, err := loadProfile(sm.User)
+	if err != nil {
+		return Options{}, err
+	}
+	var items []Option
+	for i, pack := range sm.Packs {
+		solved := 0
+		for n := range pack.Levels {
+			if sokobanLoadRecord(p, pack, n).Moves > 0 {
+				solved++
+			}
+		}
+		text := fmt.Sprintf("%-24s %s", pack.Name, turnMutedStyle.Render(fmt.Sprintf("%d/%d solved", solved, len(pack.Levels))))
+		items = append(items, Option{Text: text, Action: func(m model) model {
+			m.sokoban.Pack = i
+			m.sokoban.Status = "levels"
+			m.sokoban.Menu, m.err = m.sokoban.levelMenu()
+			return m
+		}})
+	}
+	prompt := "Sokoban. Pick a level pack.\n" + turnMutedStyle.Render("Drop .xsb packs into "+sokobanDir+" to play them here.")
+	if sm.Warning != "" {
+		prompt += "\n" + turnErrorStyle.Render(sm.Warning)
+	}
+	return Options{Prompt: prompt, Items: items, Cursor: min(sm.Pack, max(len(items)-1, 0))}, nil
+}
+
+// levelMenu lists the levels of the pack with the player's records, the
+// cursor on the first one left to solve.
+func (sm *SokobanModel) levelMenu() (Options, error) {
+	p, err := loadProfile(sm.User)
+	if err != nil {
+		return Options{}, err
+	}
+	pack := sm.Packs[sm.Pack]
+	cursor := -1
+	var items []Option
+	for n, lv := range pack.Levels {
+		mark, best := " ", ""
+		if r := sokobanLoadRecord(p, pack, n); r.Moves > 0 {
+			mark = "✓"
+			best = turnMutedStyle.Render(fmt.Sprintf("%d moves · %d pushes", r.Moves, r.Pushes))
+		} else if cursor < 0 {
+			cursor = n
+		}
+		text := fmt.Sprintf("%s %3d. %-20s %s", mark, n+1, lv.Title, best)
+		items = append(items, Option{Text: text, Action: func(m model) model {
+			m.err = m.sokoban.open(n)
+			return m
+		}})
+	}
+	return Options{Prompt: pack.Name + ". Pick a level.", Items: items, Cursor: max(cursor, 0)}, nil
+}
+
+// open starts level n of the current pack.
+func (sm *SokobanModel) open(n int) error {
+	p, err := loadProfile(sm.User)
+	if err != nil {
+		return err
+	}
+	sm.Level = n
+	sm.State = sm.level().Start()
+	sm.History, sm.Redo = nil, nil
+	sm.Status = "playing"
+	sm.Record = sokobanLoadRecord(p, sm.Packs[sm.Pack], n)
+	sm.Message = ""
+	return nil
+}
+
+func (sm *SokobanModel) level() *sokoban.Level {
+	return &sm.Packs[sm.Pack].Levels[sm.Level]
+}
+
+// ----------------------------------------------------------------------------------
+// Records
+// ----------------------------------------------------------------------------------
+
+// sokobanKey names a record in profile.yaml, as
+// sokoban-<pack file>-<level>-moves, -pushes or -path.
+func sokobanKey(pack sokoban.Pack, n int, what string) string {
+	file := strings.ToLower(strings.ReplaceAll(pack.File, ".", "-"))
+	return fmt.Sprintf("sokoban-%s-%d-%s", file, n+1, what)
+}
+
+func sokobanLoadRecord(p *viper.Viper, pack sokoban.Pack, n int) sokobanRecord {
+	return sokobanRecord{Moves: p.GetInt(sokobanKey(pack, n, "moves")), Pushes: p.GetInt(sokobanKey(pack, n, "pushes"))}
+}
+
+// record keeps the fewest moves, with the moves themselves in LURD, and
+// the fewest pushes of the solved level.
+func (sm *SokobanModel) record() (string, error) {
+	pack, n, st, old := sm.Packs[sm.Pack], sm.Level, sm.State, sm.Record
+	var news []string
+	err := updateProfile(sm.User, func(v *viper.Viper) {
+		if old.Moves == 0 || st.Moves < old.Moves {
+			v.Set(sokobanKey(pack, n, "moves"), st.Moves)
+			v.Set(sokobanKey(pack, n, "path"), st.Path)
+			if old.Moves > 0 {
+				news = append(news, "fewest moves")
+			}
+		}
+		if old.Moves == 0 || st.Pushes < old.Pushes {
+			v.Set(sokobanKey(pack, n, "pushes"), st.Pushes)
+			if old.Moves > 0 {
+				news = append(news, "fewest pushes")
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	sm.Record = sokobanRecord{Moves: st.Moves, Pushes: st.Pushes}
+	if old.Moves > 0 {
+		sm.Record = sokobanRecord{Moves: min(old.Moves, st.Moves), Pushes: min(old.Pushes, st.Pushes)}
+	}
+	if len(news) == 0 {
+		return "", nil
+	}
+	return " New record: " + strings.Join(news, " and ") + ".", nil
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) SokobanUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	sm := &m.sokoban
+
+	switch msg := msg.(type) {
+	case sokobanStartMsg:
+		m.sokoban, m.err = NewSokobanModel(m.user())
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			switch sm.Status {
+			case "packs":
+				m.currentUI = LIST_GAMES_UI
+			case "levels":
+				sm.Status = "packs"
+				sm.Menu, m.err = sm.packMenu()
+			default:
+				sm.Status = "levels"
+				sm.Menu, m.err = sm.levelMenu()
+				sm.Menu.Cursor = sm.Level
+			}
+			return m, nil
+		}
+
+		switch sm.Status {
+		case "packs", "levels":
+			updateOptions(&sm.Menu, key, &m)
+		case "playing":
+			m.err = sm.updatePlaying(key)
+		case "solved":
+			switch key {
+			case "u", "backspace":
+				sm.undo()
+			case "enter", "n", " ":
+				if sm.Level+1 < len(sm.Packs[sm.Pack].Levels) {
+					m.err = sm.open(sm.Level + 1)
+					return m, nil
+				}
+				sm.Status = "levels"
+				sm.Menu, m.err = sm.levelMenu()
+			case "r":
+				m.err = sm.open(sm.Level)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (sm *SokobanModel) updatePlaying(key string) error {
+	if dir, ok := sokobanDirs[key]; ok {
+		next, moved := sm.State.Move(sm.level(), dir)
+		if !moved {
+			return nil
+		}
+		sm.History = append(sm.History, sm.State)
+		sm.Redo = nil
+		sm.State = next
+		return sm.settle()
+	}
+
+	switch key {
+	case "u", "backspace":
+		sm.undo()
+	case "y", "ctrl+r":
+		if len(sm.Redo) == 0 {
+			sm.Message = "Nothing to redo."
+			return nil
+		}
+		sm.History = append(sm.History, sm.State)
+		sm.State = sm.Redo[len(sm.Redo)-1]
+		sm.Redo = sm.Redo[:len(sm.Redo)-1]
+		return sm.settle()
+	case "r":
+		// Restarting is a move like any other, so it can be undone.
+		if len(sm.History) > 0 {
+			sm.History = append(sm.History, sm.State)
+			sm.Redo = nil
+			sm.State = sm.level().Start()
+		}
+	case "[", "]":
+		n := sm.Level + 1
+		if key == "[" {
+			n = sm.Level - 1
+		}
+		if n >= 0 && n < len(sm.Packs[sm.Pack].Levels) {
+			return sm.open(n)
+		}
+	}
+	return nil
+}
+
+// settle clears the last message and keeps the records of a level just
+// solved.
+func (sm *SokobanModel) settle() error {
+	sm.Message = ""
+	if !sm.State.Solved(sm.level()) {
+		return nil
+	}
+	sm.Status = "solved"
+	var err error
+	sm.Message, err = sm.record()
+	return err
+}
+
+func (sm *SokobanModel) undo() {
+	if len(sm.History) == 0 {
+		sm.Message = "Nothing to undo."
+		return
+	}
+	sm.Redo = append(sm.Redo, sm.State)
+	sm.State = sm.History[len(sm.History)-1]
+	sm.History = sm.History[:len(sm.History)-1]
+	sm.Status = "playing"
+	sm.Message = ""
+}
+
+func (m model) SokobanView() string {
+	sm := m.sokoban
+	switch sm.Status {
+	case "":
+		return ""
+	case "packs", "levels":
+		return viewOptions(sm.Menu, m.terminal)
+	}
+
+	lv, st := sm.level(), sm.State
+	header := turnStatusStyle.Render(fmt.Sprintf("Moves %d   Pushes %d   Boxes %d/%d   ", st.Moves, st.Pushes, st.BoxesHome(lv), len(st.Boxes)))
+	if sm.Record.Moves > 0 {
+		header += turnMutedStyle.Render(fmt.Sprintf("best %d moves · %d pushes", sm.Record.Moves, sm.Record.Pushes))
+	}
+	board := drawSokoban(lv, st)
+
+	var lines []string
+	if sm.Status == "solved" {
+		lines = append(lines, turnStatusStyle.Bold(true).Render(fmt.Sprintf("Solved in %d moves and %d pushes!%s", st.Moves, st.Pushes, sm.Message)))
+		lines = append(lines, turnMutedStyle.Render("enter next level · u undo · r replay · esc levels · q quit"))
+	} else {
+		if sm.Message != "" {
+			lines = append(lines, turnStatusStyle.Render(sm.Message))
+		}
+		lines = append(lines,
+			turnMutedStyle.Render("arrows move · u undo · y redo · r restart · [ ] previous/next level"),
+			turnMutedStyle.Render("esc levels · q quit"))
+	}
+
+	title := horizontalCenterBox(turnTitleStyle, fmt.Sprintf("%s · %s · %d. %s", sokobanTitle, sm.Packs[sm.Pack].Name, sm.Level+1, lv.Title), m.terminal)
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", board, "", strings.Join(lines, "\n"))
+	margin := max((m.terminal.Width-lipgloss.Width(board))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// drawSokoban draws the level at st, two columns per cell.
+func drawSokoban(lv *sokoban.Level, st sokoban.State) string {
+	rows := make([]string, lv.Height)
+	for y := 0; y < lv.Height; y++ {
+		var b strings.Builder
+		for x := 0; x < lv.Width; x++ {
+			p := sokoban.Point{X: x, Y: y}
+			t := lv.Tile(x, y)
+			switch {
+			case t == sokoban.Wall:
+				b.WriteString(sokobanWallStyle.Render("  "))
+			case p == st.Player && t == sokoban.Goal:
+				b.WriteString(sokobanPlayerStyle.Render("··"))
+			case p == st.Player:
+				b.WriteString(sokobanPlayerStyle.Render("  "))
+			case st.Box(p) >= 0 && t == sokoban.Goal:
+				b.WriteString(sokobanHomeStyle.Render("  "))
+			case st.Box(p) >= 0:
+				b.WriteString(sokobanBoxStyle.Render("  "))
+			case t == sokoban.Goal:
+				b.WriteString(sokobanGoalStyle.Render("··"))
+			default:
+				b.WriteString("  ")
+			}
+		}
+		rows[y] = b.String()
+	}
+	return strings.Join(rows, "\n")
+}
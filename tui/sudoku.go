@@ -0,0 +1,576 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/sudoku"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	sudokuTitle = "Sudoku"
+	// sudokuTallHeight is the terminal height from which cells are drawn
+	// three lines tall, with room for all nine pencil marks.
+	sudokuTallHeight = 40
+	sudokuHintLines  = 3 // deductions shown with a hint, the placement last
+)
+
+var (
+	sudokuGivenStyle    = lipgloss.NewStyle().Bold(true)
+	sudokuPlayerStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#5FAFFF"))
+	sudokuMarkStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#767676"))
+	sudokuConflictStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5F5F"))
+	sudokuCursorBg      = lipgloss.Color("#4E4E4E")
+	sudokuSameBg        = lipgloss.Color("#303A4A") // cells holding the digit under the cursor
+	sudokuHintBg        = lipgloss.Color("#2F5F2F")
+	sudokuGridStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#8A8A8A"))
+)
+
+func init() {
+	initTableGames[SUDOKU_UI] = func() tea.Cmd { return func() tea.Msg { return sudokuStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type sudokuStartMsg struct{}
+
+// sudokuPuzzleMsg carries a puzzle generated in the background.
+type sudokuPuzzleMsg struct {
+	loopMsg
+	Puzzle sudoku.Puzzle
+}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// SudokuModel is a game of Sudoku, on a generated or an imported puzzle.
+type SudokuModel struct {
+	Puzzle  sudoku.Puzzle
+	Grid    sudoku.Grid // givens and the player's digits
+	Marks   [sudoku.Cells]sudoku.Digits
+	Status  string // "menu", "import", "generating", "playing", "solved"
+	Menu    Options
+	Input   textinput.Model
+	Cursor  int
+	Pencil  bool // digits toggle pencil marks instead of filling cells
+	Hint    []sudoku.Step
+	Hints   int // hints taken this game
+	Started time.Time
+	Elapsed time.Duration // time of the solved game
+	Best    bool          // the solve beat the best time
+	Loop    gameLoop
+	User    string
+	Message string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+func NewSudokuModel(user string) (SudokuModel, error) {
+	in := textinput.New()
+	in.CharLimit = 2 * sudoku.Cells
+	in.Width = sudoku.Cells
+	in.Placeholder = "81 digits, . or 0 for empty cells"
+	sm := SudokuModel{Status: "menu", User: user, Input: in, Loop: gameLoop{Name: SUDOKU_UI}}
+	var err error
+	sm.Menu, err = sudokuMenu(user)
+	return sm, err
+}
+
+// sudokuMenu offers a puzzle of each grade, with the player's record on
+// it, or one of their own.
+func sudokuMenu(user string) (Options, error) {
+	p, err := loadProfile(user)
+	if err != nil {
+		return Options{}, err
+	}
+	var items []Option
+	for _, d := range sudoku.Difficulties {
+		text := fmt.Sprintf("%-8s", strings.ToUpper(d.String()[:1])+d.String()[1:])
+		if solved := p.GetInt(sudokuKey(d, "solved")); solved > 0 {
+			best := time.Duration(p.GetInt64(sudokuKey(d, "best"))) * time.Millisecond
+			text += turnMutedStyle.Render(fmt.Sprintf("  solved %d · best %s", solved, sudokuClock(best)))
+		}
+		items = append(items, Option{Text: text, Action: func(m model) model {
+			m.sudoku.Status = "generating"
+			m.sudoku.Message = ""
+			m.sudoku.Loop.Start()
+			return m
+		}})
+	}
+	items = append(items, Option{Text: "Import a puzzle…", Action: func(m model) model {
+		sm := &m.sudoku
+		sm.Status = "import"
+		sm.Message = ""
+		sm.Input.SetValue("")
+		sm.Input.Focus()
+		return m
+	}})
+	return Options{Prompt: "Sudoku. Pick a difficulty.", Items: items}, nil
+}
+
+func sudokuKey(d sudoku.Difficulty, what string) string {
+	return "sudoku-" + d.String() + "-" + what
+}
+
+func sudokuClock(d time.Duration) string {
+	s := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// generate deals a puzzle of the grade picked on the menu off the UI
+// goroutine; hard ones may take a few hundred grids.
+func (sm *SudokuModel) generate() tea.Cmd {
+	d := sudoku.Difficulties[min(sm.Menu.Cursor, len(sudoku.Difficulties)-1)]
+	name, gen := sm.Loop.Name, sm.Loop.Gen
+	seed := uint64(time.Now().UnixNano())
+	return func() tea.Msg {
+		return sudokuPuzzleMsg{loopMsg: loopMsg{Loop: name, Gen: gen, Timer: timerGen}, Puzzle: sudoku.Generate(d, seed)}
+	}
+}
+
+// play starts p with a fresh clock.
+func (sm *SudokuModel) play(p sudoku.Puzzle) tea.Cmd {
+	sm.Loop.Start()
+	sm.Puzzle, sm.Grid = p, p.Givens
+	sm.Marks = [sudoku.Cells]sudoku.Digits{}
+	sm.Status = "playing"
+	sm.Cursor, sm.Pencil = 0, false
+	sm.Hint, sm.Hints = nil, 0
+	sm.Started, sm.Elapsed, sm.Best = time.Now(), 0, false
+	sm.Message = fmt.Sprintf("A %s puzzle: it takes up to %s.", p.Difficulty, strings.ToLower(p.Technique))
+	return sm.Loop.After(timerClock, time.Second)
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) SudokuUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	sm := &m.sudoku
+
+	switch msg := msg.(type) {
+	case sudokuStartMsg:
+		m.sudoku, m.err = NewSudokuModel(m.user())
+		return m, nil
+
+	case sudokuPuzzleMsg:
+		if !sm.Loop.Owns(msg.loopMsg) || sm.Status != "generating" {
+			return m, nil
+		}
+		cmd := sm.play(msg.Puzzle)
+		return m, cmd
+
+	case loopMsg:
+		if !sm.Loop.Owns(msg) || msg.Timer != timerClock || sm.Status != "playing" {
+			return m, nil
+		}
+		return m, sm.Loop.After(timerClock, time.Second)
+
+	case tea.KeyMsg:
+		key := msg.String()
+		if sm.Status == "import" {
+			cmd := sm.updateImport(msg)
+			return m, cmd
+		}
+		switch key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			sm.Loop.Stop()
+			if sm.Status == "menu" {
+				m.currentUI = LIST_GAMES_UI
+				return m, nil
+			}
+			sm.Status = "menu"
+			sm.Menu, m.err = sudokuMenu(sm.User)
+			return m, nil
+		}
+
+		switch sm.Status {
+		case "menu":
+			updateOptions(&sm.Menu, key, &m)
+			switch m.sudoku.Status {
+			case "generating":
+				return m, m.sudoku.generate()
+			case "import":
+				return m, textinput.Blink
+			}
+		case "playing":
+			m.err = sm.updatePlaying(key)
+		case "solved":
+			switch key {
+			case "e":
+				m.err = sm.export()
+			case "n", "enter":
+				sm.Status = "menu"
+				sm.Menu, m.err = sudokuMenu(sm.User)
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateImport reads a puzzle typed or pasted as 81 characters.
+func (sm *SudokuModel) updateImport(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+	case "esc":
+		sm.Input.Blur()
+		sm.Status = "menu"
+		return nil
+	case "enter":
+		g, err := sudoku.Parse(sm.Input.Value())
+		if err != nil {
+			sm.Message = err.Error()
+			return nil
+		}
+		p, ok := sudoku.NewPuzzle(g)
+		if !ok {
+			sm.Message = "That puzzle doesn't have exactly one solution."
+			return nil
+		}
+		sm.Input.Blur()
+		return sm.play(p)
+	}
+	var cmd tea.Cmd
+	sm.Input, cmd = sm.Input.Update(msg)
+	return cmd
+}
+
+func (sm *SudokuModel) updatePlaying(key string) error {
+	c := sm.Cursor
+	switch key {
+	case "up", "k", "w":
+		sm.Cursor = (c + sudoku.Cells - sudoku.Size) % sudoku.Cells
+	case "down", "j", "s":
+		sm.Cursor = (c + sudoku.Size) % sudoku.Cells
+	case "left", "h", "a":
+		sm.Cursor = sudoku.Row(c)*sudoku.Size + (sudoku.Col(c)+sudoku.Size-1)%sudoku.Size
+	case "right", "l", "d":
+		sm.Cursor = sudoku.Row(c)*sudoku.Size + (sudoku.Col(c)+1)%sudoku.Size
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		return sm.enter(int(key[0] - '0'))
+	case "0", ".", "backspace", "delete":
+		if sm.Puzzle.Givens[c] == 0 {
+			sm.Grid[c], sm.Marks[c] = 0, 0
+		}
+	case "m", "p":
+		sm.Pencil = !sm.Pencil
+	case "M":
+		sm.fillMarks()
+	case "?", "H":
+		sm.hint()
+	case "e":
+		return sm.export()
+	case "n":
+		sm.Loop.Stop()
+		sm.Status = "menu"
+		var err error
+		sm.Menu, err = sudokuMenu(sm.User)
+		return err
+	}
+	return nil
+}
+
+// enter fills the cursor cell with n, or toggles n among its pencil marks.
+func (sm *SudokuModel) enter(n int) error {
+	c := sm.Cursor
+	if sm.Puzzle.Givens[c] != 0 {
+		sm.Message = "That cell is a given."
+		return nil
+	}
+	sm.Hint = nil
+	if sm.Pencil {
+		if sm.Grid[c] == 0 {
+			sm.Marks[c] ^= 1 << n
+		}
+		return nil
+	}
+	if sm.Grid[c] == n {
+		sm.Grid[c] = 0
+		return nil
+	}
+	sm.Grid[c] = n
+	// A placed digit rules itself out of the marks around it.
+	for p := 0; p < sudoku.Cells; p++ {
+		if sudoku.Sees(c, p) {
+			sm.Marks[p] &^= 1 << n
+		}
+	}
+	sm.Message = ""
+	if sm.Grid == sm.Puzzle.Solution {
+		return sm.solved()
+	}
+	return nil
+}
+
+// fillMarks pencils every candidate into the empty cells.
+func (sm *SudokuModel) fillMarks() {
+	sm.Marks = sm.Grid.Candidates()
+	sm.Message = "Pencilled in every candidate."
+}
+
+// hint points out the first wrong digit, or explains the next logical
+// step and prunes the pencil marks it rules out.
+func (sm *SudokuModel) hint() {
+	sm.Hints++
+	for c, v := range sm.Grid {
+		if v != 0 && v != sm.Puzzle.Solution[c] {
+			sm.Cursor, sm.Hint = c, nil
+			sm.Message = fmt.Sprintf("%s doesn't hold %d in the solution.", sudoku.CellName(c), v)
+			return
+		}
+	}
+	steps, ok := sudoku.Hint(sm.Grid)
+	if !ok {
+		sm.Hint = nil
+		sm.Message = "No technique known here finds the next step. Time to try a digit and see."
+		return
+	}
+	for _, st := range steps {
+		for _, r := range st.Removals {
+			sm.Marks[r.Cell] &^= 1 << r.Digit
+		}
+	}
+	sm.Hint = steps[max(len(steps)-sudokuHintLines, 0):]
+	sm.Cursor = steps[len(steps)-1].Cell
+	sm.Message = ""
+}
+
+// export writes the puzzle, the board so far and the solution as
+// 81-character strings to a file in the player's sudoku directory, next to
+// where the turn games keep their saves, and shows the puzzle and the path.
+func (sm *SudokuModel) export() error {
+	sm.Hint = nil
+	dir, err := turnSavesDir(sm.Loop.Name, sm.User)
+	if err != nil {
+		return err
+	}
+	file := path.Join(dir, time.Now().Format("20060102-150405")+".txt")
+	text := fmt.Sprintf("Puzzle   %s\nProgress %s\nSolution %s\n", sm.Puzzle.Givens, sm.Grid, sm.Puzzle.Solution)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("could not export the puzzle: %w", err)
+	}
+	if err := os.WriteFile(file, []byte(text), 0o644); err != nil {
+		return fmt.Errorf("could not export the puzzle: %w", err)
+	}
+	sm.Message = fmt.Sprintf("Puzzle   %s\nExported with the solution to %s.", sm.Puzzle.Givens, file)
+	return nil
+}
+
+// solved stops the clock and keeps sudoku-<grade>-solved and the best
+// time, in milliseconds, as sudoku-<grade>-best in profile.yaml.
+func (sm *SudokuModel) solved() error {
+	sm.Loop.Stop()
+	sm.Status = "solved"
+	sm.Elapsed = time.Since(sm.Started)
+	sm.Hint = nil
+	d := sm.Puzzle.Difficulty
+	return updateProfile(sm.User, func(v *viper.Viper) {
+		v.Set(sudokuKey(d, "solved"), v.GetInt(sudokuKey(d, "solved"))+1)
+		ms := sm.Elapsed.Milliseconds()
+		if best := v.GetInt64(sudokuKey(d, "best")); best == 0 || ms < best {
+			v.Set(sudokuKey(d, "best"), ms)
+			sm.Best = true
+		}
+	})
+}
+
+func (m model) SudokuView() string {
+	sm := m.sudoku
+	switch sm.Status {
+	case "":
+		return ""
+	case "menu":
+		return viewOptions(sm.Menu, m.terminal)
+	case "import":
+		return viewSudokuImport(m)
+	case "generating":
+		return fullCenterBox(turnStatusStyle, "Generating a puzzle with a single solution...", m.terminal)
+	}
+
+	elapsed := sm.Elapsed
+	if sm.Status == "playing" {
+		elapsed = time.Since(sm.Started)
+	}
+	mode := "digits"
+	if sm.Pencil {
+		mode = "pencil"
+	}
+	header := turnStatusStyle.Render(fmt.Sprintf("Time %s   Empty %d   Hints %d   ", sudokuClock(elapsed), sm.Grid.Empty(), sm.Hints)) +
+		turnMutedStyle.Render(fmt.Sprintf("%s · %s", sm.Puzzle.Difficulty, mode))
+	board := drawSudoku(sm, m.terminal.Height >= sudokuTallHeight)
+
+	var lines []string
+	switch {
+	case sm.Status == "solved":
+		line := "Solved in " + sudokuClock(sm.Elapsed) + "!"
+		if sm.Best {
+			line += " New best time."
+		}
+		lines = append(lines, turnStatusStyle.Bold(true).Render(line))
+	case len(sm.Hint) > 0:
+		for i, st := range sm.Hint {
+			style := turnMutedStyle
+			if i == len(sm.Hint)-1 {
+				style = turnStatusStyle
+			}
+			lines = append(lines, style.Render(st.Technique+": "+st.Text))
+		}
+	}
+	if sm.Message != "" {
+		lines = append(lines, turnStatusStyle.Render(sm.Message))
+	}
+	if m.terminal.Height < sudokuTallHeight && sm.Grid[sm.Cursor] == 0 && sm.Marks[sm.Cursor] != 0 {
+		lines = append(lines, turnMutedStyle.Render(fmt.Sprintf("%s marks: %s", sudoku.CellName(sm.Cursor), sm.Marks[sm.Cursor])))
+	}
+	if sm.Status == "solved" {
+		lines = append(lines, turnMutedStyle.Render("e export · n new puzzle · esc menu · q quit"))
+	} else {
+		lines = append(lines,
+			turnMutedStyle.Render("arrows move · 1-9 fill · 0 clear · m pencil "+onOff(sm.Pencil)+" · M pencil all"),
+			turnMutedStyle.Render("? hint · e export · n new puzzle · esc menu · q quit"))
+	}
+
+	title := horizontalCenterBox(turnTitleStyle, sudokuTitle, m.terminal)
+	content := lipgloss.JoinVertical(lipgloss.Left, header, "", board, "", strings.Join(lines, "\n"))
+	margin := max((m.terminal.Width-lipgloss.Width(board))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+func viewSudokuImport(m model) string {
+	sm := m.sudoku
+	lines := []string{
+		turnStatusStyle.Render("Paste or type a puzzle, row by row."),
+		"",
+		sm.Input.View(),
+		"",
+	}
+	if sm.Message != "" {
+		lines = append(lines, turnErrorStyle.Render(sm.Message))
+	}
+	lines = append(lines, turnMutedStyle.Render("enter play · esc back"))
+	return fullCenterBox(lipgloss.NewStyle(), strings.Join(lines, "\n"), m.terminal)
+}
+
+// drawSudoku draws the grid with its boxes. Tall cells are three lines
+// high and show every pencil mark; short ones only show that marks exist.
+func drawSudoku(sm SudokuModel, tall bool) string {
+	width, height := 3, 1
+	if tall {
+		width, height = 7, 3
+	}
+	conflicts := sm.Grid.Conflicts()
+	var hinted [sudoku.Cells]bool
+	if len(sm.Hint) > 0 {
+		if c := sm.Hint[len(sm.Hint)-1].Cell; c >= 0 {
+			hinted[c] = true
+		}
+	}
+
+	rule := func(left, mid, right string) string {
+		seg := strings.Repeat("─", 3*width)
+		return sudokuGridStyle.Render(left + seg + mid + seg + mid + seg + right)
+	}
+	bar := sudokuGridStyle.Render("│")
+
+	rows := []string{rule("┌", "┬", "┐")}
+	for r := 0; r < sudoku.Size; r++ {
+		if r > 0 && r%3 == 0 {
+			rows = append(rows, rule("├", "┼", "┤"))
+		}
+		cells := make([][]string, sudoku.Size)
+		for col := 0; col < sudoku.Size; col++ {
+			c := r*sudoku.Size + col
+			cells[col] = sudokuCell(sm, c, width, height, conflicts[c], hinted[c])
+		}
+		for line := 0; line < height; line++ {
+			var b strings.Builder
+			for col := 0; col < sudoku.Size; col++ {
+				if col%3 == 0 {
+					b.WriteString(bar)
+				}
+				b.WriteString(cells[col][line])
+			}
+			b.WriteString(bar)
+			rows = append(rows, b.String())
+		}
+	}
+	rows = append(rows, rule("└", "┴", "┘"))
+	return strings.Join(rows, "\n")
+}
+
+// sudokuCell renders the lines of one cell.
+func sudokuCell(sm SudokuModel, c, width, height int, conflict, hinted bool) []string {
+	v := sm.Grid[c]
+	style := lipgloss.NewStyle()
+	switch {
+	case conflict:
+		style = sudokuConflictStyle
+	case sm.Puzzle.Givens[c] != 0:
+		style = sudokuGivenStyle
+	case v != 0:
+		style = sudokuPlayerStyle
+	}
+	var bg lipgloss.TerminalColor
+	switch {
+	case c == sm.Cursor:
+		bg = sudokuCursorBg
+	case hinted:
+		bg = sudokuHintBg
+	case v != 0 && v == sm.Grid[sm.Cursor]:
+		bg = sudokuSameBg
+	}
+	if bg != nil {
+		style = style.Background(bg)
+	}
+	marks := sudokuMarkStyle
+	if bg != nil {
+		marks = marks.Background(bg)
+	}
+
+	out := make([]string, height)
+	blank := strings.Repeat(" ", width)
+	switch {
+	case v != 0:
+		for i := range out {
+			out[i] = style.Render(blank)
+		}
+		pad := (width - 1) / 2
+		out[height/2] = style.Render(strings.Repeat(" ", pad) + fmt.Sprint(v) + strings.Repeat(" ", width-1-pad))
+	case height == 1:
+		glyph := "·"
+		if sm.Marks[c] != 0 {
+			glyph = "∘"
+		}
+		out[0] = marks.Render(" " + glyph + " ")
+	default:
+		for line := 0; line < height; line++ {
+			text := " "
+			for k := 1; k <= 3; k++ {
+				n := line*3 + k
+				if sm.Marks[c].Has(n) {
+					text += fmt.Sprint(n)
+				} else {
+					text += " "
+				}
+				text += " "
+			}
+			out[line] = marks.Render(text)
+		}
+	}
+	return out
+}
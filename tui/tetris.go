@@ -0,0 +1,411 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/tetris"
+	"gamics/leaderboard"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	tetrisGame  = "tetris" // leaderboards live in .gamics/leaderboards/tetris
+	tetrisFrame = 16 * time.Millisecond
+	// tetrisMaxStep caps how much game time one tick may run, so a stalled
+	// terminal doesn't drop pieces several rows at once.
+	tetrisMaxStep = 100 * time.Millisecond
+	tetrisShown   = 5 // leaderboard entries on the game over screen
+)
+
+var (
+	tetrisColors = map[tetris.Kind]lipgloss.Color{
+		tetris.I: lipgloss.Color("#00C8D7"),
+		tetris.O: lipgloss.Color("#E8D33F"),
+		tetris.T: lipgloss.Color("#A24BD8"),
+		tetris.S: lipgloss.Color("#4CC34C"),
+		tetris.Z: lipgloss.Color("#E0453A"),
+		tetris.J: lipgloss.Color("#3B6FE0"),
+		tetris.L: lipgloss.Color("#EF8E2E"),
+	}
+	tetrisWellStyle  = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("#555555"))
+	tetrisPanelStyle = lipgloss.NewStyle().Width(17).Padding(0, 1)
+
+	tetrisActions = map[string]tetris.Action{
+		"left": tetris.MoveLeft, "h": tetris.MoveLeft, "a": tetris.MoveLeft,
+		"right": tetris.MoveRight, "l": tetris.MoveRight, "d": tetris.MoveRight,
+		"up": tetris.RotateCW, "x": tetris.RotateCW, "w": tetris.RotateCW, "k": tetris.RotateCW,
+		"z": tetris.RotateCCW, "ctrl+z": tetris.RotateCCW,
+		"down": tetris.SoftDrop, "j": tetris.SoftDrop, "s": tetris.SoftDrop,
+		" ": tetris.HardDrop,
+		"c": tetris.Hold, "shift+left": tetris.Hold,
+	}
+)
+
+func init() {
+	initTableGames[TETRIS_UI] = func() tea.Cmd { return func() tea.Msg { return tetrisStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type tetrisStartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// TetrisModel is a game of Tetris. Each mode keeps its own leaderboard,
+// shared by every player of the machine.
+type TetrisModel struct {
+	State  tetris.State
+	Status string // "menu", "playing", "paused", "over"
+	Menu   Options
+	Loop   gameLoop
+	Tick   time.Time // wall clock of the last tick, to run game time
+	Board  leaderboard.Board
+	Rank   int // place of the finished game on Board, 0 if off it
+	User   string
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+func NewTetrisModel(user string) (TetrisModel, error) {
+	tm := TetrisModel{Status: "menu", User: user, Loop: gameLoop{Name: TETRIS_UI}}
+	var err error
+	tm.Menu, err = tetrisMenu(user)
+	return tm, err
+}
+
+// tetrisMenu lists the modes with the player's best on each.
+func tetrisMenu(user string) (Options, error) {
+	var items []Option
+	for _, mode := range tetris.Modes {
+		text := tetrisModeTitle(mode)
+		b, err := tetrisLoadBoard(mode)
+		if err != nil {
+			return Options{}, err
+		}
+		if e, ok := b.Best(user); ok {
+			text += "  " + turnMutedStyle.Render("best "+tetrisResult(mode, e))
+		}
+		items = append(items, Option{Text: text, Action: func(m model) model {
+			m.tetris.start(mode)
+			return m
+		}})
+	}
+	return Options{Prompt: "Tetris. Pick a mode.", Items: items}, nil
+}
+
+func tetrisModeTitle(mode string) string {
+	switch mode {
+	case tetris.Sprint:
+		return fmt.Sprintf("Sprint · clear %d lines fast", tetris.SprintLines)
+	case tetris.Ultra:
+		return fmt.Sprintf("Ultra · score in %d minutes", int(tetris.UltraTime.Minutes()))
+	default:
+		return fmt.Sprintf("Marathon · %d lines", tetris.MarathonLines)
+	}
+}
+
+func tetrisOrder(mode string) leaderboard.Order {
+	if mode == tetris.Sprint {
+		return leaderboard.ByTime
+	}
+	return leaderboard.ByScore
+}
+
+func tetrisLoadBoard(mode string) (leaderboard.Board, error) {
+	return leaderboard.Load(gamicsRoot, tetrisGame, mode, tetrisOrder(mode))
+}
+
+// tetrisResult is what a mode's leaderboard ranks by.
+func tetrisResult(mode string, e leaderboard.Entry) string {
+	if mode == tetris.Sprint {
+		return tetrisClock(e.Time)
+	}
+	return fmt.Sprint(e.Score)
+}
+
+func tetrisClock(d time.Duration) string {
+	return fmt.Sprintf("%d:%05.2f", int(d.Minutes()), d.Seconds()-60*d.Truncate(time.Minute).Minutes())
+}
+
+// start deals a new game of mode.
+func (tm *TetrisModel) start(mode string) {
+	tm.State = tetris.New(mode, uint64(time.Now().UnixNano()))
+	tm.Status = "playing"
+	tm.Board, tm.Rank = leaderboard.Board{}, 0
+	tm.Loop.Start()
+	tm.Tick = time.Now()
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) TetrisUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	tm := &m.tetris
+
+	switch msg := msg.(type) {
+	case tetrisStartMsg:
+		m.tetris, m.err = NewTetrisModel(m.user())
+		return m, nil
+
+	case loopMsg:
+		if !tm.Loop.Owns(msg) || msg.Timer != timerMove || tm.Status != "playing" {
+			return m, nil
+		}
+		now := time.Now()
+		tm.State = tm.State.Advance(min(now.Sub(tm.Tick), tetrisMaxStep))
+		tm.Tick = now
+		if tm.State.Over {
+			m.err = tm.finish()
+			return m, nil
+		}
+		return m, tm.Loop.After(timerMove, tetrisFrame)
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			tm.Loop.Stop()
+			if tm.Status == "menu" {
+				m.currentUI = LIST_GAMES_UI
+				return m, nil
+			}
+			tm.Status = "menu"
+			tm.Menu, m.err = tetrisMenu(tm.User)
+			return m, nil
+		}
+
+		switch tm.Status {
+		case "menu":
+			started := tm.Status
+			updateOptions(&tm.Menu, key, &m)
+			if m.tetris.Status != started {
+				return m, m.tetris.Loop.After(timerMove, tetrisFrame)
+			}
+		case "playing":
+			if key == "p" {
+				tm.Loop.Stop()
+				tm.Status = "paused"
+				return m, nil
+			}
+			if a, ok := tetrisActions[key]; ok {
+				tm.State = tm.State.Apply(a)
+				if tm.State.Over {
+					m.err = tm.finish()
+				}
+			}
+		case "paused":
+			if key == "p" || key == "enter" || key == " " {
+				tm.Status = "playing"
+				tm.Loop.Start()
+				tm.Tick = time.Now()
+				return m, tm.Loop.After(timerMove, tetrisFrame)
+			}
+		case "over":
+			if key == "r" || key == "enter" {
+				tm.start(tm.State.Mode)
+				return m, tm.Loop.After(timerMove, tetrisFrame)
+			}
+		}
+	}
+	return m, nil
+}
+
+// finish stops the game and submits it to its mode's leaderboard. A sprint
+// only counts once its lines are cleared.
+func (tm *TetrisModel) finish() error {
+	tm.Loop.Stop()
+	tm.Status = "over"
+	st := tm.State
+	if st.Mode != tetris.Sprint || st.Done {
+		e := leaderboard.Entry{Player: tm.User, Score: st.Score, Time: st.Elapsed, Lines: st.Lines}
+		rank, err := leaderboard.Submit(gamicsRoot, tetrisGame, st.Mode, tetrisOrder(st.Mode), e)
+		if err != nil {
+			return err
+		}
+		tm.Rank = rank
+	}
+	var err error
+	tm.Board, err = tetrisLoadBoard(st.Mode)
+	return err
+}
+
+func (m model) TetrisView() string {
+	tm := m.tetris
+	switch tm.Status {
+	case "":
+		return ""
+	case "menu":
+		return viewOptions(tm.Menu, m.terminal)
+	}
+
+	st := tm.State
+	well := tetrisWellStyle.Render(drawTetrisWell(st))
+
+	hold := turnMutedStyle.Render("HOLD") + "\n\n" + drawTetromino(st.Held, st.HoldUsed)
+	left := tetrisPanelStyle.Render(hold + "\n\n\n" + tetrisStats(st))
+
+	next := []string{turnMutedStyle.Render("NEXT"), ""}
+	for _, k := range st.Next(tetris.QueueShown) {
+		next = append(next, drawTetromino(k, false), "")
+	}
+	right := tetrisPanelStyle.Render(strings.Join(next, "\n"))
+
+	var status string
+	switch {
+	case tm.Status == "paused":
+		status = turnStatusStyle.Render("Paused. Press p to go on.")
+	case tm.Status == "over" && st.Done:
+		status = turnStatusStyle.Bold(true).Render(tetrisOverLine(tm))
+	case tm.Status == "over":
+		status = turnErrorStyle.Bold(true).Render(tetrisOverLine(tm))
+	case st.Last.Name != "" && st.Locks > 0:
+		status = turnStatusStyle.Render(tetrisClearLine(st.Last))
+	}
+
+	help := "←/→ move · ↓ soft drop · space hard drop · ↑/x/z rotate · c hold · p pause · esc menu · q quit"
+	if tm.Status == "over" {
+		help = "r play again · esc menu · q quit"
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, left, well, right)
+	if tm.Status == "over" {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, "  ", tetrisBoardView(tm))
+	}
+	title := horizontalCenterBox(turnTitleStyle, "Tetris · "+tetrisModeTitle(st.Mode), m.terminal)
+	content := lipgloss.JoinVertical(lipgloss.Left, body, "", status, turnMutedStyle.Render(help))
+	margin := max((m.terminal.Width-lipgloss.Width(body))/2, 0)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// drawTetrisWell draws the visible rows, the ghost under the falling piece.
+func drawTetrisWell(st tetris.State) string {
+	var grid [tetris.Rows][tetris.Cols]string
+	for y := range st.Board {
+		for x, k := range st.Board[y] {
+			grid[y][x] = tetrisBlock(k)
+		}
+	}
+	if !st.Over {
+		for _, c := range st.Ghost().Cells() {
+			if st.Board[c.Y][c.X] == tetris.None {
+				grid[c.Y][c.X] = lipgloss.NewStyle().Foreground(tetrisColors[st.Piece.Kind]).Render("░░")
+			}
+		}
+		for _, c := range st.Piece.Cells() {
+			grid[c.Y][c.X] = tetrisBlock(st.Piece.Kind)
+		}
+	}
+
+	rows := make([]string, 0, tetris.Visible)
+	for y := tetris.Hidden; y < tetris.Rows; y++ {
+		rows = append(rows, strings.Join(grid[y][:], ""))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func tetrisBlock(k tetris.Kind) string {
+	if k == tetris.None {
+		return turnMutedStyle.Render(" .")
+	}
+	return lipgloss.NewStyle().Background(tetrisColors[k]).Render("  ")
+}
+
+// drawTetromino draws k at spawn in a 4×2 box for the hold slot and the
+// queue; a held piece that can't be swapped back is greyed out.
+func drawTetromino(k tetris.Kind, greyed bool) string {
+	var grid [2][4]string
+	for y := range grid {
+		for x := range grid[y] {
+			grid[y][x] = "  "
+		}
+	}
+	if k != tetris.None {
+		style := lipgloss.NewStyle().Background(tetrisColors[k])
+		if greyed {
+			style = style.Background(lipgloss.Color("#555555"))
+		}
+		for _, c := range tetris.Shape(k) {
+			// The I piece sits on the second row of its box.
+			y := c.Y
+			if k == tetris.I {
+				y--
+			}
+			grid[y][c.X] = style.Render("  ")
+		}
+	}
+	return strings.Join(grid[0][:], "") + "\n" + strings.Join(grid[1][:], "")
+}
+
+func tetrisStats(st tetris.State) string {
+	clock := "Time   " + tetrisClock(st.Elapsed)
+	if st.Mode == tetris.Ultra {
+		clock = "Left   " + tetrisClock(st.TimeLeft())
+	}
+	lines := fmt.Sprint(st.Lines)
+	switch st.Mode {
+	case tetris.Sprint:
+		lines = fmt.Sprintf("%d/%d", st.Lines, tetris.SprintLines)
+	case tetris.Marathon:
+		lines = fmt.Sprintf("%d/%d", st.Lines, tetris.MarathonLines)
+	}
+	return strings.Join([]string{
+		turnMutedStyle.Render("SCORE"), fmt.Sprint(st.Score), "",
+		"Lines  " + lines,
+		"Level  " + fmt.Sprint(st.Level),
+		clock,
+	}, "\n")
+}
+
+func tetrisClearLine(c tetris.Clear) string {
+	line := fmt.Sprintf("%s +%d", c.Name, c.Points)
+	if c.Combo > 0 {
+		line += fmt.Sprintf(" · combo %d", c.Combo)
+	}
+	return line
+}
+
+func tetrisOverLine(tm TetrisModel) string {
+	st := tm.State
+	var line string
+	switch {
+	case st.Mode == tetris.Sprint && st.Done:
+		line = fmt.Sprintf("%d lines in %s!", tetris.SprintLines, tetrisClock(st.Elapsed))
+	case st.Done:
+		line = fmt.Sprintf("%s! Final score %d.", strings.ToUpper(st.Reason[:1])+st.Reason[1:], st.Score)
+	default:
+		line = fmt.Sprintf("Game over, %s. Final score %d.", st.Reason, st.Score)
+	}
+	if tm.Rank > 0 {
+		line += fmt.Sprintf(" #%d on the leaderboard.", tm.Rank)
+	}
+	return line
+}
+
+// tetrisBoardView lists the top of the mode's leaderboard, the game just
+// played highlighted.
+func tetrisBoardView(tm TetrisModel) string {
+	lines := []string{turnMutedStyle.Render("LEADERBOARD · " + strings.ToUpper(tm.State.Mode)), ""}
+	if len(tm.Board.Entries) == 0 {
+		lines = append(lines, turnMutedStyle.Render("No results yet."))
+	}
+	for i, e := range tm.Board.Entries[:min(tetrisShown, len(tm.Board.Entries))] {
+		line := fmt.Sprintf("%2d. %-12s %8s", i+1, e.Player, tetrisResult(tm.State.Mode, e))
+		if i+1 == tm.Rank {
+			line = turnCursorStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
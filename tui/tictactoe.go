@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/tictactoe"
+	"gamics/games/turn"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The board is drawn with cells tttCellWidth wide, a row label of
+// tttLabelWidth on the left and a header line on top; Click relies on the
+// same numbers.
+const (
+	tttLabelWidth = 3
+	tttCellWidth  = 5
+)
+
+var (
+	tttMarkStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#0B2E6B", Dark: "#5C9DFF"}),
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#7A2E00", Dark: "#FF8A3D"}),
+	}
+	tttWinStyle = lipgloss.NewStyle().Bold(true).
+			Foreground(lipgloss.Color("#000")).
+			Background(lipgloss.AdaptiveColor{Light: "#2FC67D", Dark: "#9BE8C3"})
+)
+
+// tttGame plays Tic-Tac-Toe on the turn screens, by keyboard or mouse.
+type tttGame struct{}
+
+func (tttGame) Title() string                           { return "Tic-Tac-Toe" }
+func (tttGame) Help() string                            { return "arrows move · enter or click to mark" }
+func (tttGame) New() turn.Game                          { return tictactoe.New() }
+func (tttGame) NewAI(level string, seed uint64) turn.AI { return tictactoe.NewAI(level, seed) }
+func (tttGame) Seat(seat int) string                    { return tictactoe.Mark(seat) }
+
+func (tttGame) Board(tg turn.Game, cursor *turnCursor) string {
+	g := tg.(tictactoe.Game)
+	win := map[int]bool{}
+	if line, ok := g.WinLine(); ok {
+		for _, i := range line {
+			win[i] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", tttLabelWidth))
+	for c := 0; c < tictactoe.Size; c++ {
+		fmt.Fprintf(&b, "%-*s", tttCellWidth+1, fmt.Sprintf("  %c", 'a'+c))
+	}
+	b.WriteString("\n")
+
+	sep := strings.Repeat(" ", tttLabelWidth) + strings.Repeat(strings.Repeat("─", tttCellWidth)+"┼", tictactoe.Size-1) + strings.Repeat("─", tttCellWidth)
+	for r := 0; r < tictactoe.Size; r++ {
+		fmt.Fprintf(&b, "%-*d", tttLabelWidth, r+1)
+		for c := 0; c < tictactoe.Size; c++ {
+			i := r*tictactoe.Size + c
+			mark := " "
+			style := lipgloss.NewStyle()
+			if v := g.Cells[i]; v != 0 {
+				mark = tictactoe.Mark(v - 1)
+				style = tttMarkStyles[v-1]
+			}
+			switch {
+			case win[i]:
+				style = tttWinStyle
+			case cursor != nil && cursor.Row == r && cursor.Col == c:
+				style = style.Inherit(turnCursorStyle)
+			}
+			b.WriteString(style.Render("  " + mark + "  "))
+			if c < tictactoe.Size-1 {
+				b.WriteString("│")
+			}
+		}
+		if r < tictactoe.Size-1 {
+			b.WriteString("\n" + sep + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (tttGame) Key(tg turn.Game, c turnCursor, key string) (turnCursor, turn.Move) {
+	switch key {
+	case "up", "k":
+		c.Row = (c.Row + tictactoe.Size - 1) % tictactoe.Size
+	case "down", "j":
+		c.Row = (c.Row + 1) % tictactoe.Size
+	case "left", "h":
+		c.Col = (c.Col + tictactoe.Size - 1) % tictactoe.Size
+	case "right", "l":
+		c.Col = (c.Col + 1) % tictactoe.Size
+	case "enter", " ":
+		return c, tictactoe.CellMove(c.Row, c.Col)
+	}
+	return c, ""
+}
+
+func (tttGame) Click(tg turn.Game, c turnCursor, x, y int) (turnCursor, turn.Move) {
+	x -= tttLabelWidth
+	y -= 1 // header
+	if x < 0 || y < 0 || y%2 == 1 || x%(tttCellWidth+1) == tttCellWidth {
+		return c, "" // a label, a grid line or outside
+	}
+	row, col := y/2, x/(tttCellWidth+1)
+	if row >= tictactoe.Size || col >= tictactoe.Size {
+		return c, ""
+	}
+	c.Row, c.Col = row, col
+	return c, tictactoe.CellMove(row, col)
+}
@@ -1,27 +1,94 @@
 package tui
 
 import (
+	"fmt"
+	"gamics/spectate"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/colorprofile"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 const (
-	LIST_GAMES_UI = "listGames"
-	SNAKE_GAME_UI = "snakeGame"
+	LIST_GAMES_UI   = "listGames"
+	SNAKE_GAME_UI   = "snakeGame"
+	SNAKE_VS_UI     = "snakeVersus"
+	NET_PLAY_UI     = "netPlay"
+	WATCH_UI        = "watch"
+	NIM_UI          = "nim"
+	TIC_TAC_TOE_UI  = "ticTacToe"
+	CONNECT_FOUR_UI = "connectFour"
+	REVERSI_UI      = "reversi"
+	CHECKERS_UI     = "checkers"
+
+	MINESWEEPER_UI      = "minesweeper"
+	MINESWEEPER_TINY_UI = "minesweeperTiny"
+	GAME_2048_UI        = "game2048"
+	TETRIS_UI           = "tetris"
+	SUDOKU_UI           = "sudoku"
+	SOKOBAN_UI          = "sokoban"
+	WORDLE_UI           = "wordle"
+	HANGMAN_UI          = "hangman"
+	BATTLESHIP_UI       = "battleship"
 )
 
+var errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F33")).Bold(true)
+
 var initTableGames = map[string]func() tea.Cmd{
 	SNAKE_GAME_UI: tickStartSnakeGameCmd,
 }
 
 type tickStartSnakeGame struct{}
 
+// errMsg carries a save or load that failed in a command out to the model,
+// which shows it.
+type errMsg struct{ err error }
+
+// failCmd reports err to the model. It is nil when err is.
+func failCmd(err error) tea.Cmd {
+	if err == nil {
+		return nil
+	}
+	return func() tea.Msg { return errMsg{err} }
+}
+
 type model struct {
-	listGames listGamesModel
-	snakeGame SnakeModel
-	terminal  Terminal
-	currentUI string
+	listGames   listGamesModel
+	snakeGame   SnakeModel
+	snakeVersus VersusModel
+	netPlay     NetPlayModel
+	watch       WatchModel
+	turnGame    TurnModel
+	mines       MinesModel
+	g2048       Game2048Model
+	tetris      TetrisModel
+	sudoku      SudokuModel
+	sokoban     SokobanModel
+	wordle      WordleModel
+	hangman     HangmanModel
+	battleship  BattleshipModel
+	terminal    Terminal
+	currentUI   string
+
+	// player is who plays this model. It is empty when gamics runs locally,
+	// in which case the logged in user from .gamics/config.yaml plays.
+	player string
+
+	// renderer is the terminal the model is seen on: the process's own, or
+	// that of an SSH session.
+	renderer *lipgloss.Renderer
+
+	// spectators receives the solo snake game while it is on screen;
+	// spectateOff is set once the socket could not be opened.
+	spectators  *spectate.Broadcaster
+	spectateOff bool
+
+	// err is the last save or load that failed. View shows it above the
+	// screen until the next key.
+	err error
 }
 
 type Terminal struct {
@@ -34,13 +101,37 @@ func NewModel(currUi string) model {
 	snake := InitNewSnakeModel()
 
 	return model{
-		listGames: lgm,
-		snakeGame: snake,
-		currentUI: currUi,
+		listGames:   lgm,
+		snakeGame:   snake,
+		snakeVersus: InitNewSnakeVersusModel(),
+		currentUI:   currUi,
+		renderer:    lipgloss.DefaultRenderer(),
 	}
 }
 
+// NewPlayerModel is NewModel for a given player instead of the logged in
+// user, seen through r, as when serving several players from one process.
+func NewPlayerModel(currUi, player string, r *lipgloss.Renderer) model {
+	m := NewModel(currUi)
+	m.player = player
+	m.renderer = r
+	return m
+}
+
+func (m model) user() string {
+	if m.player != "" {
+		return m.player
+	}
+	return currentUserOrDie()
+}
+
 func (m model) Init() tea.Cmd {
+	if m.currentUI == NET_PLAY_UI {
+		return waitNetCmd(m.netPlay.Client)
+	}
+	if m.currentUI == WATCH_UI {
+		return waitFrameCmd(m.watch.Watcher)
+	}
 	return nil
 }
 
@@ -49,6 +140,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.terminal.Width = msg.Width
 		m.terminal.Height = msg.Height
+	case tea.KeyMsg:
+		m.err = nil
+	case errMsg:
+		m.err = msg.err
+		return m, nil
 	}
 
 	switch m.currentUI {
@@ -56,17 +152,96 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.ListGamesUpdate(msg)
 	case SNAKE_GAME_UI:
 		return m.SnakeGameUpdate(msg)
+	case SNAKE_VS_UI:
+		return m.SnakeVersusUpdate(msg)
+	case NET_PLAY_UI:
+		return m.NetPlayUpdate(msg)
+	case WATCH_UI:
+		return m.WatchUpdate(msg)
+	case MINESWEEPER_UI, MINESWEEPER_TINY_UI:
+		return m.MinesUpdate(msg)
+	case GAME_2048_UI:
+		return m.Game2048Update(msg)
+	case TETRIS_UI:
+		return m.TetrisUpdate(msg)
+	case SUDOKU_UI:
+		return m.SudokuUpdate(msg)
+	case SOKOBAN_UI:
+		return m.SokobanUpdate(msg)
+	case WORDLE_UI:
+		return m.WordleUpdate(msg)
+	case HANGMAN_UI:
+		return m.HangmanUpdate(msg)
+	case BATTLESHIP_UI:
+		return m.BattleshipUpdate(msg)
+	}
+	if _, ok := turnGames[m.currentUI]; ok {
+		return m.TurnUpdate(msg)
 	}
 
 	return nil, nil
 }
 
 func (m model) View() string {
+	view := m.view()
+	if m.err != nil {
+		view = errorStyle.Render("Error: "+m.err.Error()) + "\n" + view
+	}
+	return m.downsample(view)
+}
+
+// colorProfiles maps the color profiles of lipgloss to those of
+// colorprofile.
+var colorProfiles = map[termenv.Profile]colorprofile.Profile{
+	termenv.TrueColor: colorprofile.TrueColor,
+	termenv.ANSI256:   colorprofile.ANSI256,
+	termenv.ANSI:      colorprofile.ANSI,
+	termenv.Ascii:     colorprofile.Ascii,
+}
+
+// downsample brings the colors of view, drawn with styles shared by every
+// model, down to what the model's own terminal shows.
+func (m model) downsample(view string) string {
+	if m.renderer == lipgloss.DefaultRenderer() {
+		return view
+	}
+	var b strings.Builder
+	w := colorprofile.Writer{Forward: &b, Profile: colorProfiles[m.renderer.ColorProfile()]}
+	w.WriteString(view)
+	return b.String()
+}
+
+func (m model) view() string {
 	switch m.currentUI {
 	case LIST_GAMES_UI:
 		return m.ListGamesView()
 	case SNAKE_GAME_UI:
 		return m.SnakeGameView()
+	case SNAKE_VS_UI:
+		return m.SnakeVersusView()
+	case NET_PLAY_UI:
+		return m.NetPlayView()
+	case WATCH_UI:
+		return m.WatchView()
+	case MINESWEEPER_UI, MINESWEEPER_TINY_UI:
+		return m.MinesView()
+	case GAME_2048_UI:
+		return m.Game2048View()
+	case TETRIS_UI:
+		return m.TetrisView()
+	case SUDOKU_UI:
+		return m.SudokuView()
+	case SOKOBAN_UI:
+		return m.SokobanView()
+	case WORDLE_UI:
+		return m.WordleView()
+	case HANGMAN_UI:
+		return m.HangmanView()
+	case BATTLESHIP_UI:
+		return m.BattleshipView()
+	}
+	if _, ok := turnGames[m.currentUI]; ok {
+		return m.TurnView()
 	}
 
 	return ""
@@ -77,3 +252,54 @@ func tickStartSnakeGameCmd() tea.Cmd {
 		return tickStartSnakeGame{}
 	})
 }
+
+// optionsChrome is the lines a menu takes besides its items: the box
+// padding, the prompt, the blank line under it and a line of margin above
+// and below.
+const optionsChrome = 6
+
+// updateOptions moves through a menu and runs the chosen item.
+func updateOptions(opts *Options, key string, m *model) {
+	switch key {
+	case "up", "k":
+		if opts.Cursor > 0 {
+			opts.Cursor--
+		}
+	case "down", "j":
+		if opts.Cursor < len(opts.Items)-1 {
+			opts.Cursor++
+		}
+	case "enter", " ":
+		if opts.Cursor < len(opts.Items) { // a menu that failed to load is empty
+			*m = opts.Items[opts.Cursor].Action(*m)
+		}
+	}
+}
+
+// viewOptions draws a menu in the middle of the screen. A menu taller than
+// the terminal shows a window of items that follows the cursor.
+func viewOptions(opts Options, t Terminal) string {
+	first, last := 0, len(opts.Items)
+	if room := t.Height - optionsChrome - strings.Count(opts.Prompt, "\n"); t.Height > 0 && last > room {
+		room = max(room-2, 1) // the more-above and more-below lines
+		first = min(max(opts.Cursor-room/2, 0), last-room)
+		last = first + room
+	}
+
+	var tw strings.Builder
+	if first > 0 {
+		tw.WriteString(turnMutedStyle.Render(fmt.Sprintf("↑ %d more", first)) + "\n")
+	}
+	for i := first; i < last; i++ {
+		txt := snakeBoxOption
+		if i == opts.Cursor {
+			txt = txt.Foreground(lipgloss.AdaptiveColor{Light: "#0F0", Dark: "#060"}).Bold(true)
+		}
+		tw.WriteString(txt.Render(opts.Items[i].Text) + "\n")
+	}
+	if more := len(opts.Items) - last; more > 0 {
+		tw.WriteString(turnMutedStyle.Render(fmt.Sprintf("↓ %d more", more)) + "\n")
+	}
+	message := fmt.Sprintf("%s\n\n%s", opts.Prompt, tw.String())
+	return fullCenterBox(snakeBoxWarn, strings.TrimRight(message, "\n"), t)
+}
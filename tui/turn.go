@@ -0,0 +1,762 @@
+package tui
+
+import (
+	"fmt"
+	"gamics/games/checkers"
+	"gamics/games/turn"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	turnCPUDelay   = 400 * time.Millisecond // lets the player see the CPU's move land
+	turnNameWidth  = 10
+	turnAnimFrame  = 45 * time.Millisecond
+	turnSavesShown = 10 // the newest saved matches offered to continue
+)
+
+// turnGames are the catalog entries played on the shared turn-based
+// screens, by catalog ID.
+var turnGames = map[string]turnGame{
+	NIM_UI:          nimGame{},
+	TIC_TAC_TOE_UI:  tttGame{},
+	CONNECT_FOUR_UI: cfGame{},
+	REVERSI_UI:      rvGame{},
+	CHECKERS_UI:     ckGame{rules: checkers.American},
+}
+
+var (
+	turnTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFFDF5")).
+			Background(lipgloss.Color("#25A065")).
+			Padding(0, 2)
+
+	turnBoardStyle = lipgloss.NewStyle().
+			Padding(1, 2).
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.AdaptiveColor{Light: "#135334", Dark: "#2FC67D"})
+
+	turnHistoryStyle = lipgloss.NewStyle().
+				Padding(0, 1).
+				Width(turnNameWidth + 16).
+				BorderStyle(lipgloss.NormalBorder()).
+				BorderForeground(lipgloss.AdaptiveColor{Light: "#999", Dark: "#555"})
+
+	turnStatusStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#135334", Dark: "#9BE8C3"})
+	turnErrorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#F33"))
+	turnMutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#666", Dark: "#999"})
+	turnCursorStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+func init() {
+	for id := range turnGames {
+		initTableGames[id] = startTurnGameCmd
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type turnStartMsg struct{}
+
+// turnCPUMsg carries the move a CPU player chose. It rides on the game loop
+// so that a move computed before an undo or a menu exit is dropped.
+type turnCPUMsg struct {
+	loopMsg
+	Move turn.Move
+}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// turnGame plugs a turn game into the shared screens: the rules come from
+// the engine, the game only draws its board and maps keys to moves.
+type turnGame interface {
+	Title() string
+	// Help explains the keys of the board, one short line.
+	Help() string
+	New() turn.Game
+	NewAI(level string, seed uint64) turn.AI
+	// Seat names the pieces of a seat, e.g. "X"; it may be empty.
+	Seat(seat int) string
+	// Board draws g with the cursor of the human to move; cursor is nil
+	// while nobody types, e.g. on the result screen.
+	Board(g turn.Game, cursor *turnCursor) string
+	// Key moves the cursor. A key that completes a move returns it too.
+	Key(g turn.Game, cursor turnCursor, key string) (turnCursor, turn.Move)
+}
+
+// turnClicker is implemented by games whose board can be played with the
+// mouse.
+type turnClicker interface {
+	// Click maps a click at x, y, counted from the top left corner of the
+	// board drawing, to the cursor and possibly a move.
+	Click(g turn.Game, cursor turnCursor, x, y int) (turnCursor, turn.Move)
+}
+
+// turnAnimator is implemented by games that animate moves, such as discs
+// falling down a column. Input waits until the move has landed.
+type turnAnimator interface {
+	// AnimFrames is how many frames mv, which led to g, takes to land.
+	AnimFrames(g turn.Game, mv turn.Move) int
+	// BoardFrame draws g while mv is still frame frames away from landing.
+	BoardFrame(g turn.Game, mv turn.Move, frame int) string
+}
+
+// turnVariants is implemented by games played under several rule sets.
+// The setup screen asks for the rule set before the players.
+type turnVariants interface {
+	// Variants lists the game under each rule set, the default first.
+	Variants() []turnGame
+}
+
+// turnRecorder is implemented by games whose matches can be saved to a file
+// and continued later. Matches are saved in the player's directory, in a
+// folder named after the game.
+type turnRecorder interface {
+	// Ext is the extension of saved matches, e.g. ".pdn".
+	Ext() string
+	Export(match turn.Match) string
+	// Import reads a saved match: the game under the rule set it was
+	// played with, and its moves.
+	Import(text string) (turnGame, []turn.Move, error)
+}
+
+// turnCursor is where the human to move points on the board. Games that
+// build a move in several steps (pick a piece, then a square) keep the
+// earlier steps in Picks.
+type turnCursor struct {
+	Row, Col int
+	Picks    []turnPos
+}
+
+type turnPos struct{ Row, Col int }
+
+// TurnModel is a match of any turn game: who plays, the moves so far and
+// the wins of each seat over rematches.
+type TurnModel struct {
+	Game    turnGame
+	Match   turn.Match
+	Status  string // "setup", "playing", "over"
+	Setup   Options
+	Cursor  turnCursor
+	Wins    []int
+	Message string // why the last move was refused
+	Note    string // news that is not an error, such as where a match was saved
+	Seed    uint64
+	Loop    gameLoop
+	User    string // the logged in player
+	Anim    int    // frames left before the last move has landed
+	// Start are the moves of a saved match, replayed when it begins.
+	Start []turn.Move
+	// Recorded is set while the outcome on screen is counted in Wins and
+	// the player's record, which a take-back undoes. A saved match that had
+	// ended was counted when it was played, not here, so it can't be taken
+	// back.
+	Recorded bool
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+
+// NewTurnModel opens the setup screen of the turn game with catalog ID id.
+func NewTurnModel(id string, user string) (TurnModel, error) {
+	g := turnGames[id]
+	tm := TurnModel{Game: g, Status: "setup", Setup: turnSetupOptions(g, user), Loop: gameLoop{Name: id}, User: user}
+	if v, ok := g.(turnVariants); ok {
+		tm.Setup = turnVariantOptions(id, user, v.Variants())
+	}
+	line, err := turnRecordLine(id, user)
+	if err != nil {
+		return tm, err
+	}
+	tm.Setup.Prompt += "\n" + line
+	if r, ok := g.(turnRecorder); ok && len(turnSaves(id, user, r.Ext())) > 0 {
+		tm.Setup.Items = append(tm.Setup.Items, Option{Text: "Continue a saved game…", Action: func(m model) model {
+			m.turnGame.Setup = turnSavesOptions(id, user, r)
+			return m
+		}})
+	}
+	return tm, nil
+}
+
+// turnVariantOptions offers the rule sets of a game; picking one asks who
+// is playing.
+func turnVariantOptions(id, user string, variants []turnGame) Options {
+	items := make([]Option, len(variants))
+	for i, g := range variants {
+		items[i] = Option{Text: g.Title(), Action: func(m model) model {
+			m.turnGame.Game = g
+			m.turnGame.Setup = turnSetupOptions(g, user)
+			line, err := turnRecordLine(id, user)
+			if err != nil {
+				m.err = err
+				return m
+			}
+			m.turnGame.Setup.Prompt += "\n" + line
+			return m
+		}}
+	}
+	return Options{Prompt: "Which rules?", Items: items}
+}
+
+// turnSavesOptions offers the newest saved matches of the player; picking
+// one asks who is playing on.
+func turnSavesOptions(id, user string, r turnRecorder) Options {
+	var items []Option
+	for _, file := range turnSaves(id, user, r.Ext()) {
+		items = append(items, Option{Text: path.Base(file), Action: func(m model) model {
+			text, err := os.ReadFile(file)
+			if err != nil {
+				m.turnGame.Message = err.Error()
+				return m
+			}
+			g, moves, err := r.Import(string(text))
+			if err != nil {
+				m.turnGame.Message = fmt.Sprintf("%s: %v", path.Base(file), err)
+				return m
+			}
+			m.turnGame.Game, m.turnGame.Start, m.turnGame.Message = g, moves, ""
+			m.turnGame.Setup = turnSetupOptions(g, user)
+			m.turnGame.Setup.Prompt += fmt.Sprintf("\nContinuing %s after %d moves.", path.Base(file), len(moves))
+			return m
+		}})
+	}
+	return Options{Prompt: "Which game?", Items: items}
+}
+
+// turnSavesDir is where user's matches of the game with catalog ID id are
+// saved.
+func turnSavesDir(id, user string) (string, error) {
+	dir, err := userDir(user)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(dir, id), nil
+}
+
+// turnSaves lists the saved matches of user, newest first.
+func turnSaves(id, user, ext string) []string {
+	dir, err := turnSavesDir(id, user)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	type save struct {
+		file string
+		mod  time.Time
+	}
+	var saves []save
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || path.Ext(e.Name()) != ext {
+			continue
+		}
+		saves = append(saves, save{path.Join(dir, e.Name()), info.ModTime()})
+	}
+	slices.SortFunc(saves, func(a, b save) int { return b.mod.Compare(a.mod) })
+	var files []string
+	for _, s := range saves[:min(len(saves), turnSavesShown)] {
+		files = append(files, s.file)
+	}
+	return files
+}
+
+// turnSetupOptions offers hot seat and every CPU level, with the CPU moving
+// second or first.
+func turnSetupOptions(g turnGame, user string) Options {
+	seat := func(i int) string {
+		if s := g.Seat(i); s != "" {
+			return " as " + s
+		}
+		return ""
+	}
+
+	items := []Option{{Text: fmt.Sprintf("Hot seat: %s%s vs Player 2%s", user, seat(0), seat(1)), Action: func(m model) model {
+		return startTurnMatch(m, []turn.Player{{Name: user}, {Name: "Player 2"}})
+	}}}
+	for _, level := range []string{turn.AIEasy, turn.AIMedium, turn.AIHard} {
+		cpu := turn.Player{Name: "CPU (" + level + ")", AI: level}
+		items = append(items,
+			Option{Text: fmt.Sprintf("Versus CPU (%s), you move first", level), Action: func(m model) model {
+				return startTurnMatch(m, []turn.Player{{Name: user}, cpu})
+			}},
+			Option{Text: fmt.Sprintf("Versus CPU (%s), CPU moves first", level), Action: func(m model) model {
+				return startTurnMatch(m, []turn.Player{cpu, {Name: user}})
+			}},
+		)
+	}
+	return Options{Prompt: fmt.Sprintf("%s. Who is playing?", g.Title()), Items: items}
+}
+
+func startTurnMatch(m model, players []turn.Player) model {
+	tm := &m.turnGame
+	match, err := turn.NewMatch(tm.Game.New(), players)
+	if err != nil {
+		tm.Message = err.Error()
+		return m
+	}
+	for _, mv := range tm.Start {
+		if match, err = match.Play(mv); err != nil {
+			tm.Message = err.Error()
+			return m
+		}
+	}
+	tm.Match = match
+	tm.Wins = make([]int, len(players))
+	tm.Seed = uint64(time.Now().UnixNano())
+	tm.Status = "playing"
+	tm.Cursor = turnCursor{}
+	tm.Message = ""
+	tm.Recorded = false
+	if match.Outcome().Over {
+		tm.Status = "over" // the saved match had ended
+	}
+	return m
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) TurnUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	tm := &m.turnGame
+
+	switch msg := msg.(type) {
+	case turnStartMsg:
+		m.turnGame, m.err = NewTurnModel(m.currentUI, m.user())
+		return m, nil
+
+	case turnCPUMsg:
+		if !tm.Loop.Owns(msg.loopMsg) || tm.Status != "playing" {
+			return m, nil
+		}
+		cmd := tm.play(msg.Move)
+		return m, cmd
+
+	case loopMsg:
+		if !tm.Loop.Owns(msg) || msg.Timer != timerAnim {
+			return m, nil
+		}
+		tm.Anim--
+		if tm.Anim > 0 {
+			return m, tm.animate()
+		}
+		if tm.Status != "playing" {
+			return m, nil
+		}
+		cmd := tm.cpuMove()
+		return m, cmd
+
+	case tea.MouseMsg:
+		clicker, ok := tm.Game.(turnClicker)
+		if !ok || tm.Status != "playing" || tm.Match.ToMove().CPU() || tm.Anim > 0 ||
+			msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+			return m, nil
+		}
+		x, y := turnBoardOrigin(m)
+		var mv turn.Move
+		tm.Cursor, mv = clicker.Click(tm.Match.Game(), tm.Cursor, msg.X-x, msg.Y-y)
+		if mv == "" {
+			return m, nil
+		}
+		cmd := tm.play(mv)
+		return m, cmd
+
+	case tea.KeyMsg:
+		var cmd tea.Cmd
+		switch tm.Status {
+		case "setup":
+			return updateTurnSetup(m, msg)
+		case "playing":
+			cmd = tm.updatePlaying(msg.String(), &m)
+		case "over":
+			cmd = tm.updateOver(msg.String(), &m)
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+func updateTurnSetup(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return exitTurnGame(m)
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	updateOptions(&m.turnGame.Setup, msg.String(), &m)
+	if m.turnGame.Status == "setup" {
+		return m, nil // still choosing, or a submenu opened
+	}
+	return m, m.turnGame.startLoop()
+}
+
+func (tm *TurnModel) updatePlaying(key string, m *model) tea.Cmd {
+	switch key {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc":
+		var cmd tea.Cmd
+		*m, cmd = exitTurnGame(*m)
+		return cmd
+	case "u":
+		return tm.undo()
+	case "e":
+		if tm.save() {
+			return nil
+		}
+	}
+
+	if tm.Match.ToMove().CPU() || tm.Anim > 0 {
+		return nil // the CPU is thinking, or the last move still lands
+	}
+	var mv turn.Move
+	tm.Cursor, mv = tm.Game.Key(tm.Match.Game(), tm.Cursor, key)
+	if mv == "" {
+		return nil
+	}
+	return tm.play(mv)
+}
+
+func (tm *TurnModel) updateOver(key string, m *model) tea.Cmd {
+	switch key {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "esc":
+		var cmd tea.Cmd
+		*m, cmd = exitTurnGame(*m)
+		return cmd
+	case "u":
+		if !tm.Match.CanUndo() {
+			return nil
+		}
+		if !tm.Recorded {
+			tm.Message = "The saved game had already ended."
+			return nil
+		}
+		if o := tm.Match.Outcome(); o.Winner != turn.Draw {
+			tm.Wins[o.Winner]--
+		}
+		err := tm.record(-1)
+		tm.Status, tm.Recorded = "playing", false
+		return tea.Batch(failCmd(err), tm.undo())
+	case "e":
+		tm.save()
+	case "r", "enter":
+		tm.Match = tm.Match.Rematch()
+		tm.Cursor = turnCursor{}
+		tm.Message = ""
+		tm.Status = "playing"
+		return tm.startLoop()
+	}
+	return nil
+}
+
+// play makes a move for whoever is to move, then lets the CPU answer.
+func (tm *TurnModel) play(mv turn.Move) tea.Cmd {
+	next, err := tm.Match.Play(mv)
+	if err != nil {
+		tm.Message = err.Error()
+		return nil
+	}
+	tm.Match = next
+	tm.Message, tm.Note = "", ""
+	tm.Cursor.Picks = nil
+
+	// Whatever was in flight belongs to the previous position.
+	tm.Loop.Start()
+	tm.Anim = 0
+	if a, ok := tm.Game.(turnAnimator); ok {
+		tm.Anim = a.AnimFrames(next.Game(), mv)
+	}
+
+	if o := next.Outcome(); o.Over {
+		tm.Status, tm.Recorded = "over", true
+		if o.Winner != turn.Draw {
+			tm.Wins[o.Winner]++
+		}
+		return tea.Batch(failCmd(tm.record(1)), tm.animate())
+	}
+	if tm.Anim > 0 {
+		return tm.animate() // the CPU answers once the move has landed
+	}
+	return tm.cpuMove()
+}
+
+// animate schedules the next frame of the last move, if it is still moving.
+func (tm TurnModel) animate() tea.Cmd {
+	if tm.Anim <= 0 {
+		return nil
+	}
+	return tm.Loop.After(timerAnim, turnAnimFrame)
+}
+
+func (tm *TurnModel) undo() tea.Cmd {
+	if !tm.Match.CanUndo() {
+		tm.Message = "Nothing to undo."
+		return nil
+	}
+	tm.Match = tm.Match.Undo()
+	tm.Cursor.Picks = nil
+	tm.Message, tm.Note = "", ""
+	return tm.startLoop()
+}
+
+// startLoop drops any CPU move in flight and asks for a new one if a CPU
+// is to move.
+func (tm *TurnModel) startLoop() tea.Cmd {
+	tm.Loop.Start()
+	tm.Anim = 0
+	if tm.Status != "playing" {
+		return nil
+	}
+	return tm.cpuMove()
+}
+
+// cpuMove asks the AI of the seat to move, if it is a CPU, off the UI
+// goroutine. The AI is rebuilt for every move from the match seed, so a
+// move computed for a stale position shares nothing with the next one.
+func (tm TurnModel) cpuMove() tea.Cmd {
+	p := tm.Match.ToMove()
+	if !p.CPU() {
+		return nil
+	}
+	ai := tm.Game.NewAI(p.AI, tm.Seed+uint64(len(tm.Match.History)))
+	g := tm.Match.Game()
+	name, gen := tm.Loop.Name, tm.Loop.Gen
+	return tea.Tick(turnCPUDelay, func(time.Time) tea.Msg {
+		return turnCPUMsg{loopMsg: loopMsg{Loop: name, Gen: gen, Timer: timerCPU}, Move: ai.Choose(g)}
+	})
+}
+
+// save writes the match to a new file in the player's directory, if the
+// game can be saved, and says where.
+func (tm *TurnModel) save() bool {
+	r, ok := tm.Game.(turnRecorder)
+	if !ok {
+		return false
+	}
+	dir, err := turnSavesDir(tm.Loop.Name, tm.User)
+	file := path.Join(dir, time.Now().Format("20060102-150405")+r.Ext())
+	if err == nil {
+		err = os.MkdirAll(dir, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(file, []byte(r.Export(tm.Match)), 0o644)
+	}
+	if err != nil {
+		tm.Message = fmt.Sprintf("could not save the game: %v", err)
+		return true
+	}
+	tm.Message, tm.Note = "", "Saved to "+file+"."
+	return true
+}
+
+// exitTurnGame stops the loop and goes back to the game list, which has no
+// use for the mouse.
+func exitTurnGame(m model) (model, tea.Cmd) {
+	m.turnGame.Loop.Stop()
+	m.currentUI = LIST_GAMES_UI
+	return m, tea.DisableMouse
+}
+
+// ----------------------------------------------------------------------------------
+// Record
+// ----------------------------------------------------------------------------------
+
+// record adds delta to the wins, losses or draws in this game of every human
+// seat, kept in each player's profile.yaml as <game>-wins, <game>-losses and
+// <game>-draws. A result taken back is recorded with delta -1. Only seats
+// played by a registered player are recorded: the guest of a hot seat match,
+// such as "Player 2", has no profile to keep it in.
+func (tm TurnModel) record(delta int) error {
+	winner := tm.Match.Outcome().Winner
+	for seat, player := range tm.Match.Players {
+		if player.CPU() {
+			continue
+		}
+		if player.Name != tm.User {
+			if _, err := userDir(player.Name); err != nil {
+				continue // a guest
+			}
+		}
+		key := "losses"
+		switch winner {
+		case turn.Draw:
+			key = "draws"
+		case seat:
+			key = "wins"
+		}
+		key = tm.Loop.Name + "-" + key
+		if err := updateProfile(player.Name, func(p *viper.Viper) {
+			p.Set(key, max(p.GetInt(key)+delta, 0))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func turnRecordLine(id, user string) (string, error) {
+	p, err := loadProfile(user)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Your record: %d won, %d lost, %d drawn.",
+		p.GetInt(id+"-wins"), p.GetInt(id+"-losses"), p.GetInt(id+"-draws")), nil
+}
+
+func (m model) TurnView() string {
+	tm := m.turnGame
+	if tm.Game == nil {
+		return ""
+	}
+	if tm.Status == "setup" {
+		return viewTurnSetup(m)
+	}
+
+	title := horizontalCenterBox(turnTitleStyle, tm.Game.Title(), m.terminal)
+	content, margin := turnLayout(m)
+	return fmt.Sprintf("%s\n\n%s", title, lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// turnLayout lays out the board, the history panel and the status lines,
+// and says how far right they are pushed to be centered.
+func turnLayout(m model) (content string, margin int) {
+	tm := m.turnGame
+	var cursor *turnCursor
+	if tm.Status == "playing" && !tm.Match.ToMove().CPU() {
+		cursor = &tm.Cursor
+	}
+	drawing := tm.Game.Board(tm.Match.Game(), cursor)
+	if a, ok := tm.Game.(turnAnimator); ok && tm.Anim > 0 {
+		last := tm.Match.History[len(tm.Match.History)-1]
+		drawing = a.BoardFrame(tm.Match.Game(), last.Move, tm.Anim)
+	}
+	board := turnBoardStyle.Render(drawing)
+	history := turnHistoryStyle.Height(lipgloss.Height(board) - 2).Render(tm.historyPanel(lipgloss.Height(board) - 2))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, board, " ", history)
+
+	lines := []string{tm.statusLine()}
+	if tm.Message != "" {
+		lines = append(lines, turnErrorStyle.Render(tm.Message))
+	}
+	if tm.Note != "" {
+		lines = append(lines, turnStatusStyle.Render(tm.Note))
+	}
+	lines = append(lines, turnMutedStyle.Render(tm.helpLine()))
+
+	content = lipgloss.JoinVertical(lipgloss.Left, body, "", strings.Join(lines, "\n"))
+	return content, max((m.terminal.Width-lipgloss.Width(content))/2, 0)
+}
+
+// turnBoardOrigin is where the board drawing starts on screen: below the
+// title and a blank line, inside the border and padding of the board box.
+func turnBoardOrigin(m model) (x, y int) {
+	_, margin := turnLayout(m)
+	return margin + turnBoardStyle.GetBorderLeftSize() + turnBoardStyle.GetPaddingLeft(),
+		2 + turnBoardStyle.GetBorderTopSize() + turnBoardStyle.GetPaddingTop()
+}
+
+func viewTurnSetup(m model) string {
+	opts := m.turnGame.Setup
+	var tw strings.Builder
+	for i, it := range opts.Items {
+		txt := snakeBoxOption
+		if i == opts.Cursor {
+			txt = txt.Foreground(lipgloss.AdaptiveColor{Light: "#0F0", Dark: "#060"}).Bold(true)
+		}
+		tw.WriteString(txt.Render(it.Text) + "\n")
+	}
+	message := fmt.Sprintf("%s\n\n%s\n%s", opts.Prompt, tw.String(), m.turnGame.Message)
+	return fullCenterBox(snakeBoxWarn, strings.TrimRight(message, "\n"), m.terminal)
+}
+
+// statusLine says whose turn it is, or how the game ended and the wins so
+// far.
+func (tm TurnModel) statusLine() string {
+	if tm.Status == "over" {
+		o := tm.Match.Outcome()
+		result := "Draw"
+		if o.Winner != turn.Draw {
+			result = tm.seatName(o.Winner) + " wins"
+		}
+		if o.Reason != "" {
+			result += ": " + o.Reason
+		}
+		tally := make([]string, len(tm.Wins))
+		for i, w := range tm.Wins {
+			tally[i] = fmt.Sprintf("%s %d", tm.Match.Players[i].Name, w)
+		}
+		return turnStatusStyle.Bold(true).Render(result+"!") + "\n" + turnStatusStyle.Render("Wins: "+strings.Join(tally, " · "))
+	}
+
+	seat := tm.Match.Game().ToMove()
+	line := fmt.Sprintf("%s to move", tm.seatName(seat))
+	if tm.Match.ToMove().CPU() {
+		line += " (thinking...)"
+	}
+	return turnStatusStyle.Render(line)
+}
+
+func (tm TurnModel) helpLine() string {
+	save := ""
+	if _, ok := tm.Game.(turnRecorder); ok {
+		save = " · e save"
+	}
+	if tm.Status == "over" {
+		back := ""
+		if tm.Recorded {
+			back = " · u take back"
+		}
+		return "r rematch" + back + save + " · esc menu · q quit"
+	}
+	return tm.Game.Help() + " · u undo" + save + " · esc menu · q quit"
+}
+
+func (tm TurnModel) seatName(seat int) string {
+	name := tm.Match.Players[seat].Name
+	if s := tm.Game.Seat(seat); s != "" {
+		name += " (" + s + ")"
+	}
+	return name
+}
+
+// historyPanel lists the last moves that fit in height lines.
+func (tm TurnModel) historyPanel(height int) string {
+	lines := []string{turnStatusStyle.Bold(true).Render("Moves")}
+	start := max(len(tm.Match.History)-(height-1), 0)
+	for i, r := range tm.Match.History[start:] {
+		name := []rune(tm.Match.Players[r.Seat].Name)
+		if len(name) > turnNameWidth {
+			name = append(name[:turnNameWidth-1], '…')
+		}
+		lines = append(lines, fmt.Sprintf("%3d. %-*s %s", start+i+1, turnNameWidth, string(name), r.Move))
+	}
+	if len(tm.Match.History) == 0 {
+		lines = append(lines, turnMutedStyle.Render("none yet"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// startTurnGameCmd opens the setup screen and turns the mouse on for games
+// that can be clicked.
+func startTurnGameCmd() tea.Cmd {
+	return tea.Batch(func() tea.Msg { return turnStartMsg{} }, tea.EnableMouseCellMotion)
+}
@@ -0,0 +1,601 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"gamics/daily"
+	"gamics/games/wordle"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+// ----------------------------------------------------------------------------------
+// Constants & globals
+// ----------------------------------------------------------------------------------
+const (
+	wordleGame          = "wordle"
+	wordleSession       = "wordle"       // practice game, saved in wordle.yaml
+	wordleDailySession  = "wordle-daily" // daily game, saved in wordle-daily.yaml
+	wordleHard          = "wordle-hard"
+	wordlePlayed        = "wordle-played"
+	wordleWins          = "wordle-wins"
+	wordleDist          = "wordle-dist" // wins by number of guesses
+	wordleStreak        = "wordle-streak"
+	wordleBestStreak    = "wordle-best-streak"
+	wordleDistBarWidth  = 24
+	wordleKeyboardWidth = 39 // ten keys of three cells and a gap
+)
+
+var (
+	wordleMarkStyles = map[wordle.Mark]lipgloss.Style{
+		wordle.Unknown: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#6C6C6C")),
+		wordle.Absent:  lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#3A3A3C")),
+		wordle.Present: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#B59F3B")),
+		wordle.Correct: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#538D4E")),
+	}
+	wordleTypedStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FFFFFF")).Background(lipgloss.Color("#262626"))
+	wordleEmptyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#585858")).Background(lipgloss.Color("#1C1C1C"))
+	wordleKeyGone     = lipgloss.NewStyle().Foreground(lipgloss.Color("#585858")).Background(lipgloss.Color("#1C1C1C"))
+	wordleKeyboardRow = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+)
+
+func init() {
+	initTableGames[WORDLE_UI] = func() tea.Cmd { return func() tea.Msg { return wordleStartMsg{} } }
+}
+
+// ----------------------------------------------------------------------------------
+// Messages (Bubble Tea)
+// ----------------------------------------------------------------------------------
+type wordleStartMsg struct{}
+
+// ----------------------------------------------------------------------------------
+// Data types
+// ----------------------------------------------------------------------------------
+
+// WordleModel is a game of Wordle, on the daily word or a practice one.
+// Games are saved after every guess, so both can be left and continued.
+type WordleModel struct {
+	Game    wordle.Game
+	Status  string // "menu", "playing", "over"
+	Menu    Options
+	Typed   string // letters of the guess being typed
+	Daily   string // day of the daily word; empty in practice
+	Started time.Time
+	Elapsed time.Duration // time spent in earlier sittings
+	Stats   WordleStats
+	Board   string // daily leaderboard, once the daily word is done
+	Share   string // result grid, when it could not go to the clipboard
+	Remote  bool   // played over the network, where the clipboard is the server's
+	User    string
+	Message string
+}
+
+// wordleSave is a game as saved between sittings.
+type wordleSave struct {
+	Game    wordle.Game
+	Daily   string
+	Elapsed time.Duration
+}
+
+// WordleStats are the player's results, kept in profile.yaml.
+type WordleStats struct {
+	Played     int
+	Wins       int
+	Dist       []int // Dist[i] counts the wins in i+1 guesses
+	Streak     int   // wins in a row
+	BestStreak int
+}
+
+// ----------------------------------------------------------------------------------
+// Constructors
+// ----------------------------------------------------------------------------------
+func NewWordleModel(user string, remote bool) (WordleModel, error) {
+	wm := WordleModel{Status: "menu", User: user, Remote: remote}
+	var err error
+	wm.Menu, err = wordleMenu(user)
+	return wm, err
+}
+
+// wordleMenu offers today's word, a practice word and the hard mode
+// switch, under the player's statistics.
+func wordleMenu(user string) (Options, error) {
+	p, err := loadProfile(user)
+	if err != nil {
+		return Options{}, err
+	}
+	day := daily.Today()
+	stats := loadWordleStats(p)
+
+	dailyText := fmt.Sprintf("Daily word (%s)", daily.Key(day))
+	sg, ok, err := loadWordle(user, wordleDailySession)
+	if err != nil {
+		return Options{}, err
+	}
+	if ok && sg.Daily == daily.Key(day) && !sg.Game.Over() {
+		dailyText += turnMutedStyle.Render(fmt.Sprintf("  %d/%d guesses", len(sg.Game.Guesses), wordle.MaxGuesses))
+	}
+	practiceText := "Practice"
+	sg, ok, err = loadWordle(user, wordleSession)
+	if err != nil {
+		return Options{}, err
+	}
+	if ok && !sg.Game.Over() {
+		practiceText += turnMutedStyle.Render(fmt.Sprintf("  %d/%d guesses", len(sg.Game.Guesses), wordle.MaxGuesses))
+	}
+
+	items := []Option{
+		{Text: dailyText, Action: func(m model) model {
+			m.err = m.wordle.playDaily(day)
+			return m
+		}},
+		{Text: practiceText, Action: func(m model) model {
+			m.err = m.wordle.playPractice()
+			return m
+		}},
+		{Text: "Hard mode: " + onOff(p.GetBool(wordleHard)), Action: func(m model) model {
+			err := updateProfile(m.wordle.User, func(p *viper.Viper) { p.Set(wordleHard, !p.GetBool(wordleHard)) })
+			if err != nil {
+				m.err = err
+				return m
+			}
+			cursor := m.wordle.Menu.Cursor
+			m.wordle.Menu, m.err = wordleMenu(m.wordle.User)
+			m.wordle.Menu.Cursor = cursor
+			return m
+		}},
+	}
+	prompt := "Wordle. Guess the five-letter word in six tries.\n" +
+		"Hard mode: revealed hints must be used in later guesses; it applies from the next new word."
+	if stats.Played > 0 {
+		prompt += "\n\n" + wordleStatsLines(stats, 0)
+	}
+	return Options{Prompt: prompt, Items: items}, nil
+}
+
+// playDaily continues today's word or starts it, using up the day's
+// attempt; a word already done shows the day's board instead.
+func (wm *WordleModel) playDaily(day time.Time) error {
+	sg, ok, err := loadWordle(wm.User, wordleDailySession)
+	if err != nil {
+		return err
+	}
+	if ok && sg.Daily == daily.Key(day) && !sg.Game.Over() {
+		wm.resume(sg)
+		return nil
+	}
+
+	err = daily.Begin(gamicsRoot, wordleGame, wm.User, day)
+	if errors.Is(err, daily.ErrAlreadyPlayed) {
+		board, err := daily.Load(gamicsRoot, wordleGame, day)
+		if err != nil {
+			return err
+		}
+		wm.Menu = Options{Prompt: fmt.Sprintf("You already played today's word, rank %d of %d.\nCome back tomorrow!\n\n%s",
+			board.Rank(wm.User), len(board.Entries), wordleBoardLines(board, wm.User)),
+			Items: []Option{{Text: "Back", Action: func(m model) model {
+				m.wordle.Menu, m.err = wordleMenu(m.wordle.User)
+				return m
+			}}}}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return wm.start(wordle.Answer(daily.Seed(wordleGame, day)), daily.Key(day))
+}
+
+// playPractice continues the practice word or deals a new one.
+func (wm *WordleModel) playPractice() error {
+	sg, ok, err := loadWordle(wm.User, wordleSession)
+	if err != nil {
+		return err
+	}
+	if ok && !sg.Game.Over() {
+		wm.resume(sg)
+		return nil
+	}
+	return wm.start(wordle.Answer(uint64(time.Now().UnixNano())), "")
+}
+
+func (wm *WordleModel) start(answer, day string) error {
+	p, err := loadProfile(wm.User)
+	if err != nil {
+		return err
+	}
+	wm.resume(wordleSave{Game: wordle.New(answer, p.GetBool(wordleHard)), Daily: day})
+	return wm.save()
+}
+
+// resume puts sg on screen, its clock going on from where it stopped.
+func (wm *WordleModel) resume(sg wordleSave) {
+	wm.Game, wm.Daily, wm.Elapsed = sg.Game, sg.Daily, sg.Elapsed
+	wm.Status, wm.Typed, wm.Started = "playing", "", time.Now()
+	wm.Board, wm.Share, wm.Message = "", "", ""
+	if sg.Game.Hard {
+		wm.Message = "Hard mode: revealed hints must be used."
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Session helpers
+// ----------------------------------------------------------------------------------
+func (wm *WordleModel) session() string {
+	if wm.Daily != "" {
+		return wordleDailySession
+	}
+	return wordleSession
+}
+
+func (wm *WordleModel) elapsed() time.Duration {
+	return wm.Elapsed + time.Since(wm.Started)
+}
+
+func (wm *WordleModel) save() error {
+	cfg, err := sessionCfg(wm.User, wm.session())
+	if err != nil {
+		return err
+	}
+	cfg.Set("game", wm.Game)
+	cfg.Set("daily", wm.Daily)
+	cfg.Set("elapsed-ms", wm.elapsed().Milliseconds())
+	if err := cfg.WriteConfig(); err != nil {
+		return fmt.Errorf("could not write wordle session file: %w", err)
+	}
+	return nil
+}
+
+// loadWordle reads a saved game. ok is false when there is none.
+func loadWordle(user, session string) (sg wordleSave, ok bool, err error) {
+	if !sessionExists(user, session) {
+		return sg, false, nil
+	}
+	cfg, err := sessionCfg(user, session)
+	if err != nil {
+		return sg, false, err
+	}
+	if err := cfg.ReadInConfig(); err != nil {
+		return sg, false, fmt.Errorf("could not read wordle session file: %w", err)
+	}
+	if err := cfg.UnmarshalKey("game", &sg.Game); err != nil {
+		return sg, false, fmt.Errorf("could not unmarshal wordle game: %w", err)
+	}
+	if len(sg.Game.Answer) != wordle.Length {
+		return sg, false, errors.New("wordle session file has no word to continue")
+	}
+	sg.Daily = cfg.GetString("daily")
+	sg.Elapsed = time.Duration(cfg.GetInt64("elapsed-ms")) * time.Millisecond
+	return sg, true, nil
+}
+
+// ----------------------------------------------------------------------------------
+// Results
+// ----------------------------------------------------------------------------------
+func loadWordleStats(p *viper.Viper) WordleStats {
+	s := WordleStats{
+		Played:     p.GetInt(wordlePlayed),
+		Wins:       p.GetInt(wordleWins),
+		Dist:       p.GetIntSlice(wordleDist),
+		Streak:     p.GetInt(wordleStreak),
+		BestStreak: p.GetInt(wordleBestStreak),
+	}
+	for len(s.Dist) < wordle.MaxGuesses {
+		s.Dist = append(s.Dist, 0)
+	}
+	return s
+}
+
+// finish records the ended game in the statistics and, for the daily word,
+// on the day's board. The daily score is the guesses left over, plus one
+// for the win, so fewer guesses rank first and the clock breaks ties.
+func (wm *WordleModel) finish() error {
+	g := wm.Game
+	wm.Status, wm.Typed = "over", ""
+	err := updateProfile(wm.User, func(p *viper.Viper) {
+		s := loadWordleStats(p)
+		s.Played++
+		s.Streak = 0
+		if g.Won() {
+			s.Wins++
+			s.Dist[len(g.Guesses)-1]++
+			s.Streak = p.GetInt(wordleStreak) + 1
+		}
+		s.BestStreak = max(s.BestStreak, s.Streak)
+		p.Set(wordlePlayed, s.Played)
+		p.Set(wordleWins, s.Wins)
+		p.Set(wordleDist, s.Dist)
+		p.Set(wordleStreak, s.Streak)
+		p.Set(wordleBestStreak, s.BestStreak)
+		wm.Stats = s
+	})
+	if err != nil {
+		return err
+	}
+	if err := endSession(wm.User, wm.session()); err != nil {
+		return err
+	}
+
+	if wm.Daily == "" {
+		return nil
+	}
+	day, err := daily.Parse(wm.Daily)
+	if err != nil {
+		return err
+	}
+	score := 0
+	if g.Won() {
+		score = wordle.MaxGuesses + 1 - len(g.Guesses)
+	}
+	if err := daily.Finish(gamicsRoot, wordleGame, wm.User, day, score, int(wm.elapsed().Seconds())); err != nil {
+		return err
+	}
+	board, err := daily.Load(gamicsRoot, wordleGame, day)
+	if err != nil {
+		return err
+	}
+	wm.Board = fmt.Sprintf("Daily %s: rank %d of %d.\n%s", wm.Daily, board.Rank(wm.User), len(board.Entries), wordleBoardLines(board, wm.User))
+	return nil
+}
+
+// share copies the result grid to the clipboard, or shows it where the
+// clipboard can't be reached.
+func (wm *WordleModel) share() {
+	title := "Gamics Wordle practice"
+	if wm.Daily != "" {
+		title = "Gamics Wordle " + wm.Daily
+	}
+	text := wm.Game.Share(title)
+	if !wm.Remote {
+		if err := clipboard.WriteAll(text); err == nil {
+			wm.Message = "Result copied to the clipboard."
+			return
+		}
+	}
+	wm.Share = text
+	wm.Message = "Copy your result:"
+}
+
+// wordleBoardLines renders the top of a daily board as guesses and time,
+// marking the player's entry.
+func wordleBoardLines(b daily.Board, player string) string {
+	lines := make([]string, 0, dailyBoardShown)
+	for i, e := range b.Entries {
+		if i == dailyBoardShown {
+			break
+		}
+		mark := "  "
+		if e.Player == player {
+			mark = "> "
+		}
+		result := "X"
+		if e.Score > 0 {
+			result = fmt.Sprint(wordle.MaxGuesses + 1 - e.Score)
+		}
+		status := fmt.Sprintf("%s/%d %s", result, wordle.MaxGuesses, sudokuClock(time.Duration(e.Ticks)*time.Second))
+		if !e.Finished {
+			status = "(playing)"
+		}
+		lines = append(lines, fmt.Sprintf("%s%d. %-12s %s", mark, i+1, e.Player, status))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wordleStatsLines renders the statistics and the guess distribution,
+// highlighting the bar of a win in last guesses.
+func wordleStatsLines(s WordleStats, last int) string {
+	lines := []string{fmt.Sprintf("Played %d · won %d%% · streak %d · best streak %d",
+		s.Played, s.Wins*100/max(s.Played, 1), s.Streak, s.BestStreak)}
+	most := 1
+	for _, n := range s.Dist {
+		most = max(most, n)
+	}
+	for i, n := range s.Dist {
+		style := wordleMarkStyles[wordle.Absent]
+		if i+1 == last {
+			style = wordleMarkStyles[wordle.Correct]
+		}
+		bar := style.Render(fmt.Sprintf("%*d ", 1+n*wordleDistBarWidth/most, n))
+		lines = append(lines, fmt.Sprintf("%d %s", i+1, bar))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ----------------------------------------------------------------------------------
+// Update / View (Bubble Tea)
+// ----------------------------------------------------------------------------------
+func (m model) WordleUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	wm := &m.wordle
+
+	switch msg := msg.(type) {
+	case wordleStartMsg:
+		m.wordle, m.err = NewWordleModel(m.user(), m.player != "")
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		switch key {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if wm.Status == "menu" {
+				m.currentUI = LIST_GAMES_UI
+				return m, nil
+			}
+			if wm.Status == "playing" {
+				if err := wm.save(); err != nil {
+					m.err = err
+					return m, nil
+				}
+			}
+			wm.Status = "menu"
+			wm.Menu, m.err = wordleMenu(wm.User)
+			return m, nil
+		}
+
+		switch wm.Status {
+		case "menu":
+			if key == "q" {
+				return m, tea.Quit
+			}
+			updateOptions(&wm.Menu, key, &m)
+		case "playing":
+			m.err = wm.updatePlaying(msg)
+		case "over":
+			switch key {
+			case "q":
+				return m, tea.Quit
+			case "s":
+				wm.share()
+			case "n", "enter":
+				if wm.Daily == "" {
+					m.err = wm.playPractice()
+					break
+				}
+				wm.Status = "menu"
+				wm.Menu, m.err = wordleMenu(wm.User)
+			}
+		}
+	}
+	return m, nil
+}
+
+// updatePlaying types letters into the guess and plays it on enter.
+func (wm *WordleModel) updatePlaying(msg tea.KeyMsg) error {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if wm.Typed != "" {
+			wm.Typed = wm.Typed[:len(wm.Typed)-1]
+		}
+		wm.Message = ""
+	case tea.KeyEnter:
+		next, err := wm.Game.Guess(wm.Typed)
+		if err != nil {
+			wm.Message = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "."
+			return nil
+		}
+		wm.Game, wm.Typed, wm.Message = next, "", ""
+		if wm.Game.Over() {
+			return wm.finish()
+		}
+		return wm.save()
+	case tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return nil
+		}
+		c := msg.Runes[0] | 0x20 // lower case
+		if c < 'a' || c > 'z' || len(wm.Typed) == wordle.Length {
+			return nil
+		}
+		wm.Typed += string(c)
+		wm.Message = ""
+	}
+	return nil
+}
+
+func (m model) WordleView() string {
+	wm := m.wordle
+	if wm.Status == "" {
+		return ""
+	}
+	if wm.Status == "menu" {
+		return viewOptions(wm.Menu, m.terminal)
+	}
+
+	title := "Wordle practice"
+	if wm.Daily != "" {
+		title = "Wordle " + wm.Daily
+	}
+	if wm.Game.Hard {
+		title += " · hard"
+	}
+
+	var lines []string
+	switch {
+	case wm.Status == "over" && wm.Game.Won():
+		lines = append(lines, turnStatusStyle.Bold(true).Render(fmt.Sprintf("Got it in %d/%d!", len(wm.Game.Guesses), wordle.MaxGuesses)))
+	case wm.Status == "over":
+		lines = append(lines, turnErrorStyle.Bold(true).Render("The word was "+strings.ToUpper(wm.Game.Answer)+"."))
+	default:
+		lines = append(lines, "")
+	}
+	if wm.Message != "" {
+		style := turnStatusStyle
+		if wm.Status == "playing" && !strings.HasPrefix(wm.Message, "Hard mode") {
+			style = turnErrorStyle
+		}
+		lines = append(lines, style.Render(wm.Message))
+	}
+	if wm.Share != "" {
+		lines = append(lines, "", wm.Share)
+	}
+
+	help := "type a word · enter guess · backspace erase · esc menu · ctrl+c quit"
+	if wm.Status == "over" {
+		help = "s share · n new word · esc menu · q quit"
+		if wm.Daily != "" {
+			help = "s share · n menu · esc menu · q quit"
+		}
+	}
+
+	parts := []string{drawWordleGrid(wm.Game, wm.Typed, wm.Status == "playing"), "", drawWordleKeyboard(wm.Game.Keyboard()), "", strings.Join(lines, "\n")}
+	if wm.Status == "over" {
+		last := 0
+		if wm.Game.Won() {
+			last = len(wm.Game.Guesses)
+		}
+		parts = append(parts, "", wordleStatsLines(wm.Stats, last))
+		if wm.Board != "" {
+			parts = append(parts, "", wm.Board)
+		}
+	}
+	parts = append(parts, "", turnMutedStyle.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	margin := max((m.terminal.Width-wordleKeyboardWidth)/2, 0)
+	return fmt.Sprintf("%s\n\n%s", horizontalCenterBox(turnTitleStyle, title, m.terminal),
+		lipgloss.NewStyle().MarginLeft(margin).Render(content))
+}
+
+// drawWordleGrid draws the six rows: the guesses marked, the word being
+// typed and the empty rows left, centred over the keyboard.
+func drawWordleGrid(g wordle.Game, typed string, playing bool) string {
+	rows := make([]string, 0, wordle.MaxGuesses)
+	for i := 0; i < wordle.MaxGuesses; i++ {
+		tiles := make([]string, wordle.Length)
+		for j := range tiles {
+			switch {
+			case i < len(g.Guesses):
+				tiles[j] = wordleMarkStyles[g.Marks(i)[j]].Render(" " + strings.ToUpper(g.Guesses[i][j:j+1]) + " ")
+			case i == len(g.Guesses) && playing && j < len(typed):
+				tiles[j] = wordleTypedStyle.Render(" " + strings.ToUpper(typed[j:j+1]) + " ")
+			default:
+				tiles[j] = wordleEmptyStyle.Render(" · ")
+			}
+		}
+		rows = append(rows, strings.Join(tiles, " "))
+	}
+	grid := strings.Join(rows, "\n\n")
+	return lipgloss.PlaceHorizontal(wordleKeyboardWidth, lipgloss.Center, grid)
+}
+
+// drawWordleKeyboard draws the keys coloured by the best mark each letter
+// earned; letters known to be absent fade out.
+func drawWordleKeyboard(marks map[byte]wordle.Mark) string {
+	rows := make([]string, 0, len(wordleKeyboardRow))
+	for _, row := range wordleKeyboardRow {
+		keys := make([]string, 0, len(row))
+		for i := 0; i < len(row); i++ {
+			style := wordleMarkStyles[marks[row[i]]]
+			if marks[row[i]] == wordle.Absent {
+				style = wordleKeyGone
+			}
+			keys = append(keys, style.Render(" "+strings.ToUpper(row[i:i+1])+" "))
+		}
+		rows = append(rows, lipgloss.PlaceHorizontal(wordleKeyboardWidth, lipgloss.Center, strings.Join(keys, " ")))
+	}
+	return strings.Join(rows, "\n")
+}